@@ -88,6 +88,14 @@ var (
 	periodicSyncIntervalInMin = flag.Duration("storagequota-sync-interval", 30*time.Minute,
 		"Periodic sync interval in Minutes")
 	enableProfileServer = flag.Bool("enable-profile-server", false, "Enable profiling endpoint for the syncer.")
+	logLevelServerAddr  = flag.String("log-level-server-address", "",
+		"Address of the unauthenticated endpoint which changes the log level at runtime, e.g. "+
+			"\"127.0.0.1:9503\". Disabled if empty.")
+	validateConfigPath = flag.String("validate-config", "",
+		"Validate the given vSphere config file, print a JSON report and exit")
+	validateConfigConnect = flag.Bool("validate-config-connect", false,
+		"Also connect to the vCenters to check the datacenters, topology categories and privileges "+
+			"when validating the config file")
 )
 
 // main for vsphere syncer.
@@ -97,10 +105,15 @@ func main() {
 		fmt.Printf("%s\n", syncer.Version)
 		return
 	}
+	if *validateConfigPath != "" {
+		ctx, _ := logger.GetNewContextWithLogger()
+		os.Exit(utils.ValidateConfig(ctx, *validateConfigPath, *validateConfigConnect, os.Stdout))
+	}
 	logType := logger.LogLevel(os.Getenv(logger.EnvLoggerLevel))
 	logger.SetLoggerLevel(logType)
 	ctx, log := logger.GetNewContextWithLogger()
 	log.Infof("Version : %s", syncer.Version)
+	logger.StartLogLevelServer(ctx, *logLevelServerAddr)
 
 	// Log GOMEMLIMIT if set for memory management visibility
 	if goMemLimit := os.Getenv("GOMEMLIMIT"); goMemLimit != "" {
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsvolumeoperationrequest"
+	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
+)
+
+// auditCommand is the name of the subcommand which queries the volume
+// operation audit log.
+const auditCommand = "audit"
+
+// runAuditCommand queries the volume operation audit log by volume ID or PVC
+// and prints the matching records as JSON lines. It returns the exit code.
+func runAuditCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet(auditCommand, flag.ContinueOnError)
+	logPath := fs.String("log-path", os.Getenv(cnsvolumeoperationrequest.EnvOperationAuditLogPath),
+		"Path of the volume operation audit log")
+	volumeID := fs.String("volume-id", "", "Volume ID to query the operation history for")
+	pvc := fs.String("pvc", "", "PVC to query the operation history for, in the form <namespace>/<name>")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [--volume-id <id> | --pvc <namespace>/<name>] [--log-path <path>]\n",
+			os.Args[0], auditCommand)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *logPath == "" {
+		fmt.Fprintf(os.Stderr, "audit log path is not set. Use --log-path or set %s\n",
+			cnsvolumeoperationrequest.EnvOperationAuditLogPath)
+		return 2
+	}
+	if (*volumeID == "") == (*pvc == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --volume-id or --pvc must be specified")
+		fs.Usage()
+		return 2
+	}
+
+	filter := cnsvolumeoperationrequest.AuditFilter{VolumeID: *volumeID}
+	if *pvc != "" {
+		var err error
+		filter, err = getAuditFilterForPVC(ctx, *pvc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to look up PVC %q: %v\n", *pvc, err)
+			return 1
+		}
+	}
+	records, err := cnsvolumeoperationrequest.QueryAuditRecords(*logPath, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to query audit log %q: %v\n", *logPath, err)
+		return 1
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	for i := range records {
+		if err := encoder.Encode(&records[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to print audit record: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// getAuditFilterForPVC resolves the PV bound to the given PVC and returns a
+// filter matching the operations on its volume. CreateVolume operations are
+// recorded against the PV name as the volume ID is not known before the
+// volume is created.
+func getAuditFilterForPVC(ctx context.Context, pvc string) (cnsvolumeoperationrequest.AuditFilter, error) {
+	var filter cnsvolumeoperationrequest.AuditFilter
+	namespace, name, found := strings.Cut(pvc, "/")
+	if !found || namespace == "" || name == "" {
+		return filter, fmt.Errorf("PVC must be specified in the form <namespace>/<name>")
+	}
+	k8sClient, err := k8s.NewClient(ctx)
+	if err != nil {
+		return filter, err
+	}
+	claim, err := k8sClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return filter, err
+	}
+	if claim.Spec.VolumeName == "" {
+		return filter, fmt.Errorf("PVC is not bound to a PV")
+	}
+	filter.Name = claim.Spec.VolumeName
+	pv, err := k8sClient.CoreV1().PersistentVolumes().Get(ctx, claim.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return filter, err
+	}
+	if pv.Spec.CSI != nil {
+		filter.VolumeID = pv.Spec.CSI.VolumeHandle
+	}
+	return filter, nil
+}
@@ -24,9 +24,14 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	csiconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/tracing"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/utils"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
@@ -44,19 +49,39 @@ var (
 	internalFSSName      = flag.String("fss-name", "", "Name of the feature state switch configmap")
 	internalFSSNamespace = flag.String("fss-namespace", "", "Namespace of the feature state switch configmap")
 	enableProfileServer  = flag.Bool("enable-profile-server", false, "Enable profiling endpoint for the controller.")
+	logLevelServerAddr   = flag.String("log-level-server-address", "",
+		"Address of the unauthenticated endpoint which changes the log level at runtime, e.g. "+
+			"\"127.0.0.1:9502\". Disabled if empty.")
+	nodeMetricsAddr = flag.String("node-metrics-address", "",
+		"Address of the endpoint which exposes the Prometheus metrics of the node plugin, e.g. \":2114\". "+
+			"Set to empty to disable.")
+	validateConfigPath = flag.String("validate-config", "",
+		"Validate the given vSphere config file, print a JSON report and exit")
+	validateConfigConnect = flag.Bool("validate-config-connect", false,
+		"Also connect to the vCenters to check the datacenters, topology categories and privileges "+
+			"when validating the config file")
 )
 
 // main is ignored when this package is built as a go plug-in.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == auditCommand {
+		ctx, _ := logger.GetNewContextWithLogger()
+		os.Exit(runAuditCommand(ctx, os.Args[2:]))
+	}
 	flag.Parse()
 	if *printVersion {
 		fmt.Printf("%s\n", service.Version)
 		return
 	}
+	if *validateConfigPath != "" {
+		ctx, _ := logger.GetNewContextWithLogger()
+		os.Exit(utils.ValidateConfig(ctx, *validateConfigPath, *validateConfigConnect, os.Stdout))
+	}
 	logType := logger.LogLevel(os.Getenv(logger.EnvLoggerLevel))
 	logger.SetLoggerLevel(logType)
 	ctx, log := logger.GetNewContextWithLogger()
 	log.Infof("Version : %s", service.Version)
+	logger.StartLogLevelServer(ctx, *logLevelServerAddr)
 
 	// Log GOMEMLIMIT if set for memory management visibility
 	if goMemLimit := os.Getenv("GOMEMLIMIT"); goMemLimit != "" {
@@ -81,6 +106,17 @@ func main() {
 		log.Errorf("failed retrieving the cluster flavor. Error: %v", err)
 	}
 	serviceMode := os.Getenv(csitypes.EnvVarMode)
+	if strings.EqualFold(serviceMode, "node") && *nodeMetricsAddr != "" {
+		go func() {
+			log.Infof("Starting the http server to expose Prometheus metrics on %s", *nodeMetricsAddr)
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", promhttp.Handler())
+			server := &http.Server{Addr: *nodeMetricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+			if err := server.ListenAndServe(); err != nil {
+				log.Errorf("Http server that exposes the Prometheus metrics exited. Error: %v", err)
+			}
+		}()
+	}
 	commonco.SetInitParams(ctx, clusterFlavor, &service.COInitParams, *supervisorFSSName, *supervisorFSSNamespace,
 		*internalFSSName, *internalFSSNamespace, serviceMode, "")
 
@@ -90,6 +126,12 @@ func main() {
 		log.Error("CSI endpoint cannot be empty. Please set the env variable.")
 		os.Exit(1)
 	}
+	shutdownTracing, err := tracing.InitTracing(ctx, "vsphere-csi-"+strings.ToLower(serviceMode))
+	if err != nil {
+		log.Errorf("failed to initialize tracing. Error: %v", err)
+		os.Exit(1)
+	}
+
 	log.Info("Enable logging off for vCenter sessions on exit")
 	// Disconnect VC session on restart
 	defer func() {
@@ -107,6 +149,9 @@ func main() {
 			if sig == syscall.SIGTERM {
 				log.Info("SIGTERM signal received")
 				utils.LogoutAllvCenterSessions(ctx)
+				if err := shutdownTracing(ctx); err != nil {
+					log.Errorf("failed to flush traces. Error: %v", err)
+				}
 				os.Exit(0)
 			}
 		}
@@ -91,6 +91,38 @@ cat <<eof >"${tmpdir}"/webhook.config
 port = "8443"
 cert-file = "/run/secrets/tls/tls.crt"
 key-file = "/run/secrets/tls/tls.key"
+
+[StorageClassValidation]
+# Deny the creation of StorageClasses with invalid parameters instead of admitting them with warnings.
+deny-invalid-parameters = false
+# Verify the storage policies and datastores of the StorageClasses exist in vCenter.
+# Requires the vsphere-config-secret in the namespace of the webhook.
+vcenter-lookup = false
+vcenter-lookup-cache-ttl-insec = 300
+
+[PVCExpansionValidation]
+# Deny the PVC expansions which cannot succeed instead of admitting them with warnings.
+deny-invalid-expansion = false
+# Verify the datastores of the volumes have enough free space in vCenter. The free space is only
+# enforced for thick provisioned volumes. Requires the vsphere-config-secret in the namespace of the webhook.
+# When enabled, each webhook replica lists all the datastores of each vCenter and checks the Datastore.FileManagement
+# and System.Read privileges of the vCenter user on them every csi-auth-check-intervalinmin of the vSphere config,
+# like the CSI controller does, and retrieves the FCD of each expanded volume once per cache TTL. The vCenter user
+# needs the same privileges as the CSI controller user.
+vcenter-lookup = false
+vcenter-lookup-cache-ttl-insec = 300
+
+[TopologyInjection]
+# Topology requirement injected into the vSphere CSI StorageClasses created without allowedTopologies:
+# "AllowedTopologies" or "WaitForFirstConsumer". Nothing is injected if empty.
+policy = ""
+# Topology category of the zones, as set in topology-categories of the vSphere config.
+topology-category = ""
+# Comma separated zones injected as allowedTopologies by the "AllowedTopologies" policy.
+allowed-zones = ""
+# Namespace label whose value is the default zone of the PVCs created in the namespace.
+# Requires the csi-provisioner of the vSphere CSI controller to run with --extra-create-metadata.
+namespace-zone-label = ""
 eof
 
 kubectl delete secret ${secret} --namespace "${namespace}" 2>/dev/null || true
@@ -105,10 +137,11 @@ kubectl create secret generic "${secret}" \
 
 
 CA_BUNDLE="$(openssl base64 -A <"${tmpdir}/ca.crt")"
-# clean-up previously created service and validatingwebhookconfiguration. Ignore errors if not present.
+# clean-up previously created service, validatingwebhookconfiguration and mutatingwebhookconfiguration. Ignore errors if not present.
 
 kubectl delete service vsphere-webhook-svc --namespace "${namespace}" 2>/dev/null || true
 kubectl delete validatingwebhookconfiguration.admissionregistration.k8s.io validation.csi.vsphere.vmware.com --namespace "${namespace}" 2>/dev/null || true
+kubectl delete mutatingwebhookconfiguration.admissionregistration.k8s.io mutation.csi.vsphere.vmware.com 2>/dev/null || true
 kubectl delete serviceaccount vsphere-csi-webhook --namespace "${namespace}" 2>/dev/null || true
 kubectl delete role.rbac.authorization.k8s.io vsphere-csi-webhook-role --namespace "${namespace}" 2>/dev/null || true
 kubectl delete rolebinding.rbac.authorization.k8s.io vsphere-csi-webhook-role-binding --namespace "${namespace}" 2>/dev/null || true
@@ -116,5 +149,5 @@ kubectl delete clusterrole.rbac.authorization.k8s.io vsphere-csi-webhook-cluster
 kubectl delete clusterrolebinding.rbac.authorization.k8s.io vsphere-csi-webhook-cluster-role-binding 2>/dev/null || true
 kubectl delete deployment vsphere-csi-webhook --namespace "${namespace}" 2>/dev/null || true
 
-# patch validatingwebhook.yaml with CA_BUNDLE and create service and validatingwebhookconfiguration
+# patch validatingwebhook.yaml with CA_BUNDLE and create service, validatingwebhookconfiguration and mutatingwebhookconfiguration
 sed "s/caBundle: .*$/caBundle: ${CA_BUNDLE}/g" <validatingwebhook.yaml | kubectl apply -f -
@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BulkRegisterVolumeItemPhase is the phase of the registration of a single
+// virtual disk discovered by a CnsBulkRegisterVolume.
+type BulkRegisterVolumeItemPhase string
+
+const (
+	// ItemPending indicates the virtual disk is waiting to be registered.
+	ItemPending BulkRegisterVolumeItemPhase = "Pending"
+	// ItemRegistering indicates a CnsRegisterVolume was created for the
+	// virtual disk and the PVC is being created.
+	ItemRegistering BulkRegisterVolumeItemPhase = "Registering"
+	// ItemRegistered indicates the virtual disk is bound to its PVC.
+	ItemRegistered BulkRegisterVolumeItemPhase = "Registered"
+	// ItemSkipped indicates the virtual disk is attached to a VM or is an FCD
+	// already used by a PV of the cluster. The reason is reported in the
+	// Message of the item.
+	ItemSkipped BulkRegisterVolumeItemPhase = "Skipped"
+	// ItemFailed indicates the virtual disk could not be registered. The
+	// reason is reported in the Error of the item.
+	ItemFailed BulkRegisterVolumeItemPhase = "Failed"
+)
+
+// CnsBulkRegisterVolumeSpec defines the desired state of CnsBulkRegisterVolume
+// +k8s:openapi-gen=true
+type CnsBulkRegisterVolumeSpec struct {
+	// DatastoreURL is the URL of the datastore to import the virtual disks
+	// from, e.g. ds:///vmfs/volumes/5f5b8b0e-2c4b6f3a-1234-0050568a1234/
+	DatastoreURL string `json:"datastoreURL"`
+
+	// FolderPrefix restricts the import to the virtual disks under the given
+	// folder of the datastore, e.g. "legacy-vms/app". All the virtual disks of
+	// the datastore are imported if not specified.
+	FolderPrefix string `json:"folderPrefix,omitempty"`
+
+	// TargetNamespace is the namespace to create the ReadWriteOnce PVCs in.
+	// It can be any namespace of the cluster, not only the namespace of the
+	// CnsBulkRegisterVolume, so creating CnsBulkRegisterVolume instances must
+	// be restricted to cluster administrators.
+	TargetNamespace string `json:"targetNamespace"`
+
+	// PVCNameTemplate is a Go template used to name the PVC of each virtual
+	// disk. The template is executed with .DiskName, the name of the virtual
+	// disk file without the ".vmdk" extension converted to a DNS-1123 label,
+	// and .Index, the position of the virtual disk in the status items.
+	// Defaults to "{{.DiskName}}".
+	PVCNameTemplate string `json:"pvcNameTemplate,omitempty"`
+
+	// VolumeMode of the PVCs. Defaults to "Filesystem".
+	VolumeMode v1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// MaxConcurrentRegistrations is the maximum number of virtual disks being
+	// registered at the same time. Defaults to 10.
+	MaxConcurrentRegistrations int `json:"maxConcurrentRegistrations,omitempty"`
+
+	// DryRun only discovers the virtual disks and reports the PVC names they
+	// would be registered with, without registering them.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// BulkRegisterVolumeItem is the status of the registration of a single
+// virtual disk.
+// +k8s:openapi-gen=true
+type BulkRegisterVolumeItem struct {
+	// DiskPath is the datastore path of the virtual disk,
+	// e.g. "[vsanDatastore] legacy-vms/app/app_1.vmdk".
+	DiskPath string `json:"diskPath"`
+
+	// PVCName is the name of the PVC of the virtual disk.
+	PVCName string `json:"pvcName,omitempty"`
+
+	// VolumeID is the ID of the FCD the virtual disk is registered as. It is
+	// set at discovery for the virtual disks already registered as FCDs.
+	VolumeID string `json:"volumeID,omitempty"`
+
+	// CapacityInMb is the capacity of the virtual disk.
+	CapacityInMb int64 `json:"capacityInMb,omitempty"`
+
+	// CnsRegisterVolumeName is the name of the CnsRegisterVolume created in
+	// the target namespace to create the PVC of the virtual disk.
+	CnsRegisterVolumeName string `json:"cnsRegisterVolumeName,omitempty"`
+
+	// Phase of the registration of the virtual disk.
+	Phase BulkRegisterVolumeItemPhase `json:"phase"`
+
+	// StartTime is the time the registration of the virtual disk started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Error encountered during the registration of the virtual disk, if any.
+	Error string `json:"error,omitempty"`
+
+	// Message explains why the virtual disk was skipped, if it was.
+	Message string `json:"message,omitempty"`
+}
+
+// CnsBulkRegisterVolumeStatus defines the observed state of CnsBulkRegisterVolume
+// +k8s:openapi-gen=true
+type CnsBulkRegisterVolumeStatus struct {
+	// Discovered indicates the virtual disks of the datastore were listed in
+	// Items.
+	Discovered bool `json:"discovered,omitempty"`
+
+	// Completed indicates all the items are either Registered, Skipped or
+	// Failed.
+	Completed bool `json:"completed,omitempty"`
+
+	// Total is the number of virtual disks discovered.
+	Total int `json:"total,omitempty"`
+
+	// Registered is the number of virtual disks registered.
+	Registered int `json:"registered,omitempty"`
+
+	// Skipped is the number of virtual disks skipped.
+	Skipped int `json:"skipped,omitempty"`
+
+	// Failed is the number of virtual disks which failed to register.
+	Failed int `json:"failed,omitempty"`
+
+	// Items is the status of each discovered virtual disk.
+	Items []BulkRegisterVolumeItem `json:"items,omitempty"`
+
+	// The last error encountered during the bulk import, if any.
+	// This field must only be set by the entity completing the import
+	// operation, i.e. the CNS Operator.
+	Error string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CnsBulkRegisterVolume is the Schema for the cnsbulkregistervolumes API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type CnsBulkRegisterVolume struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CnsBulkRegisterVolumeSpec   `json:"spec,omitempty"`
+	Status CnsBulkRegisterVolumeStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CnsBulkRegisterVolumeList contains a list of CnsBulkRegisterVolume
+type CnsBulkRegisterVolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CnsBulkRegisterVolume `json:"items"`
+}
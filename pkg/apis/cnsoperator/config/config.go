@@ -32,6 +32,11 @@ var EmbedCnsUnregisterVolumeCRFile embed.FS
 
 const EmbedCnsUnregisterVolumeCRFileName = "cnsunregistervolume_crd.yaml"
 
+//go:embed cnsbulkregistervolume_crd.yaml
+var EmbedCnsBulkRegisterVolumeCRFile embed.FS
+
+const EmbedCnsBulkRegisterVolumeCRFileName = "cnsbulkregistervolume_crd.yaml"
+
 //go:embed cns.vmware.com_storagepolicyquotas.yaml
 var EmbedStoragePolicyQuotaCRFile embed.FS
 
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clusterstoragepolicyinfov1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/clusterstoragepolicyinfo/v1alpha1"
+	cnsbulkregistervolumev1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsbulkregistervolume/v1alpha1"
 	cnsfileaccessconfigv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsfileaccessconfig/v1alpha1"
 	cnsnodevmattachmentv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsnodevmattachment/v1alpha1"
 	cnsnodevmbatchattachmentv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsnodevmbatchattachment/v1alpha1"
@@ -67,6 +68,8 @@ var (
 	CnsRegisterVolumePlural = "cnsregistervolumes"
 	// CnsUnregisterVolumePlural is plural of CnsUnregisterVolume
 	CnsUnregisterVolumePlural = "cnsunregistervolumes"
+	// CnsBulkRegisterVolumePlural is plural of CnsBulkRegisterVolume
+	CnsBulkRegisterVolumePlural = "cnsbulkregistervolumes"
 	// CnsFileAccessConfigPlural is plural of CnsFileAccessConfig
 	CnsFileAccessConfigPlural = "cnsfileaccessconfigs"
 	// CnsStoragePolicyUsageSingular is singular of StoragePolicyUsage
@@ -135,6 +138,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&cnsunregistervolumev1alpha1.CnsUnregisterVolumeList{},
 	)
 
+	scheme.AddKnownTypes(
+		SchemeGroupVersion,
+		&cnsbulkregistervolumev1alpha1.CnsBulkRegisterVolume{},
+		&cnsbulkregistervolumev1alpha1.CnsBulkRegisterVolumeList{},
+	)
+
 	scheme.AddKnownTypes(
 		SchemeGroupVersion,
 		&cnsvolumemetadatav1alpha1.CnsVolumeMetadata{},
@@ -20,6 +20,8 @@ import (
 	"sync"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 )
 
 const (
@@ -45,9 +47,11 @@ func (vl *VolumeLocks) TryAcquire(volumeID string) bool {
 	vl.mux.Lock()
 	defer vl.mux.Unlock()
 	if vl.locks.Has(volumeID) {
+		prometheus.VolumeLockContentionsCounter.Inc()
 		return false
 	}
 	vl.locks.Insert(volumeID)
+	prometheus.VolumeLocksHeldGauge.Set(float64(vl.locks.Len()))
 	return true
 }
 
@@ -56,4 +60,5 @@ func (vl *VolumeLocks) Release(volumeID string) {
 	vl.mux.Lock()
 	defer vl.mux.Unlock()
 	vl.locks.Delete(volumeID)
+	prometheus.VolumeLocksHeldGauge.Set(float64(vl.locks.Len()))
 }
@@ -13,8 +13,12 @@ import (
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/tracing"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 )
 
@@ -61,6 +65,9 @@ type TaskDetails struct {
 	Reference types.ManagedObjectReference
 	// MarkedForRemoval helps in retrying the removal of tasks in case of failures
 	MarkedForRemoval bool
+	// span traces the wait for the task to complete. It is ended when the
+	// result of the task is reported or the task is removed.
+	span trace.Span
 }
 
 type TaskResult struct {
@@ -73,6 +80,7 @@ var ErrSessionNotAuthenticated = errors.New("session is not authenticated")
 
 // NewListViewImpl creates a new listView object and starts a goroutine to listen to property collector task updates
 func NewListViewImpl(ctx context.Context, virtualCenter *cnsvsphere.VirtualCenter) (*ListViewImpl, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemListView)
 	log := logger.GetLogger(ctx)
 	t := &ListViewImpl{
 		taskMap:       NewTaskMap(),
@@ -196,6 +204,7 @@ func (l *ListViewImpl) IsListViewReady() bool {
 
 // AddTask adds task to listView and the internal map
 func (l *ListViewImpl) AddTask(ctx context.Context, taskMoRef types.ManagedObjectReference, ch chan TaskResult) error {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemListView)
 	log := logger.GetLogger(ctx)
 	log.Infof("AddTask called for %+v", taskMoRef)
 
@@ -209,23 +218,29 @@ func (l *ListViewImpl) AddTask(ctx context.Context, taskMoRef types.ManagedObjec
 		return fmt.Errorf("%w. task: %v, err: listview not ready", ErrListViewTaskAddition, taskMoRef)
 	}
 
+	_, span := tracing.StartSpan(ctx, "ListView.WaitForTask", attribute.String("vsphere.task", taskMoRef.Value))
 	l.taskMap.Upsert(taskMoRef, TaskDetails{
 		Reference:        taskMoRef,
 		MarkedForRemoval: false,
 		ResultCh:         ch,
+		span:             span,
 	})
+	l.updateTaskMapMetrics()
 	log.Debugf("task %+v added to map", taskMoRef)
 	log.Infof("client is valid. trying to add task to listview object")
 
 	response, err := l.listView.Add(l.ctx, []types.ManagedObjectReference{taskMoRef})
 	if err != nil {
+		tracing.EndSpan(span, err)
 		l.taskMap.Delete(taskMoRef)
+		l.updateTaskMapMetrics()
 		l.SetListViewNotReady(ctx)
 		return fmt.Errorf("%w. task: %v, err: %v", ErrListViewTaskAddition, taskMoRef, err)
 	}
 	if len(response) > 0 {
 		for _, unresolvedTaskRef := range response {
 			l.taskMap.Delete(unresolvedTaskRef)
+			l.updateTaskMapMetrics()
 			fault := &soap.Fault{
 				Code: "ServerFaultCode",
 				String: fmt.Sprintf("The object %v has already been deleted "+
@@ -234,7 +249,9 @@ func (l *ListViewImpl) AddTask(ctx context.Context, taskMoRef types.ManagedObjec
 			fault.Detail.Fault = types.ManagedObjectNotFound{
 				Obj: taskMoRef,
 			}
-			return soap.WrapSoapFault(fault)
+			err = soap.WrapSoapFault(fault)
+			tracing.EndSpan(span, err)
+			return err
 		}
 	}
 
@@ -244,6 +261,7 @@ func (l *ListViewImpl) AddTask(ctx context.Context, taskMoRef types.ManagedObjec
 
 // RemoveTask removes task from listview and the internal map
 func (l *ListViewImpl) RemoveTask(ctx context.Context, taskMoRef types.ManagedObjectReference) error {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemListView)
 	log := logger.GetLogger(ctx)
 	// the op context has a timeout of 5 mins.
 	// if CNS doesn't respond within that time, the context deadline is exceeded.
@@ -271,7 +289,12 @@ func (l *ListViewImpl) RemoveTask(ctx context.Context, taskMoRef types.ManagedOb
 		return logger.LogNewErrorf(log, "failed to remove task %v from ListView. error: %+v", taskMoRef, err)
 	}
 	log.Infof("task %+v removed from listView", taskMoRef)
+	if taskDetails, ok := l.taskMap.Get(taskMoRef); ok && taskDetails.span != nil {
+		// Ending a span is a no-op if it has already ended on task completion.
+		taskDetails.span.End()
+	}
 	l.taskMap.Delete(taskMoRef)
+	l.updateTaskMapMetrics()
 	log.Debugf("task %+v removed from map", taskMoRef)
 	return nil
 }
@@ -412,6 +435,9 @@ func (l *ListViewImpl) reportErrorOnAllPendingTasks(err error) {
 			Err:      err,
 		}
 		// Non-blocking send
+		if taskDetails.span != nil {
+			tracing.EndSpan(taskDetails.span, err)
+		}
 		select {
 		case taskDetails.ResultCh <- result:
 			log.Infof("reported error for task %+v", taskDetails.Reference)
@@ -448,6 +474,13 @@ func (l *ListViewImpl) processTaskUpdate(prop types.PropertyChange) {
 		result.TaskInfo = &taskInfo
 		result.Err = nil
 	}
+	if taskDetails.span != nil {
+		// The ActivationId of the task is the vCenter operation ID of the
+		// request which created it.
+		taskDetails.span.SetAttributes(attribute.String("vsphere.opid", taskInfo.ActivationId),
+			attribute.String("vsphere.task_state", string(taskInfo.State)))
+		tracing.EndSpan(taskDetails.span, result.Err)
+	}
 	// Use a non-blocking send to prevent deadlocks when multiple goroutines
 	// try to send to the same channel (e.g., due to duplicate task updates from vSphere)
 	select {
@@ -484,11 +517,13 @@ func RemoveTasksMarkedForDeletion(l *ListViewImpl) {
 	for _, task := range tasksToDelete {
 		l.taskMap.Delete(task)
 	}
+	l.updateTaskMapMetrics()
 	log.Debugf("pending tasks count after purging: %v", l.taskMap.Count())
 }
 
 // MarkTaskForDeletion marks a given task MoRef for deletion by setting a boolean flag in the TaskDetails object
 func (l *ListViewImpl) MarkTaskForDeletion(ctx context.Context, taskMoRef types.ManagedObjectReference) error {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemListView)
 	log := logger.GetLogger(ctx)
 	taskDetails, ok := l.taskMap.Get(taskMoRef)
 	if !ok {
@@ -496,6 +531,27 @@ func (l *ListViewImpl) MarkTaskForDeletion(ctx context.Context, taskMoRef types.
 	}
 	taskDetails.MarkedForRemoval = true
 	l.taskMap.Upsert(taskMoRef, taskDetails)
+	l.updateTaskMapMetrics()
 	log.Infof("%v marked for deletion", taskMoRef)
 	return nil
 }
+
+// updateTaskMapMetrics updates the gauges of the pending tasks and of the size
+// of the TaskMap of the vCenter of the ListView.
+func (l *ListViewImpl) updateTaskMapMetrics() {
+	l.mu.RLock()
+	virtualCenter := l.virtualCenter
+	l.mu.RUnlock()
+	if virtualCenter == nil || virtualCenter.Config == nil {
+		return
+	}
+	tasks := l.taskMap.GetAll()
+	pendingTasks := 0
+	for _, taskDetails := range tasks {
+		if !taskDetails.MarkedForRemoval {
+			pendingTasks++
+		}
+	}
+	prometheus.ListViewPendingTasksGaugeVec.WithLabelValues(virtualCenter.Config.Host).Set(float64(pendingTasks))
+	prometheus.ListViewTaskMapSizeGaugeVec.WithLabelValues(virtualCenter.Config.Host).Set(float64(len(tasks)))
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/vmware/govmomi/vslm"
 	vslmmethods "github.com/vmware/govmomi/vslm/methods"
 	vslmtypes "github.com/vmware/govmomi/vslm/types"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/codes"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -43,6 +44,7 @@ import (
 	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 	csifault "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/fault"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/tracing"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsvolumeoperationrequest"
 )
@@ -75,6 +77,7 @@ const (
 	// fails. e.g. When snapshot creation is successful, but update db failed, then task status will be marked as
 	// PartiallyFailed.
 	taskInvocationStatusPartiallyFailed = cnsvolumeoperationrequest.TaskInvocationStatusPartiallyFailed
+	taskInvocationStatusTimedOut        = cnsvolumeoperationrequest.TaskInvocationStatusTimedOut
 
 	// MbInBytes is the number of bytes in one mebibyte.
 	MbInBytes = int64(1024 * 1024)
@@ -126,6 +129,12 @@ type Manager interface {
 	// When ExpandVolume failed, the first return value (faultType) and second return value(error) need to be set, and
 	// should not be nil.
 	ExpandVolume(ctx context.Context, volumeID string, size int64, extraParams interface{}) (string, error)
+	// UpdateVolumePolicy changes the storage policy of a block volume, relocating
+	// it to targetDatastore when one is given.
+	// When UpdateVolumePolicy failed, the first return value (faultType) and second return value(error) need to be
+	// set, and should not be nil.
+	UpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+		targetDatastore *vim25types.ManagedObjectReference) (string, error)
 	// ResetManager helps set new manager instance and VC configuration.
 	ResetManager(ctx context.Context, vcenter *cnsvsphere.VirtualCenter) error
 	// ConfigureVolumeACLs configures net permissions for a given CnsVolumeACLConfigureSpec.
@@ -261,8 +270,21 @@ var (
 	snapshotTaskMapLock sync.Mutex
 	// Alias for CreateVolumeOperationRequestDetails function declaration.
 	createRequestDetails = cnsvolumeoperationrequest.CreateVolumeOperationRequestDetails
+
+	// ErrTaskTimedOut is returned when no response is received from CNS for a task
+	// before the deadline. The task may still be running on vCenter.
+	ErrTaskTimedOut = errors.New("time out for task")
+	// errTaskTimeoutExpired is the cause of the task wait context being cancelled
+	// when the task timeout configured for the operation expires.
+	errTaskTimeoutExpired = errors.New("task timeout configured for the operation expired")
+	// errTaskCancelled is wrapped into ErrTaskTimedOut when the timed out task was
+	// cancelled on vCenter, so the next attempt of the operation starts a new task.
+	errTaskCancelled = errors.New("task was cancelled on vCenter")
 )
 
+// taskTimeoutKey is the context key for the task timeout of a volume operation.
+type taskTimeoutKey struct{}
+
 // createSnapshotTaskDetails has the same structure as createVolumeTaskDetails
 type createSnapshotTaskDetails struct {
 	createVolumeTaskDetails
@@ -335,6 +357,23 @@ type defaultManager struct {
 	multivCenterTopologyDeployment bool
 }
 
+// opTarget returns the optional metric labels of the operations run by the
+// manager.
+func (m *defaultManager) opTarget() prometheus.OpTarget {
+	if m.virtualCenter == nil || m.virtualCenter.Config == nil {
+		return prometheus.OpTarget{}
+	}
+	return prometheus.OpTarget{VC: m.virtualCenter.Config.Host}
+}
+
+// opTargetForVolume returns the optional metric labels of the operations run
+// by the manager on the given volume.
+func (m *defaultManager) opTargetForVolume(volumeID string) prometheus.OpTarget {
+	target := m.opTarget()
+	target.DatastoreType = GetVolumeOpTarget(volumeID).DatastoreType
+	return target
+}
+
 // ClearTaskInfoObjects is a go routine which runs in the background to clean
 // up expired taskInfo objects from volumeTaskMap.
 func ClearTaskInfoObjects() {
@@ -486,12 +525,13 @@ func (m *defaultManager) MonitorCreateVolumeTask(ctx context.Context,
 		// - CNS restarted and marked "InProgress" tasks as "Failed".
 		// - Any failures from CNS.
 		// - Any failures at lower layers like FCD and SPS.
-		// In all cases, mark task as failed and retry.
+		// In all cases, mark task as failed and retry. If the task timed out, mark it
+		// as timed out so that the retry waits on the same task.
 		log.Errorf("failed to get CreateVolume taskInfo from CNS with error: %v", err)
 		*volumeOperationDetails = createRequestDetails(volNameFromInputSpec, "", "", 0,
 			(*volumeOperationDetails).QuotaDetails, (*volumeOperationDetails).OperationDetails.TaskInvocationTimestamp,
 			task.Reference().Value, vCenterServerForVolumeOperationCR,
-			(*volumeOperationDetails).OperationDetails.TaskID, taskInvocationStatusError, err.Error(), "")
+			(*volumeOperationDetails).OperationDetails.TaskID, taskInvocationStatusForError(err), err.Error(), "")
 
 		return nil, ExtractFaultTypeFromErr(ctx, err), err
 	}
@@ -848,12 +888,14 @@ func (m *defaultManager) createVolumeWithTransaction(ctx context.Context, spec *
 		spec.Metadata.ContainerClusterArray[0].ClusterId)
 }
 
-// IsTaskPending returns true in two cases -
+// IsTaskPending returns true in three cases -
 // 1. if the task status was in progress
-// 2. if the status was an error but the error was for adding the task to the listview
+// 2. if the task timed out before a response from CNS (as the task may still be running)
+// 3. if the status was an error but the error was for adding the task to the listview
 // (as we don't know the status of the task on CNS)
 func IsTaskPending(volumeOperationDetails *cnsvolumeoperationrequest.VolumeOperationRequestDetails) bool {
-	if volumeOperationDetails.OperationDetails.TaskStatus == taskInvocationStatusInProgress &&
+	if (volumeOperationDetails.OperationDetails.TaskStatus == taskInvocationStatusInProgress ||
+		volumeOperationDetails.OperationDetails.TaskStatus == taskInvocationStatusTimedOut) &&
 		volumeOperationDetails.OperationDetails.TaskID != "" {
 		return true
 	} else if volumeOperationDetails.OperationDetails.TaskStatus == taskInvocationStatusError &&
@@ -894,7 +936,75 @@ func (m *defaultManager) waitOnTask(csiOpContext context.Context,
 			}
 		}
 	}()
-	return waitForResultOrTimeout(csiOpContext, taskMoRef, ch)
+
+	// Wait no longer than the task timeout configured for the operation, if any.
+	waitContext := csiOpContext
+	if timeout, ok := csiOpContext.Value(taskTimeoutKey{}).(time.Duration); ok && timeout > 0 {
+		var cancelFunc context.CancelFunc
+		waitContext, cancelFunc = context.WithTimeoutCause(csiOpContext, timeout, errTaskTimeoutExpired)
+		defer cancelFunc()
+	}
+	taskInfo, err := waitForResultOrTimeout(waitContext, taskMoRef, ch)
+	if errors.Is(err, ErrTaskTimedOut) && errors.Is(context.Cause(waitContext), errTaskTimeoutExpired) &&
+		m.cancelTask(csiOpContext, taskMoRef) {
+		err = fmt.Errorf("%w: %w", err, errTaskCancelled)
+	}
+	return taskInfo, err
+}
+
+// withTaskTimeout returns a copy of ctx which limits the time spent waiting on
+// CNS tasks to the task timeout configured for the given operation type. If no
+// timeout is configured, the wait is bounded only by the deadline of ctx.
+func (m *defaultManager) withTaskTimeout(ctx context.Context, opType string) context.Context {
+	if m.virtualCenter == nil || m.virtualCenter.Config == nil {
+		return ctx
+	}
+	var timeoutInSec int
+	switch opType {
+	case prometheus.PrometheusCnsCreateVolumeOpType:
+		timeoutInSec = m.virtualCenter.Config.CreateVolumeTimeoutInSec
+	case prometheus.PrometheusCnsDeleteVolumeOpType:
+		timeoutInSec = m.virtualCenter.Config.DeleteVolumeTimeoutInSec
+	case prometheus.PrometheusCnsAttachVolumeOpType:
+		timeoutInSec = m.virtualCenter.Config.AttachVolumeTimeoutInSec
+	case prometheus.PrometheusCnsDetachVolumeOpType:
+		timeoutInSec = m.virtualCenter.Config.DetachVolumeTimeoutInSec
+	case prometheus.PrometheusCnsExpandVolumeOpType:
+		timeoutInSec = m.virtualCenter.Config.ExpandVolumeTimeoutInSec
+	}
+	if timeoutInSec <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, taskTimeoutKey{}, time.Duration(timeoutInSec)*time.Second)
+}
+
+// cancelTask requests vCenter to cancel the given task and returns true if it
+// was cancelled. Not all CNS tasks can be cancelled, in which case the task keeps
+// running and its outcome is picked up by the next attempt of the operation.
+func (m *defaultManager) cancelTask(ctx context.Context, taskMoRef vim25types.ManagedObjectReference) bool {
+	log := logger.GetLogger(ctx)
+	if m.virtualCenter == nil || m.virtualCenter.Client == nil {
+		return false
+	}
+	err := object.NewTask(m.virtualCenter.Client.Client, taskMoRef).Cancel(ctx)
+	if err != nil {
+		log.Warnf("failed to cancel task %q on vCenter %q after it timed out. err: %v",
+			taskMoRef.Value, m.virtualCenter.Config.Host, err)
+		return false
+	}
+	log.Infof("Cancelled task %q on vCenter %q after it timed out",
+		taskMoRef.Value, m.virtualCenter.Config.Host)
+	return true
+}
+
+// taskInvocationStatusForError returns the task status to persist when waiting
+// on a CNS task failed with the given error. A timed out task which was
+// cancelled is persisted as an error, so it is not considered pending.
+func taskInvocationStatusForError(err error) string {
+	if errors.Is(err, ErrTaskTimedOut) && !errors.Is(err, errTaskCancelled) {
+		return taskInvocationStatusTimedOut
+	}
+	return taskInvocationStatusError
 }
 
 // waitForResultOrTimeout uses the context provided by the sidecars when CSI driver operations are called.
@@ -906,7 +1016,7 @@ func waitForResultOrTimeout(csiOpContext context.Context, taskMoRef vim25types.M
 	var err error
 	select {
 	case <-csiOpContext.Done():
-		err = fmt.Errorf("time out for task %v before response from CNS", taskMoRef)
+		err = fmt.Errorf("%w %v before response from CNS", ErrTaskTimedOut, taskMoRef)
 		taskInfo = nil
 	case result := <-ch:
 		err = result.Err
@@ -1026,6 +1136,8 @@ func (m *defaultManager) CreateVolume(ctx context.Context, spec *cnstypes.CnsVol
 	extraParams interface{}) (*CnsVolumeInfo, string, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.CreateVolume", attribute.String("cns.volume_name", spec.Name))
+	ctx = m.withTaskTimeout(ctx, prometheus.PrometheusCnsCreateVolumeOpType)
 	internalCreateVolume := func() (*CnsVolumeInfo, string, error) {
 		log := logger.GetLogger(ctx)
 		var faultType string
@@ -1071,13 +1183,18 @@ func (m *defaultManager) CreateVolume(ctx context.Context, spec *cnstypes.CnsVol
 	}
 	start := time.Now()
 	resp, faultType, err := internalCreateVolume()
+	tracing.EndSpan(span, err)
 	log := logger.GetLogger(ctx)
 	log.Debugf("internalCreateVolume: returns fault %q", faultType)
+	target := m.opTarget()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsCreateVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(target).WithLabelValues(prometheus.PrometheusCnsCreateVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsCreateVolumeOpType,
+		if resp != nil {
+			target = cacheVolumeOpTarget(resp.VolumeID.Id, target.VC, resp.DatastoreURL)
+		}
+		prometheus.CnsControlOpsHistVec.ForTarget(target).WithLabelValues(prometheus.PrometheusCnsCreateVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 
@@ -1101,6 +1218,8 @@ func (m *defaultManager) AttachVolume(ctx context.Context,
 	vm *cnsvsphere.VirtualMachine, volumeID string, checkNVMeController bool) (string, string, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.AttachVolume", attribute.String("cns.volume_id", volumeID))
+	ctx = m.withTaskTimeout(ctx, prometheus.PrometheusCnsAttachVolumeOpType)
 	var internalAttachVolume func(bool) (string, string, error)
 	internalAttachVolume = func(hasRetriedAfterReregister bool) (string, string, error) {
 		log := logger.GetLogger(ctx)
@@ -1243,13 +1362,16 @@ func (m *defaultManager) AttachVolume(ctx context.Context,
 	}
 	start := time.Now()
 	resp, faultType, err := internalAttachVolume(false)
+	tracing.EndSpan(span, err)
 	log := logger.GetLogger(ctx)
 	log.Debugf("internalAttachVolume: returns fault %q for volume %q", faultType, volumeID)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsAttachVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsAttachVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsAttachVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsAttachVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return resp, faultType, err
@@ -1260,6 +1382,8 @@ func (m *defaultManager) DetachVolume(ctx context.Context, vm *cnsvsphere.Virtua
 	error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.DetachVolume", attribute.String("cns.volume_id", volumeID))
+	ctx = m.withTaskTimeout(ctx, prometheus.PrometheusCnsDetachVolumeOpType)
 	var internalDetachVolume func(bool) (string, error)
 	internalDetachVolume = func(hasRetriedAfterReregister bool) (string, error) {
 		log := logger.GetLogger(ctx)
@@ -1406,13 +1530,16 @@ func (m *defaultManager) DetachVolume(ctx context.Context, vm *cnsvsphere.Virtua
 	}
 	start := time.Now()
 	faultType, err := internalDetachVolume(false)
+	tracing.EndSpan(span, err)
 	log := logger.GetLogger(ctx)
 	log.Debugf("internalDetachVolume: returns fault %q for volume %q", faultType, volumeID)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsDetachVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsDetachVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsDetachVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsDetachVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return faultType, err
@@ -1422,6 +1549,8 @@ func (m *defaultManager) DetachVolume(ctx context.Context, vm *cnsvsphere.Virtua
 func (m *defaultManager) DeleteVolume(ctx context.Context, volumeID string, deleteDisk bool) (string, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.DeleteVolume", attribute.String("cns.volume_id", volumeID))
+	ctx = m.withTaskTimeout(ctx, prometheus.PrometheusCnsDeleteVolumeOpType)
 	internalDeleteVolume := func() (string, error) {
 		log := logger.GetLogger(ctx)
 		var faultType string
@@ -1445,14 +1574,18 @@ func (m *defaultManager) DeleteVolume(ctx context.Context, volumeID string, dele
 	}
 	start := time.Now()
 	faultType, err := internalDeleteVolume()
+	tracing.EndSpan(span, err)
 	log := logger.GetLogger(ctx)
 	log.Debugf("internalDeleteVolume: returns fault %q for volume %q", faultType, volumeID)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsDeleteVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsDeleteVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsDeleteVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsDeleteVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
+		volumeOpTargets.Delete(volumeID)
 	}
 	return faultType, err
 }
@@ -1649,6 +1782,11 @@ func (m *defaultManager) deleteVolumeWithImprovedIdempotency(ctx context.Context
 					nil, metav1.Now(), task.Reference().Value, "", "", taskInvocationStatusError, msg, "")
 				return faultType, logger.LogNewError(log, msg)
 			}
+			if errors.Is(err, ErrTaskTimedOut) {
+				volumeOperationDetails = createRequestDetails(instanceName, "", "", 0,
+					nil, volumeOperationDetails.OperationDetails.TaskInvocationTimestamp, task.Reference().Value,
+					"", "", taskInvocationStatusForError(err), err.Error(), "")
+			}
 		} else {
 			faultType = csifault.CSITaskInfoEmptyFault
 		}
@@ -1823,10 +1961,12 @@ func (m *defaultManager) UpdateVolumeMetadata(ctx context.Context, spec *cnstype
 	start := time.Now()
 	err := internalUpdateVolumeMetadata(false)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsUpdateVolumeMetadataOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(spec.VolumeId.Id)).WithLabelValues(
+			prometheus.PrometheusCnsUpdateVolumeMetadataOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsUpdateVolumeMetadataOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(spec.VolumeId.Id)).WithLabelValues(
+			prometheus.PrometheusCnsUpdateVolumeMetadataOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return err
@@ -1909,10 +2049,12 @@ func (m *defaultManager) UpdateVolumeCrypto(ctx context.Context, spec *cnstypes.
 	start := time.Now()
 	err := internalUpdateVolumeCrypto(false)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsUpdateVolumeCryptoOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(spec.VolumeId.Id)).WithLabelValues(
+			prometheus.PrometheusCnsUpdateVolumeCryptoOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsUpdateVolumeCryptoOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(spec.VolumeId.Id)).WithLabelValues(
+			prometheus.PrometheusCnsUpdateVolumeCryptoOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return err
@@ -1923,6 +2065,8 @@ func (m *defaultManager) ExpandVolume(ctx context.Context, volumeID string, size
 	extraParams interface{}) (string, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.ExpandVolume", attribute.String("cns.volume_id", volumeID))
+	ctx = m.withTaskTimeout(ctx, prometheus.PrometheusCnsExpandVolumeOpType)
 	internalExpandVolume := func() (string, error) {
 		log := logger.GetLogger(ctx)
 		var faultType string
@@ -1947,13 +2091,16 @@ func (m *defaultManager) ExpandVolume(ctx context.Context, volumeID string, size
 	}
 	start := time.Now()
 	faultType, err := internalExpandVolume()
+	tracing.EndSpan(span, err)
 	log := logger.GetLogger(ctx)
 	log.Debugf("internalExpandVolume: returns fault %q for volume %q", faultType, volumeID)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsExpandVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsExpandVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsExpandVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsExpandVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return faultType, err
@@ -2196,12 +2343,13 @@ func (m *defaultManager) expandVolumeWithImprovedIdempotency(ctx context.Context
 		// - CNS restarted and marked "InProgress" tasks as "Failed".
 		// - Any other CNS failures.
 		// - Any other failures at lower layers like FCD and SPS.
-		// In all cases, mark task as failed and retry.
+		// In all cases, mark task as failed and retry. If the task timed out, mark it
+		// as timed out so that the retry waits on the same task.
 		log.Errorf("failed to expand volume with ID %s with error %+v", volumeID, finalErr)
 		volumeOperationDetails = createRequestDetails(instanceName, "", "",
 			volumeOperationDetails.Capacity, quotaInfo, volumeOperationDetails.OperationDetails.TaskInvocationTimestamp,
 			task.Reference().Value, "", volumeOperationDetails.OperationDetails.TaskID,
-			taskInvocationStatusError, finalErr.Error(), "")
+			taskInvocationStatusForError(finalErr), finalErr.Error(), "")
 		return ExtractFaultTypeFromErr(ctx, finalErr), finalErr
 	}
 
@@ -2290,6 +2438,214 @@ func (m *defaultManager) expandVolumeWithImprovedIdempotency(ctx context.Context
 		volumeOperationDetails.Capacity, size)
 }
 
+// UpdateVolumePolicy changes the storage policy of the given block volume to
+// storagePolicyID. If targetDatastore is nil, the policy is reconfigured in
+// place; otherwise the volume is relocated to targetDatastore with the new
+// policy applied.
+func (m *defaultManager) UpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+	targetDatastore *vim25types.ManagedObjectReference) (string, error) {
+	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
+	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.UpdateVolumePolicy",
+		attribute.String("cns.volume_id", volumeID))
+	internalUpdateVolumePolicy := func() (string, error) {
+		log := logger.GetLogger(ctx)
+		err := validateManager(ctx, m)
+		if err != nil {
+			log.Errorf("validateManager failed with err: %+v", err)
+			return ExtractFaultTypeFromErr(ctx, err), err
+		}
+		// Set up the VC connection.
+		err = m.virtualCenter.ConnectCns(ctx)
+		if err != nil {
+			log.Errorf("ConnectCns failed with err: %+v", err)
+			return ExtractFaultTypeFromErr(ctx, err), err
+		}
+		if m.idempotencyHandlingEnabled {
+			return m.updateVolumePolicyWithImprovedIdempotency(ctx, volumeID, storagePolicyID, targetDatastore)
+		}
+		task, err := m.invokeUpdateVolumePolicy(ctx, volumeID, storagePolicyID, targetDatastore)
+		if err != nil {
+			return ExtractFaultTypeFromErr(ctx, err), err
+		}
+		_, faultType, err := m.waitOnUpdateVolumePolicyTask(ctx, volumeID, task)
+		return faultType, err
+	}
+	start := time.Now()
+	faultType, err := internalUpdateVolumePolicy()
+	tracing.EndSpan(span, err)
+	if err != nil {
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsUpdateVolumePolicyOpType,
+			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
+	} else {
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsUpdateVolumePolicyOpType,
+			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
+	}
+	return faultType, err
+}
+
+// invokeUpdateVolumePolicy calls CNS ReconfigVolumePolicy, or CNS
+// RelocateVolume when a target datastore is given, and returns the task.
+func (m *defaultManager) invokeUpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+	targetDatastore *vim25types.ManagedObjectReference) (*object.Task, error) {
+	log := logger.GetLogger(ctx)
+	profileSpec := &vim25types.VirtualMachineDefinedProfileSpec{
+		ProfileId: storagePolicyID,
+	}
+	var (
+		task *object.Task
+		err  error
+	)
+	if targetDatastore == nil {
+		log.Infof("Calling CnsClient.ReconfigVolumePolicy: VolumeID [%q] StoragePolicyID [%q]",
+			volumeID, storagePolicyID)
+		task, err = m.virtualCenter.CnsClient.ReconfigVolumePolicy(ctx, []cnstypes.CnsVolumePolicyReconfigSpec{
+			{
+				VolumeId: cnstypes.CnsVolumeId{Id: volumeID},
+				Profile:  []vim25types.BaseVirtualMachineProfileSpec{profileSpec},
+			},
+		})
+	} else {
+		log.Infof("Calling CnsClient.RelocateVolume: VolumeID [%q] StoragePolicyID [%q] Datastore [%v]",
+			volumeID, storagePolicyID, *targetDatastore)
+		relocateSpec := cnstypes.NewCnsBlockVolumeRelocateSpec(volumeID, *targetDatastore, profileSpec)
+		task, err = m.virtualCenter.CnsClient.RelocateVolume(ctx, relocateSpec)
+	}
+	if err != nil {
+		if cnsvsphere.IsNotFoundError(err) {
+			return nil, logger.LogNewErrorf(log, "volume %q not found. Cannot update storage policy.", volumeID)
+		}
+		log.Errorf("CNS storage policy update failed from the vCenter %q with err: %v",
+			m.virtualCenter.Config.Host, err)
+		return nil, err
+	}
+	return task, nil
+}
+
+// waitOnUpdateVolumePolicyTask waits for the given ReconfigVolumePolicy or
+// RelocateVolume task and returns the CNS operation ID on success.
+func (m *defaultManager) waitOnUpdateVolumePolicyTask(ctx context.Context, volumeID string,
+	task *object.Task) (string, string, error) {
+	log := logger.GetLogger(ctx)
+	taskInfo, err := m.waitOnTask(ctx, task.Reference())
+	if err != nil || taskInfo == nil {
+		log.Errorf("failed to get taskInfo for storage policy update task %s from vCenter %q with err: %v",
+			task.Reference().Value, m.virtualCenter.Config.Host, err)
+		if err != nil {
+			return "", ExtractFaultTypeFromErr(ctx, err), err
+		}
+		return "", csifault.CSITaskInfoEmptyFault, logger.LogNewErrorf(log,
+			"taskInfo is empty for storage policy update task %q", task.Reference().Value)
+	}
+	log.Infof("UpdateVolumePolicy: volumeID: %q, opId: %q", volumeID, taskInfo.ActivationId)
+	taskResult, err := getTaskResultFromTaskInfo(ctx, taskInfo)
+	if taskResult == nil {
+		return taskInfo.ActivationId, csifault.CSITaskResultEmptyFault,
+			logger.LogNewErrorf(log, "taskResult is empty for storage policy update task: %q, opID: %q",
+				taskInfo.Task.Value, taskInfo.ActivationId)
+	}
+	if err != nil {
+		log.Errorf("failed to get task result for storage policy update task %s with error: %v",
+			task.Reference().Value, err)
+		return taskInfo.ActivationId, ExtractFaultTypeFromErr(ctx, err), err
+	}
+	volumeOperationRes := taskResult.GetCnsVolumeOperationResult()
+	if volumeOperationRes.Fault != nil {
+		return taskInfo.ActivationId, ExtractFaultTypeFromVolumeResponseResult(ctx, volumeOperationRes),
+			logger.LogNewErrorf(log, "failed to update storage policy of volume: %q, fault: %q, opID: %q",
+				volumeID, spew.Sdump(volumeOperationRes.Fault), taskInfo.ActivationId)
+	}
+	log.Infof("UpdateVolumePolicy: storage policy updated successfully. volumeID: %q, opId: %q",
+		volumeID, taskInfo.ActivationId)
+	return taskInfo.ActivationId, "", nil
+}
+
+// updateVolumePolicyWithImprovedIdempotency leverages the VolumeOperationRequest
+// interface to persist CNS task information, so that a retried
+// ControllerModifyVolume call waits on a storage policy update task that is
+// already in progress instead of invoking a new one.
+func (m *defaultManager) updateVolumePolicyWithImprovedIdempotency(ctx context.Context, volumeID string,
+	storagePolicyID string, targetDatastore *vim25types.ManagedObjectReference) (faultType string, finalErr error) {
+	log := logger.GetLogger(ctx)
+	var (
+		// Reference to the ReconfigVolumePolicy or RelocateVolume task.
+		task *object.Task
+		// Details to be persisted.
+		volumeOperationDetails *cnsvolumeoperationrequest.VolumeOperationRequestDetails
+		// CnsVolumeOperationRequest instance name. Policy IDs are UUIDs, lower
+		// them to keep the name a valid object name.
+		instanceName = "modify-" + volumeID + "-" + strings.ToLower(storagePolicyID)
+	)
+	if m.operationStore == nil {
+		return csifault.CSIInternalFault, logger.LogNewError(log, "operation store cannot be nil")
+	}
+
+	volumeOperationDetails, finalErr = m.operationStore.GetRequestDetails(ctx, instanceName)
+	switch {
+	case finalErr == nil:
+		// A previously successful update is not trusted here, as the policy may
+		// have been changed again since. Callers check the current policy of the
+		// volume before invoking UpdateVolumePolicy.
+		if volumeOperationDetails.OperationDetails != nil && IsTaskPending(volumeOperationDetails) {
+			log.Infof("Volume with ID %s has storage policy update task %s pending on CNS.",
+				volumeID, volumeOperationDetails.OperationDetails.TaskID)
+			taskMoRef := vim25types.ManagedObjectReference{
+				Type:  "Task",
+				Value: volumeOperationDetails.OperationDetails.TaskID,
+			}
+			task = object.NewTask(m.virtualCenter.Client.Client, taskMoRef)
+		}
+	case !apierrors.IsNotFound(finalErr):
+		return csifault.CSIInternalFault, finalErr
+	}
+	defer func() {
+		// Persist the operation details before returning.
+		if volumeOperationDetails != nil && volumeOperationDetails.OperationDetails != nil &&
+			volumeOperationDetails.OperationDetails.TaskStatus != taskInvocationStatusInProgress {
+			err := m.operationStore.StoreRequestDetails(ctx, volumeOperationDetails)
+			if err != nil {
+				log.Warnf("failed to store UpdateVolumePolicy details with error: %v", err)
+			}
+		}
+	}()
+
+	if task == nil {
+		volumeOperationDetails = createRequestDetails(instanceName, volumeID, "", 0,
+			nil, metav1.Now(), "", "", "", taskInvocationStatusInProgress, "", "")
+		err := m.operationStore.StoreRequestDetails(ctx, volumeOperationDetails)
+		if err != nil {
+			log.Warnf("failed to store UpdateVolumePolicy details with error: %v", err)
+		}
+		task, finalErr = m.invokeUpdateVolumePolicy(ctx, volumeID, storagePolicyID, targetDatastore)
+		if finalErr != nil {
+			volumeOperationDetails = createRequestDetails(instanceName, volumeID, "", 0, nil,
+				metav1.Now(), "", "", "", taskInvocationStatusError, finalErr.Error(), "")
+			return ExtractFaultTypeFromErr(ctx, finalErr), finalErr
+		}
+		volumeOperationDetails = createRequestDetails(instanceName, volumeID, "", 0, nil,
+			metav1.Now(), task.Reference().Value, "", "", taskInvocationStatusInProgress, "", "")
+		err = m.operationStore.StoreRequestDetails(ctx, volumeOperationDetails)
+		if err != nil {
+			log.Warnf("failed to store UpdateVolumePolicy details with error: %v", err)
+		}
+	}
+
+	var opID string
+	opID, faultType, finalErr = m.waitOnUpdateVolumePolicyTask(ctx, volumeID, task)
+	if finalErr != nil {
+		volumeOperationDetails = createRequestDetails(instanceName, volumeID, "", 0, nil,
+			volumeOperationDetails.OperationDetails.TaskInvocationTimestamp, task.Reference().Value, "",
+			opID, taskInvocationStatusForError(finalErr), finalErr.Error(), "")
+		return faultType, finalErr
+	}
+	volumeOperationDetails = createRequestDetails(instanceName, volumeID, "", 0, nil,
+		volumeOperationDetails.OperationDetails.TaskInvocationTimestamp, task.Reference().Value, "",
+		opID, taskInvocationStatusSuccess, "", "")
+	return "", nil
+}
+
 // QueryVolume returns volumes matching the given filter.
 func (m *defaultManager) QueryVolume(ctx context.Context,
 	queryFilter cnstypes.CnsQueryFilter) (*cnstypes.CnsQueryResult, error) {
@@ -2314,15 +2670,16 @@ func (m *defaultManager) QueryVolume(ctx context.Context,
 			return nil, err
 		}
 		res = updateQueryResult(ctx, m, res)
+		cacheQueryResultOpTargets(m.virtualCenter.Config.Host, res)
 		return res, err
 	}
 	start := time.Now()
 	resp, err := internalQueryVolume()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2352,15 +2709,16 @@ func (m *defaultManager) QueryAllVolume(ctx context.Context, queryFilter cnstype
 			return nil, err
 		}
 		res = updateQueryResult(ctx, m, res)
+		cacheQueryResultOpTargets(m.virtualCenter.Config.Host, res)
 		return res, err
 	}
 	start := time.Now()
 	resp, err := internalQueryAllVolume()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryAllVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryAllVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryAllVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryAllVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2425,10 +2783,10 @@ func (m *defaultManager) QueryVolumeInfo(ctx context.Context,
 	start := time.Now()
 	resp, err := internalQueryVolumeInfo()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2462,10 +2820,10 @@ func (m *defaultManager) RelocateVolume(ctx context.Context,
 	start := time.Now()
 	resp, err := internalRelocateVolume()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsRelocateVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsRelocateVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsRelocateVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsRelocateVolumeOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2532,10 +2890,12 @@ func (m *defaultManager) ConfigureVolumeACLs(ctx context.Context, spec cnstypes.
 	start := time.Now()
 	err := internalConfigureVolumeACLs()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsConfigureVolumeACLOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsConfigureVolumeACLOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsConfigureVolumeACLOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsConfigureVolumeACLOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return err
@@ -2710,10 +3070,10 @@ func (m *defaultManager) QuerySnapshots(ctx context.Context, snapshotQueryFilter
 	start := time.Now()
 	resp, err := internalQuerySnapshots()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusQuerySnapshotsOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusQuerySnapshotsOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusQuerySnapshotsOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusQuerySnapshotsOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -3228,6 +3588,7 @@ func (m *defaultManager) CreateSnapshot(
 	ctx context.Context, volumeID string, snapshotName string, extraParams interface{}) (*CnsSnapshotInfo, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.CreateSnapshot", attribute.String("cns.volume_id", volumeID))
 	internalCreateSnapshot := func() (*CnsSnapshotInfo, error) {
 		log := logger.GetLogger(ctx)
 		err := validateManager(ctx, m)
@@ -3268,11 +3629,14 @@ func (m *defaultManager) CreateSnapshot(
 
 	start := time.Now()
 	cnsSnapshotInfo, err := internalCreateSnapshot()
+	tracing.EndSpan(span, err)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsCreateSnapshotOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsCreateSnapshotOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsCreateSnapshotOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsCreateSnapshotOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return cnsSnapshotInfo, err
@@ -3589,6 +3953,9 @@ func (m *defaultManager) DeleteSnapshot(ctx context.Context, volumeID string, sn
 	extraParams interface{}) (*CnsSnapshotInfo, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.DeleteSnapshot",
+		attribute.String("cns.volume_id", volumeID),
+		attribute.String("cns.snapshot_id", snapshotID))
 	internalDeleteSnapshot := func() (*CnsSnapshotInfo, error) {
 		log := logger.GetLogger(ctx)
 		err := validateManager(ctx, m)
@@ -3607,11 +3974,14 @@ func (m *defaultManager) DeleteSnapshot(ctx context.Context, volumeID string, sn
 
 	start := time.Now()
 	cnsSnapshotInfo, err := internalDeleteSnapshot()
+	tracing.EndSpan(span, err)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsDeleteSnapshotOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsDeleteSnapshotOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsDeleteSnapshotOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTargetForVolume(volumeID)).WithLabelValues(
+			prometheus.PrometheusCnsDeleteSnapshotOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return cnsSnapshotInfo, err
@@ -3759,10 +4129,12 @@ func (m *defaultManager) SetVolumeControlFlags(ctx context.Context, volumeID str
 	start := time.Now()
 	err := internalSetVolumeControlFlags()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsSetVolumeControlFlagsOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsSetVolumeControlFlagsOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsSetVolumeControlFlagsOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsSetVolumeControlFlagsOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return err
@@ -3801,10 +4173,12 @@ func (m *defaultManager) ClearVolumeControlFlags(ctx context.Context, volumeID s
 	start := time.Now()
 	err := internalClearVolumeControlFlags()
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsClearVolumeControlFlagsOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsClearVolumeControlFlagsOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsClearVolumeControlFlagsOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsClearVolumeControlFlagsOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return err
@@ -3984,6 +4358,7 @@ func (m *defaultManager) BatchAttachVolumes(ctx context.Context,
 	batchAttachRequest []BatchAttachRequest) ([]BatchAttachResult, string, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.BatchAttachVolumes")
 	internalBatchAttachVolumes := func() ([]BatchAttachResult, string, error) {
 		log := logger.GetLogger(ctx)
 		var faultType string
@@ -4076,14 +4451,17 @@ func (m *defaultManager) BatchAttachVolumes(ctx context.Context,
 	log := logger.GetLogger(ctx)
 	start := time.Now()
 	batchAttachResult, faultType, err := internalBatchAttachVolumes()
+	tracing.EndSpan(span, err)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsBatchAttachVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+			prometheus.PrometheusCnsBatchAttachVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 		log.Errorf("CNS BatchAttachVolumes failed with err: %s", err)
 		return batchAttachResult, faultType, err
 	}
 
-	prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsBatchAttachVolumeOpType,
+	prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(
+		prometheus.PrometheusCnsBatchAttachVolumeOpType,
 		prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 
 	return batchAttachResult, faultType, err
@@ -4093,6 +4471,7 @@ func (m *defaultManager) BatchAttachVolumes(ctx context.Context,
 func (m *defaultManager) SyncVolume(ctx context.Context, syncVolumeSpecs []cnstypes.CnsSyncVolumeSpec) (string, error) {
 	ctx, cancelFunc := ensureOperationContextHasATimeout(ctx)
 	defer cancelFunc()
+	ctx, span := tracing.StartSpan(ctx, "volume.Manager.SyncVolume")
 	internalSyncVolumeInfo := func() (string, error) {
 		log := logger.GetLogger(ctx)
 		err := validateManager(ctx, m)
@@ -4140,13 +4519,14 @@ func (m *defaultManager) SyncVolume(ctx context.Context, syncVolumeSpecs []cnsty
 	}
 	start := time.Now()
 	faultType, err := internalSyncVolumeInfo()
+	tracing.EndSpan(span, err)
 	log := logger.GetLogger(ctx)
 	log.Debugf("internalSyncVolumeInfo: returns fault %q for CnsSyncVolumeSpecs %v", faultType, syncVolumeSpecs)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusCnsQueryVolumeInfoOpType,
 			prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	}
 	return faultType, err
@@ -4173,12 +4553,12 @@ func (m *defaultManager) UnregisterVolume(ctx context.Context, volumeID string,
 	start := time.Now()
 	faultType, err := m.unregisterVolume(ctx, volumeID, unregisterDisk)
 	if err != nil {
-		prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusUnregisterVolumeOpType,
+		prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusUnregisterVolumeOpType,
 			prometheus.PrometheusFailStatus).Observe(time.Since(start).Seconds())
 		return faultType, err
 	}
 
-	prometheus.CnsControlOpsHistVec.WithLabelValues(prometheus.PrometheusUnregisterVolumeOpType,
+	prometheus.CnsControlOpsHistVec.ForTarget(m.opTarget()).WithLabelValues(prometheus.PrometheusUnregisterVolumeOpType,
 		prometheus.PrometheusPassStatus).Observe(time.Since(start).Seconds())
 	return "", nil
 }
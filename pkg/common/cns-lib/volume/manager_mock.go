@@ -116,6 +116,12 @@ func (m MockManager) ExpandVolume(ctx context.Context, volumeID string, size int
 	panic("implement me")
 }
 
+func (m MockManager) UpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+	targetDatastore *vim25types.ManagedObjectReference) (string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m MockManager) ResetManager(ctx context.Context, vcenter *cnsvsphere.VirtualCenter) error {
 	//TODO implement me
 	panic("implement me")
@@ -18,7 +18,10 @@ import (
 	"google.golang.org/grpc/status"
 
 	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	csifault "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/fault"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsvolumeoperationrequest"
 )
 
 const createVolumeTaskTimeout = 3 * time.Second
@@ -42,6 +45,8 @@ func TestWaitForResultOrTimeoutFail(t *testing.T) {
 	}
 	taskInfo, err := waitForResultOrTimeout(ctx, taskMoRef, ch)
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrTaskTimedOut)
+	assert.Equal(t, csifault.CSITaskTimedOutFault, ExtractFaultTypeFromErr(ctx, err))
 	var expectedTaskInfo *vim25types.TaskInfo
 	assert.Equal(t, expectedTaskInfo, taskInfo)
 }
@@ -62,6 +67,46 @@ func TestWaitForResultOrTimeoutPass(t *testing.T) {
 	assert.Equal(t, expectedTaskInfo, taskInfo)
 }
 
+func TestWithTaskTimeout(t *testing.T) {
+	m := &defaultManager{
+		virtualCenter: &cnsvsphere.VirtualCenter{
+			Config: &cnsvsphere.VirtualCenterConfig{
+				CreateVolumeTimeoutInSec: 30,
+			},
+		},
+	}
+	ctx := m.withTaskTimeout(context.TODO(), prometheus.PrometheusCnsCreateVolumeOpType)
+	timeout, ok := ctx.Value(taskTimeoutKey{}).(time.Duration)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	// No timeout is configured for DeleteVolume.
+	ctx = m.withTaskTimeout(context.TODO(), prometheus.PrometheusCnsDeleteVolumeOpType)
+	assert.Nil(t, ctx.Value(taskTimeoutKey{}))
+}
+
+func TestIsTaskPendingAfterTimeout(t *testing.T) {
+	details := &cnsvolumeoperationrequest.VolumeOperationRequestDetails{
+		Name: "pvc-1",
+		OperationDetails: &cnsvolumeoperationrequest.OperationDetails{
+			TaskID:     "task-42",
+			TaskStatus: taskInvocationStatusForError(fmt.Errorf("%w task-42", ErrTaskTimedOut)),
+		},
+	}
+	assert.Equal(t, taskInvocationStatusTimedOut, details.OperationDetails.TaskStatus)
+	assert.True(t, IsTaskPending(details))
+
+	// A timed out task which was cancelled on vCenter is not pending anymore.
+	details.OperationDetails.TaskStatus = taskInvocationStatusForError(
+		fmt.Errorf("%w: %w", fmt.Errorf("%w task-42", ErrTaskTimedOut), errTaskCancelled))
+	assert.Equal(t, taskInvocationStatusError, details.OperationDetails.TaskStatus)
+	assert.False(t, IsTaskPending(details))
+
+	details.OperationDetails.TaskStatus = taskInvocationStatusForError(fmt.Errorf("task failed"))
+	assert.Equal(t, taskInvocationStatusError, details.OperationDetails.TaskStatus)
+	assert.False(t, IsTaskPending(details))
+}
+
 func performSlowTask(ch chan TaskResult, delay time.Duration) {
 	time.Sleep(delay)
 	ch <- TaskResult{
@@ -973,3 +1018,30 @@ func TestDefaultVslmHooksDisconnectedVC(t *testing.T) {
 	_, err = defaultRetrieveSnapshotDetailsHook(ctx, &cnsvsphere.VirtualCenter{}, vim25types.ID{}, vim25types.ID{})
 	assert.Error(t, err)
 }
+
+func TestVolumeOpTargets(t *testing.T) {
+	defer volumeOpTargets.Clear()
+	target := cacheVolumeOpTarget("vol-1", "vc1",
+		"ds:///vmfs/volumes/vsan:52a5bc2cd0bb4bf6-9f0fe4a5e5b5c85a/")
+	assert.Equal(t, prometheus.OpTarget{VC: "vc1", DatastoreType: "vsan"}, target)
+	assert.Equal(t, target, GetVolumeOpTarget("vol-1"))
+
+	cacheQueryResultOpTargets("vc2", &cnstypes.CnsQueryResult{
+		Volumes: []cnstypes.CnsVolume{
+			{VolumeId: cnstypes.CnsVolumeId{Id: "vol-2"}, DatastoreUrl: "ds:///vmfs/volumes/ds1/"},
+			{VolumeId: cnstypes.CnsVolumeId{Id: "vol-3"}},
+		},
+	})
+	assert.Equal(t, prometheus.OpTarget{VC: "vc2"}, GetVolumeOpTarget("vol-2"))
+	assert.Equal(t, prometheus.OpTarget{}, GetVolumeOpTarget("vol-3"))
+	assert.Equal(t, prometheus.OpTarget{}, GetVolumeOpTarget("unknown"))
+
+	// Expired labels are not returned, and are swept when a label is cached.
+	volumeOpTargets.entries["vol-1"] = volumeOpTargetEntry{target: target,
+		cachedAt: time.Now().Add(-volumeOpTargetTTL)}
+	volumeOpTargets.lastSweep = time.Time{}
+	assert.Equal(t, prometheus.OpTarget{}, GetVolumeOpTarget("vol-1"))
+	cacheVolumeOpTarget("vol-4", "vc1", "ds:///vmfs/volumes/ds1/")
+	assert.NotContains(t, volumeOpTargets.entries, "vol-1")
+	assert.Contains(t, volumeOpTargets.entries, "vol-4")
+}
@@ -21,6 +21,8 @@ import (
 	"errors"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	uuidlib "github.com/google/uuid"
@@ -34,7 +36,9 @@ import (
 
 	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 	csifault "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/fault"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsvolumeoperationrequest"
 )
 
 // BatchAttachRequest has details for each volume in order to call CNS Attach volume in batch.
@@ -380,11 +384,15 @@ func getCnsVolumeInfoFromTaskResult(ctx context.Context, virtualCenter *cnsvsphe
 }
 
 // ExtractFaultTypeFromErr extracts the fault type from err.
+// Return "csi.fault.TaskTimedOut" if the input err is a timeout waiting on a CNS task.
 // Return the vim fault type if the input err is a SoapFault, and can exract the fault type of VimFault.
 // Otherwise, it returns fault type as "csi.fault.Internal".
 func ExtractFaultTypeFromErr(ctx context.Context, err error) string {
 	log := logger.GetLogger(ctx)
 	var faultType string
+	if errors.Is(err, ErrTaskTimedOut) {
+		return csifault.CSITaskTimedOutFault
+	}
 	if soap.IsSoapFault(err) {
 		soapFault := soap.ToSoapFault(err)
 		// faultType has the format like "type.XXX", XXX is the specific VimFault type.
@@ -499,6 +507,26 @@ func getPendingCreateSnapshotTaskFromMap(ctx context.Context, snapshotName strin
 	return task
 }
 
+// ResetCreateSnapshotDetails removes the details of creating the snapshot with
+// the given name on the volume from the operation store and from the
+// in-memory task map, so that creating the snapshot again starts a new
+// CreateSnapshot task instead of returning the previously created snapshot.
+// It is meant to be called once that snapshot has been deleted.
+func ResetCreateSnapshotDetails(ctx context.Context, operationStore cnsvolumeoperationrequest.VolumeOperationRequest,
+	volumeID string, snapshotName string) error {
+	// Keep the instance name in sync with createSnapshotWithImprovedIdempotencyCheck.
+	instanceName := snapshotName + "-" + volumeID
+	func() {
+		snapshotTaskMapLock.Lock()
+		defer snapshotTaskMapLock.Unlock()
+		delete(snapshotTaskMap, instanceName)
+	}()
+	if operationStore == nil {
+		return nil
+	}
+	return operationStore.DeleteRequestDetails(ctx, instanceName)
+}
+
 // validateVolumeCapacity queries the CNS volume and validates the returned size with
 // input size.
 // Returns true if the volume capacity is greater than or equal to the input size.
@@ -653,3 +681,118 @@ func IsCnsNotRegisteredFault(ctx context.Context, fault *types.LocalizedMethodFa
 	}
 	return false
 }
+
+// getDatastoreTypeFromURL returns the type of the datastore with the given URL
+// for the datastore types which can be identified from the URL, e.g.
+// "ds:///vmfs/volumes/vsan:52a5bc2cd0bb4bf6-9f0fe4a5e5b5c85a/" for vSAN.
+// An empty string is returned for the other datastores.
+func getDatastoreTypeFromURL(datastoreURL string) string {
+	for _, dsType := range []string{"vsan", "vvol", "pmem"} {
+		if strings.Contains(datastoreURL, "/vmfs/volumes/"+dsType+":") {
+			return dsType
+		}
+	}
+	return ""
+}
+
+const (
+	// volumeOpTargetTTL is the time after which a cached volume metric label
+	// expires. The labels are cached again when the volumes are queried.
+	volumeOpTargetTTL = 2 * time.Hour
+	// maxVolumeOpTargets is the maximum number of volumes whose metric labels
+	// are cached. The operations on the other volumes report empty labels.
+	maxVolumeOpTargets = 100000
+)
+
+// volumeOpTargetEntry is a cached volume metric label.
+type volumeOpTargetEntry struct {
+	target   prometheus.OpTarget
+	cachedAt time.Time
+}
+
+// volumeOpTargetCache caches the metric labels of the volumes by volume ID.
+// Expired entries are swept at most once per volumeOpTargetTTL when a label is
+// cached, and the cache holds at most maxVolumeOpTargets entries.
+type volumeOpTargetCache struct {
+	lock      sync.Mutex
+	entries   map[string]volumeOpTargetEntry
+	lastSweep time.Time
+}
+
+// volumeOpTargets caches the vCenter and the datastore type of the volumes
+// created or queried by the volume managers, by volume ID, so that the later
+// operations on the volumes report them in their optional metric labels.
+var volumeOpTargets = &volumeOpTargetCache{entries: make(map[string]volumeOpTargetEntry)}
+
+// Store caches the metric label of the volume.
+func (c *volumeOpTargetCache) Store(volumeID string, target prometheus.OpTarget) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	if now.Sub(c.lastSweep) >= volumeOpTargetTTL {
+		for id, entry := range c.entries {
+			if now.Sub(entry.cachedAt) >= volumeOpTargetTTL {
+				delete(c.entries, id)
+			}
+		}
+		c.lastSweep = now
+	}
+	if _, ok := c.entries[volumeID]; !ok && len(c.entries) >= maxVolumeOpTargets {
+		return
+	}
+	c.entries[volumeID] = volumeOpTargetEntry{target: target, cachedAt: now}
+}
+
+// Load returns the cached metric label of the volume, if it has not expired.
+func (c *volumeOpTargetCache) Load(volumeID string) (prometheus.OpTarget, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[volumeID]
+	if !ok || time.Since(entry.cachedAt) >= volumeOpTargetTTL {
+		return prometheus.OpTarget{}, false
+	}
+	return entry.target, true
+}
+
+// Delete removes the cached metric label of the volume.
+func (c *volumeOpTargetCache) Delete(volumeID string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, volumeID)
+}
+
+// Clear removes all the cached metric labels.
+func (c *volumeOpTargetCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]volumeOpTargetEntry)
+}
+
+// cacheVolumeOpTarget records the vCenter and the type of the datastore with
+// the given URL as the metric labels of the volume, and returns them.
+func cacheVolumeOpTarget(volumeID, vcHost, datastoreURL string) prometheus.OpTarget {
+	target := prometheus.OpTarget{VC: vcHost, DatastoreType: getDatastoreTypeFromURL(datastoreURL)}
+	if volumeID != "" && datastoreURL != "" {
+		volumeOpTargets.Store(volumeID, target)
+	}
+	return target
+}
+
+// cacheQueryResultOpTargets records the metric labels of the volumes of a CNS
+// query result which has their datastore URLs.
+func cacheQueryResultOpTargets(vcHost string, res *cnstypes.CnsQueryResult) {
+	if res == nil {
+		return
+	}
+	for _, volume := range res.Volumes {
+		cacheVolumeOpTarget(volume.VolumeId.Id, vcHost, volume.DatastoreUrl)
+	}
+}
+
+// GetVolumeOpTarget returns the vCenter and the datastore type of a volume
+// created or queried by the volume managers, for the optional metric labels of
+// the operations on the volume. They are empty if the volume is not known.
+func GetVolumeOpTarget(volumeID string) prometheus.OpTarget {
+	target, _ := volumeOpTargets.Load(volumeID)
+	return target
+}
@@ -0,0 +1,413 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+)
+
+const (
+	// EnvCredentialExecVCenterHost is the environment variable set for the
+	// credential exec plugin to the host of the vCenter server whose
+	// credentials are requested.
+	EnvCredentialExecVCenterHost = "VSPHERE_CSI_VCENTER_HOST"
+
+	// credentialExecTimeout is the time allowed for the credential exec plugin
+	// to return the credentials.
+	credentialExecTimeout = 30 * time.Second
+	// credentialExpirySkew is the time before the expiration of the credentials
+	// returned by the exec plugin after which the plugin is run again.
+	credentialExpirySkew = time.Minute
+)
+
+// Credentials holds the credentials used to login to a vCenter server.
+// Username and Password hold a PEM encoded certificate and private key when
+// the login is done using a certificate.
+type Credentials struct {
+	Username string
+	Password string
+	// Token is a SAML bearer token used to login instead of the Username and
+	// Password. Username is still used for the privilege checks of the user.
+	Token string
+}
+
+// ExecCredential is the JSON object written to stdout by the credential exec
+// plugin.
+type ExecCredential struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+	// ExpirationTimestamp is the time after which the credentials are no longer
+	// valid. The credentials are cached until then. If it is not set, the
+	// plugin is run every time the credentials are needed.
+	ExpirationTimestamp *time.Time `json:"expirationTimestamp,omitempty"`
+}
+
+// CredentialProvider provides the credentials used to login to a vCenter
+// server.
+type CredentialProvider interface {
+	// GetCredentials returns the current credentials of the vCenter server.
+	GetCredentials(ctx context.Context) (*Credentials, error)
+}
+
+// CredentialRejectedHandler is invoked when vCenter rejects rotated
+// credentials.
+type CredentialRejectedHandler func(ctx context.Context, vcHost string, err error)
+
+// configCredentialProvider implements the CredentialProvider interface by
+// returning the credentials set in a VirtualCenterConfig.
+type configCredentialProvider struct {
+	config *VirtualCenterConfig
+}
+
+// fileCredentialProvider implements the CredentialProvider interface by
+// reading the username and password from separate files, e.g. keys of a
+// mounted Kubernetes secret. The files are read on every call so that rotated
+// secrets are picked up.
+type fileCredentialProvider struct {
+	userFile     string
+	passwordFile string
+}
+
+// execCredentialProvider implements the CredentialProvider interface by
+// running a plugin which writes an ExecCredential to stdout.
+type execCredentialProvider struct {
+	host    string
+	command string
+	args    []string
+
+	lock        sync.Mutex
+	credentials *Credentials
+	expiry      time.Time
+}
+
+var (
+	// credentialRejectedHandler is invoked when vCenter rejects rotated
+	// credentials.
+	credentialRejectedHandler CredentialRejectedHandler
+	// credentialRejectedHandlerLock protects credentialRejectedHandler.
+	credentialRejectedHandlerLock = &sync.RWMutex{}
+	// credentialsLock protects the credential fields of VirtualCenter
+	// instances. It is not part of VirtualCenter as VirtualCenter values are
+	// copied.
+	credentialsLock = &sync.Mutex{}
+	// sessionLogoutGracePeriod is the time after which the session replaced by
+	// a credential rotation is logged out, so that the CNS, PBM and VSLM calls
+	// and the tasks in flight on it can complete.
+	sessionLogoutGracePeriod = 5 * time.Minute
+)
+
+// NewConfigCredentialProvider returns a CredentialProvider which returns the
+// credentials set in the given VirtualCenterConfig.
+func NewConfigCredentialProvider(config *VirtualCenterConfig) CredentialProvider {
+	return &configCredentialProvider{config: config}
+}
+
+// GetCredentials returns the username and password of the VirtualCenterConfig.
+func (p *configCredentialProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	if p.config == nil {
+		return nil, errors.New("vCenter config is not set")
+	}
+	return &Credentials{Username: p.config.Username, Password: p.config.Password}, nil
+}
+
+// NewCredentialProvider returns the CredentialProvider for the credential
+// source set in the given VirtualCenterConfig.
+func NewCredentialProvider(vcConfig *VirtualCenterConfig) (CredentialProvider, error) {
+	if vcConfig == nil {
+		return nil, errors.New("vCenter config is not set")
+	}
+	switch vcConfig.CredentialSource {
+	case "", config.CredentialSourceConfig:
+		return NewConfigCredentialProvider(vcConfig), nil
+	case config.CredentialSourceFile:
+		if vcConfig.UserFile == "" || vcConfig.PasswordFile == "" {
+			return nil, config.ErrCredentialFileMissing
+		}
+		return &fileCredentialProvider{userFile: vcConfig.UserFile, passwordFile: vcConfig.PasswordFile}, nil
+	case config.CredentialSourceExec:
+		if vcConfig.CredentialExecCommand == "" {
+			return nil, config.ErrCredentialExecCommandMissing
+		}
+		return &execCredentialProvider{
+			host:    vcConfig.Host,
+			command: vcConfig.CredentialExecCommand,
+			args:    vcConfig.CredentialExecArgs,
+		}, nil
+	default:
+		return nil, config.ErrInvalidCredentialSource
+	}
+}
+
+// GetCredentials reads the username and password from their files.
+func (p *fileCredentialProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	username, err := os.ReadFile(p.userFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user file %q: %w", p.userFile, err)
+	}
+	password, err := os.ReadFile(p.passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password file %q: %w", p.passwordFile, err)
+	}
+	creds := &Credentials{
+		Username: strings.TrimSpace(string(username)),
+		Password: strings.TrimRight(string(password), "\r\n"),
+	}
+	if creds.Username == "" || creds.Password == "" {
+		return nil, fmt.Errorf("user file %q or password file %q is empty", p.userFile, p.passwordFile)
+	}
+	if err := config.ValidateCredentialUsername(creds.Username); err != nil {
+		return nil, fmt.Errorf("user file %q holds an invalid username %q: %w", p.userFile, creds.Username, err)
+	}
+	return creds, nil
+}
+
+// GetCredentials returns the cached credentials if they have not expired.
+// Otherwise, it runs the plugin to obtain new credentials.
+func (p *execCredentialProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	log := logger.GetLogger(ctx)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.credentials != nil && time.Now().Before(p.expiry) {
+		return p.credentials, nil
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, credentialExecTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(execCtx, p.command, p.args...)
+	cmd.Env = append(os.Environ(), EnvCredentialExecVCenterHost+"="+p.host)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential exec plugin %q failed: %w. stderr: %s", p.command, err,
+			strings.TrimSpace(stderr.String()))
+	}
+	var execCred ExecCredential
+	if err := json.Unmarshal(stdout.Bytes(), &execCred); err != nil {
+		return nil, fmt.Errorf("failed to decode the output of credential exec plugin %q: %w", p.command, err)
+	}
+	if execCred.Token == "" && (execCred.Username == "" || execCred.Password == "") {
+		return nil, fmt.Errorf("credential exec plugin %q returned neither a token nor a username and password",
+			p.command)
+	}
+	if err := config.ValidateCredentialUsername(execCred.Username); err != nil {
+		return nil, fmt.Errorf("credential exec plugin %q returned an invalid username %q: %w", p.command,
+			execCred.Username, err)
+	}
+	creds := &Credentials{Username: execCred.Username, Password: execCred.Password, Token: execCred.Token}
+	p.credentials, p.expiry = nil, time.Time{}
+	if execCred.ExpirationTimestamp != nil {
+		p.credentials, p.expiry = creds, execCred.ExpirationTimestamp.Add(-credentialExpirySkew)
+	}
+	log.Debugf("obtained credentials for vCenter %q from credential exec plugin %q", p.host, p.command)
+	return creds, nil
+}
+
+// getCredentialExecArgs splits the comma separated credential exec plugin
+// arguments.
+func getCredentialExecArgs(args string) []string {
+	var execArgs []string
+	for _, arg := range strings.Split(args, ",") {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			execArgs = append(execArgs, arg)
+		}
+	}
+	return execArgs
+}
+
+// getCredentials returns the credentials used to login to vCenter from the
+// credential source of vc.Config. The provider is cached so that credentials
+// with an expiry are reused until they expire.
+func (vc *VirtualCenter) getCredentials(ctx context.Context) (*Credentials, error) {
+	credentialsLock.Lock()
+	if vc.credentialProvider == nil || vc.credentialProviderConfig != vc.Config {
+		provider, err := NewCredentialProvider(vc.Config)
+		if err != nil {
+			credentialsLock.Unlock()
+			return nil, err
+		}
+		vc.credentialProvider, vc.credentialProviderConfig = provider, vc.Config
+	}
+	provider := vc.credentialProvider
+	credentialsLock.Unlock()
+	return provider.GetCredentials(ctx)
+}
+
+// SetConfig replaces vc.Config with the given config, e.g. when the vSphere
+// config secret is reloaded. The config is swapped under credentialsLock as
+// the credential provider is cached for the config in use. The file and exec
+// credential sources do not set the username and password in the config, so
+// the ones resolved for the current session are carried over.
+func (vc *VirtualCenter) SetConfig(vcConfig *VirtualCenterConfig) {
+	credentialsLock.Lock()
+	if vcConfig != nil && vc.Config != nil && vcConfig.CredentialSource != "" &&
+		vcConfig.CredentialSource != config.CredentialSourceConfig {
+		vcConfig.Username, vcConfig.Password = vc.Config.Username, vc.Config.Password
+	}
+	vc.Config = vcConfig
+	credentialsLock.Unlock()
+	// Apply the reloaded rate limits to the current session.
+	if vcConfig != nil {
+		setRateLimits(vcConfig.Host, vcConfig.RateLimits, vcConfig.FullSyncYield)
+	}
+}
+
+// setSessionCredentials records the credentials of the current client session.
+// The username and password are also set in vc.Config as they are used for
+// privilege checks and container cluster metadata. Token-only credentials do
+// not carry a username, so the configured one is kept for them.
+func (vc *VirtualCenter) setSessionCredentials(creds *Credentials) {
+	credentialsLock.Lock()
+	defer credentialsLock.Unlock()
+	vc.sessionCredentials = creds
+	if creds.Username != "" {
+		vc.Config.Username, vc.Config.Password = creds.Username, creds.Password
+	}
+}
+
+// setSessionUsername sets the username of a session logged in using a token in
+// vc.Config, as token-only credentials do not carry a username.
+func (vc *VirtualCenter) setSessionUsername(username string) {
+	credentialsLock.Lock()
+	defer credentialsLock.Unlock()
+	vc.Config.Username = username
+}
+
+// getSessionCredentials returns the credentials of the current client session.
+func (vc *VirtualCenter) getSessionCredentials() *Credentials {
+	credentialsLock.Lock()
+	defer credentialsLock.Unlock()
+	if vc.sessionCredentials != nil {
+		return vc.sessionCredentials
+	}
+	return &Credentials{Username: vc.Config.Username, Password: vc.Config.Password}
+}
+
+// SetCredentialRejectedHandler sets the handler which is invoked when vCenter
+// rejects rotated credentials. Passing nil removes the handler.
+func SetCredentialRejectedHandler(handler CredentialRejectedHandler) {
+	credentialRejectedHandlerLock.Lock()
+	defer credentialRejectedHandlerLock.Unlock()
+	credentialRejectedHandler = handler
+}
+
+// RotateCredentials logs in a new client session using the credentials
+// returned by the provider, if they differ from the credentials in use. The new
+// client is swapped in only once vCenter accepts the credentials, and the
+// previous session is logged out after sessionLogoutGracePeriod, so the requests
+// in flight keep using a valid session. If vCenter rejects the new credentials,
+// the current session is kept and an error is returned.
+func (vc *VirtualCenter) RotateCredentials(ctx context.Context, provider CredentialProvider) error {
+	log := logger.GetLogger(ctx)
+	creds, err := provider.GetCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials for vCenter %q: %w", vc.Config.Host, err)
+	}
+
+	vc.ClientMutex.Lock()
+	if *vc.getSessionCredentials() == *creds {
+		vc.setSessionCredentials(creds)
+		vc.ClientMutex.Unlock()
+		log.Debugf("credentials for vCenter %q are unchanged", vc.Config.Host)
+		return nil
+	}
+	if vc.Client == nil {
+		// The new credentials will be used when the client is created.
+		vc.setSessionCredentials(creds)
+		vc.ClientMutex.Unlock()
+		return nil
+	}
+	useragent := vc.Client.UserAgent
+	vc.ClientMutex.Unlock()
+
+	log.Infof("Rotating credentials for vCenter %q", vc.Config.Host)
+	client, err := vc.newClient(ctx, useragent, creds)
+	if err != nil {
+		prometheus.VcenterCredentialRotationsCounterVec.WithLabelValues(vc.Config.Host,
+			prometheus.PrometheusFailStatus).Inc()
+		log.Errorf("vCenter %q rejected the rotated credentials. Keeping the current session. err: %v",
+			vc.Config.Host, err)
+		notifyCredentialRejected(ctx, vc.Config.Host, err)
+		return fmt.Errorf("vCenter %q rejected the rotated credentials: %w", vc.Config.Host, err)
+	}
+	prometheus.VcenterCredentialRotationsCounterVec.WithLabelValues(vc.Config.Host,
+		prometheus.PrometheusPassStatus).Inc()
+
+	vc.ClientMutex.Lock()
+	oldClient := vc.Client
+	vc.Client = client
+	vc.setSessionCredentials(creds)
+	err = vc.recreateServiceClients(ctx)
+	vc.ClientMutex.Unlock()
+	if oldClient != nil {
+		host := vc.Config.Host
+		logoutCtx := context.WithoutCancel(ctx)
+		time.AfterFunc(sessionLogoutGracePeriod, func() {
+			if logoutErr := oldClient.Logout(logoutCtx); logoutErr != nil {
+				log.Warnf("failed to logout previous session of vCenter %q. err: %v", host, logoutErr)
+				return
+			}
+			log.Infof("Logged out previous session of vCenter %q", host)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("Successfully rotated credentials for vCenter %q", vc.Config.Host)
+	return nil
+}
+
+// IsCredentialOnlyChange returns true if newConfig differs from oldConfig at
+// most in the credentials and their source. Such a reload only needs to rotate
+// the credentials of the existing session.
+func IsCredentialOnlyChange(oldConfig, newConfig *VirtualCenterConfig) bool {
+	if oldConfig == nil || newConfig == nil {
+		return false
+	}
+	oldCopy, newCopy := *oldConfig, *newConfig
+	for _, vcConfig := range []*VirtualCenterConfig{&oldCopy, &newCopy} {
+		vcConfig.Username, vcConfig.Password = "", ""
+		vcConfig.CredentialSource, vcConfig.UserFile, vcConfig.PasswordFile = "", "", ""
+		vcConfig.CredentialExecCommand, vcConfig.CredentialExecArgs = "", nil
+		vcConfig.ReloadVCConfigForNewClient = false
+	}
+	return reflect.DeepEqual(oldCopy, newCopy)
+}
+
+func notifyCredentialRejected(ctx context.Context, vcHost string, err error) {
+	credentialRejectedHandlerLock.RLock()
+	handler := credentialRejectedHandler
+	credentialRejectedHandlerLock.RUnlock()
+	if handler != nil {
+		handler(ctx, vcHost, err)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"crypto/tls"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+)
+
+func TestRotateCredentials(t *testing.T) {
+	ctx := context.Background()
+	model := simulator.VPX()
+	defer model.Remove()
+	require.NoError(t, model.Create())
+	model.Service.TLS = new(tls.Config)
+	// Set a non-default login so that the simulator validates logins using
+	// ValidLogin.
+	model.Service.Listen = &url.URL{User: url.UserPassword("user1", "password1")}
+	s := model.Service.NewServer()
+	defer s.Close()
+
+	validPasswords := map[string]string{"user1": "password1", "user2": "password2"}
+	model.Map().SessionManager().ValidLogin = func(req *types.Login) bool {
+		return validPasswords[req.UserName] == req.Password
+	}
+	port, err := strconv.Atoi(s.URL.Port())
+	require.NoError(t, err)
+	vc := &VirtualCenter{
+		Config: &VirtualCenterConfig{
+			Host:     s.URL.Hostname(),
+			Port:     port,
+			Username: "user1",
+			Password: "password1",
+			Insecure: true,
+		},
+		ClientMutex: &sync.Mutex{},
+	}
+	vc.Client, err = vc.NewClient(ctx, "test")
+	require.NoError(t, err)
+	client := vc.Client
+	defer func(gracePeriod time.Duration) { sessionLogoutGracePeriod = gracePeriod }(sessionLogoutGracePeriod)
+	sessionLogoutGracePeriod = 200 * time.Millisecond
+
+	var rejectedHost string
+	SetCredentialRejectedHandler(func(ctx context.Context, vcHost string, err error) {
+		rejectedHost = vcHost
+	})
+	defer SetCredentialRejectedHandler(nil)
+
+	// Rotated credentials are used to login a new client, and the previous
+	// session is logged out after the grace period.
+	err = vc.RotateCredentials(ctx, NewConfigCredentialProvider(&VirtualCenterConfig{
+		Username: "user2", Password: "password2"}))
+	require.NoError(t, err)
+	assert.NotSame(t, client, vc.Client)
+	userSession, err := vc.Client.SessionManager.UserSession(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, userSession)
+	assert.Equal(t, "user2", userSession.UserName)
+	assert.Empty(t, rejectedHost)
+	oldSession, err := client.SessionManager.UserSession(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, oldSession)
+	assert.Eventually(t, func() bool {
+		oldSession, err := client.SessionManager.UserSession(ctx)
+		return err == nil && oldSession == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// Rejected credentials are reported and the current session is kept.
+	client = vc.Client
+	err = vc.RotateCredentials(ctx, NewConfigCredentialProvider(&VirtualCenterConfig{
+		Username: "user2", Password: "invalid"}))
+	assert.Error(t, err)
+	assert.Equal(t, vc.Config.Host, rejectedHost)
+	assert.Equal(t, "password2", vc.Config.Password)
+	assert.Same(t, client, vc.Client)
+	userSession, err = vc.Client.SessionManager.UserSession(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, userSession)
+	assert.Equal(t, "user2", userSession.UserName)
+}
+
+func TestFileCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	userFile := filepath.Join(dir, "username")
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(userFile, []byte("user1@vsphere.local\n"), 0600))
+	require.NoError(t, os.WriteFile(passwordFile, []byte(" password1\n"), 0600))
+	provider, err := NewCredentialProvider(&VirtualCenterConfig{
+		CredentialSource: "file", UserFile: userFile, PasswordFile: passwordFile})
+	require.NoError(t, err)
+
+	creds, err := provider.GetCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "user1@vsphere.local", Password: " password1"}, creds)
+
+	// Rotated files are picked up on the next call.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("password2"), 0600))
+	creds, err = provider.GetCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "password2", creds.Password)
+
+	// Usernames without a domain are rejected.
+	require.NoError(t, os.WriteFile(userFile, []byte("user1"), 0600))
+	_, err = provider.GetCredentials(ctx)
+	assert.ErrorIs(t, err, config.ErrInvalidUsername)
+
+	require.NoError(t, os.WriteFile(userFile, nil, 0600))
+	_, err = provider.GetCredentials(ctx)
+	assert.Error(t, err)
+}
+
+func TestExecCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	countFile := filepath.Join(dir, "count")
+	plugin := filepath.Join(dir, "plugin.sh")
+	// The plugin records every invocation and returns credentials which expire
+	// after the given timestamp.
+	script := "#!/bin/sh\necho x >> " + countFile + "\n" +
+		`printf '{"username": "user@%s", "password": "secret", "expirationTimestamp": "%s"}' ` +
+		`"$` + EnvCredentialExecVCenterHost + `" "$1"` + "\n"
+	require.NoError(t, os.WriteFile(plugin, []byte(script), 0700))
+	invocations := func() int {
+		data, err := os.ReadFile(countFile)
+		require.NoError(t, err)
+		return len(data) / 2
+	}
+
+	expiry := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	provider, err := NewCredentialProvider(&VirtualCenterConfig{Host: "vc1", CredentialSource: "exec",
+		CredentialExecCommand: plugin, CredentialExecArgs: []string{expiry}})
+	require.NoError(t, err)
+	creds, err := provider.GetCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "user@vc1", Password: "secret"}, creds)
+	// Unexpired credentials are served from the cache.
+	_, err = provider.GetCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, invocations())
+
+	// Credentials expiring within the skew are refreshed on every call.
+	expiry = time.Now().Add(credentialExpirySkew / 2).UTC().Format(time.RFC3339)
+	provider, err = NewCredentialProvider(&VirtualCenterConfig{Host: "vc1", CredentialSource: "exec",
+		CredentialExecCommand: plugin, CredentialExecArgs: []string{expiry}})
+	require.NoError(t, err)
+	_, err = provider.GetCredentials(ctx)
+	require.NoError(t, err)
+	_, err = provider.GetCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, invocations())
+
+	_, err = NewCredentialProvider(&VirtualCenterConfig{CredentialSource: "exec"})
+	assert.Error(t, err)
+}
+
+func TestSessionCredentials(t *testing.T) {
+	ctx := context.Background()
+	vc := &VirtualCenter{Config: &VirtualCenterConfig{Username: "user1", Password: "password1"}}
+	creds, err := vc.getCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "user1", Password: "password1"}, creds)
+
+	// Token-only credentials keep the configured username.
+	vc.setSessionCredentials(&Credentials{Token: "token"})
+	assert.Equal(t, "user1", vc.Config.Username)
+	assert.Equal(t, &Credentials{Token: "token"}, vc.getSessionCredentials())
+
+	// The credential provider follows the config set.
+	vc.SetConfig(&VirtualCenterConfig{Username: "user2", Password: "password2"})
+	creds, err = vc.getCredentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "user2", Password: "password2"}, creds)
+
+	// The username resolved from a file or exec credential source is kept
+	// when the config is reloaded.
+	vc.SetConfig(&VirtualCenterConfig{CredentialSource: "exec", CredentialExecCommand: "/bin/get-credentials"})
+	assert.Equal(t, "user2", vc.Config.Username)
+	assert.Equal(t, "password2", vc.Config.Password)
+}
+
+func TestIsCredentialOnlyChange(t *testing.T) {
+	oldConfig := &VirtualCenterConfig{Host: "vc1", Username: "user1", Password: "password1",
+		DatacenterPaths: []string{"dc1"}}
+	newConfig := &VirtualCenterConfig{Host: "vc1", CredentialSource: "file", UserFile: "/etc/user",
+		PasswordFile: "/etc/password", DatacenterPaths: []string{"dc1"}, ReloadVCConfigForNewClient: true}
+	assert.True(t, IsCredentialOnlyChange(oldConfig, newConfig))
+
+	newConfig.DatacenterPaths = []string{"dc1", "dc2"}
+	assert.False(t, IsCredentialOnlyChange(oldConfig, newConfig))
+	assert.False(t, IsCredentialOnlyChange(nil, newConfig))
+}
@@ -19,11 +19,15 @@ package vsphere
 import (
 	"context"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 )
 
@@ -58,3 +62,116 @@ func (ds *Datastore) GetDatastoreURLAndType(ctx context.Context) (string, string
 	}
 	return dsMo.Summary.Url, dsMo.Summary.Type, nil
 }
+
+// ListVirtualDiskPaths returns the sorted datastore paths of the virtual disks
+// in the given folder of the datastore and its sub folders, e.g.
+// "[vsanDatastore] folder/disk.vmdk". All the virtual disks of the datastore
+// are returned if folder is empty.
+func (ds *Datastore) ListVirtualDiskPaths(ctx context.Context, folder string) ([]string, error) {
+	log := logger.GetLogger(ctx)
+	dsName, err := ds.ObjectName(ctx)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to get the name of datastore %v. err: %v",
+			ds.Reference(), err)
+	}
+	browser, err := ds.Browser(ctx)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to get the browser of datastore %q. err: %v", dsName, err)
+	}
+	searchSpec := &types.HostDatastoreBrowserSearchSpec{
+		MatchPattern: []string{"*.vmdk"},
+		Query:        []types.BaseFileQuery{&types.VmDiskFileQuery{}},
+	}
+	folderPath := object.DatastorePath{Datastore: dsName, Path: folder}
+	task, err := browser.SearchDatastoreSubFolders(ctx, folderPath.String(), searchSpec)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to search the virtual disks in %q. err: %v",
+			folderPath.String(), err)
+	}
+	taskInfo, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to search the virtual disks in %q. err: %v",
+			folderPath.String(), err)
+	}
+	results, ok := taskInfo.Result.(types.ArrayOfHostDatastoreBrowserSearchResults)
+	if !ok {
+		return nil, logger.LogNewErrorf(log, "unexpected result %T of the search in %q",
+			taskInfo.Result, folderPath.String())
+	}
+	var diskPaths []string
+	for _, result := range results.HostDatastoreBrowserSearchResults {
+		var resultPath object.DatastorePath
+		if !resultPath.FromString(result.FolderPath) {
+			log.Warnf("ignoring the search result with invalid folder path %q", result.FolderPath)
+			continue
+		}
+		for _, file := range result.File {
+			diskPath := object.DatastorePath{
+				Datastore: resultPath.Datastore,
+				Path:      strings.TrimPrefix(path.Join(resultPath.Path, file.GetFileInfo().Path), "/"),
+			}
+			diskPaths = append(diskPaths, diskPath.String())
+		}
+	}
+	sort.Strings(diskPaths)
+	log.Debugf("Found %d virtual disks in %q", len(diskPaths), folderPath.String())
+	return diskPaths, nil
+}
+
+// ListFirstClassDisks returns the FCDs of the datastore.
+func (ds *Datastore) ListFirstClassDisks(ctx context.Context) ([]*types.VStorageObject, error) {
+	log := logger.GetLogger(ctx)
+	objectManager := vslm.NewObjectManager(ds.Client())
+	ids, err := objectManager.List(ctx, ds.Datastore)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to list the FCDs of datastore %v. err: %v",
+			ds.Reference(), err)
+	}
+	vStorageObjects := make([]*types.VStorageObject, 0, len(ids))
+	for _, id := range ids {
+		vStorageObject, err := objectManager.Retrieve(ctx, ds.Datastore, id.Id)
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to retrieve FCD %q of datastore %v. err: %v",
+				id.Id, ds.Reference(), err)
+		}
+		vStorageObjects = append(vStorageObjects, vStorageObject)
+	}
+	log.Debugf("Found %d FCDs in datastore %v", len(vStorageObjects), ds.Reference())
+	return vStorageObjects, nil
+}
+
+// GetAttachedVirtualDiskPaths returns the datastore paths of the virtual disks
+// attached to the VMs using the datastore, mapped to the name of their VM.
+func (ds *Datastore) GetAttachedVirtualDiskPaths(ctx context.Context) (map[string]string, error) {
+	log := logger.GetLogger(ctx)
+	var dsMo mo.Datastore
+	pc := property.DefaultCollector(ds.Client())
+	err := pc.RetrieveOne(ctx, ds.Datastore.Reference(), []string{"vm"}, &dsMo)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to retrieve the VMs of datastore %v. err: %v",
+			ds.Reference(), err)
+	}
+	diskPaths := make(map[string]string)
+	if len(dsMo.Vm) == 0 {
+		return diskPaths, nil
+	}
+	var vmMos []mo.VirtualMachine
+	err = pc.Retrieve(ctx, dsMo.Vm, []string{"name", "config.hardware.device"}, &vmMos)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to retrieve the devices of the VMs of datastore %v. err: %v",
+			ds.Reference(), err)
+	}
+	for _, vmMo := range vmMos {
+		if vmMo.Config == nil {
+			continue
+		}
+		for _, device := range object.VirtualDeviceList(vmMo.Config.Hardware.Device).SelectByType(
+			(*types.VirtualDisk)(nil)) {
+			backing, ok := device.GetVirtualDevice().Backing.(types.BaseVirtualDeviceFileBackingInfo)
+			if ok {
+				diskPaths[backing.GetVirtualDeviceFileBackingInfo().FileName] = vmMo.Name
+			}
+		}
+	}
+	return diskPaths, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
+)
+
+func TestListVirtualDiskPaths(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+		dc, err := finder.DefaultDatacenter(ctx)
+		require.NoError(t, err)
+		finder.SetDatacenter(dc)
+		datastore, err := finder.Datastore(ctx, "LocalDS_0")
+		require.NoError(t, err)
+		var dsMo mo.Datastore
+		require.NoError(t, datastore.Properties(ctx, datastore.Reference(), []string{"info"}, &dsMo))
+
+		// The vcsim datastores are backed by a local directory.
+		root := dsMo.Info.GetDatastoreInfo().Url
+		for _, file := range []string{"legacy/app/app_1.vmdk", "legacy/app/app_1-flat.vmdk", "legacy/db.vmdk",
+			"legacy/notes.txt", "other/other.vmdk"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(file)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, file), nil, 0600))
+		}
+
+		ds := &Datastore{Datastore: datastore}
+		diskPaths, err := ds.ListVirtualDiskPaths(ctx, "legacy")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"[LocalDS_0] legacy/app/app_1.vmdk", "[LocalDS_0] legacy/db.vmdk"}, diskPaths)
+
+		diskPaths, err = ds.ListVirtualDiskPaths(ctx, "")
+		require.NoError(t, err)
+		assert.Contains(t, diskPaths, "[LocalDS_0] legacy/db.vmdk")
+		assert.Contains(t, diskPaths, "[LocalDS_0] other/other.vmdk")
+
+		_, err = ds.ListVirtualDiskPaths(ctx, "missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestListFirstClassDisksAndAttachedVirtualDiskPaths(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+		dc, err := finder.DefaultDatacenter(ctx)
+		require.NoError(t, err)
+		finder.SetDatacenter(dc)
+		datastore, err := finder.Datastore(ctx, "LocalDS_0")
+		require.NoError(t, err)
+		ds := &Datastore{Datastore: datastore}
+
+		fcds, err := ds.ListFirstClassDisks(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, fcds)
+
+		task, err := vslm.NewObjectManager(c).CreateDisk(ctx, types.VslmCreateSpec{
+			Name:         "fcd",
+			CapacityInMB: 10,
+			BackingSpec: &types.VslmCreateSpecDiskFileBackingSpec{
+				VslmCreateSpecBackingSpec: types.VslmCreateSpecBackingSpec{
+					Datastore: datastore.Reference(),
+				},
+			},
+		})
+		require.NoError(t, err)
+		_, err = task.WaitForResult(ctx, nil)
+		require.NoError(t, err)
+		fcds, err = ds.ListFirstClassDisks(ctx)
+		require.NoError(t, err)
+		require.Len(t, fcds, 1)
+		assert.Equal(t, "fcd", fcds[0].Config.Name)
+		assert.Equal(t, int64(10), fcds[0].Config.CapacityInMB)
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		require.NoError(t, err)
+		devices, err := vm.Device(ctx)
+		require.NoError(t, err)
+		disks := devices.SelectByType((*types.VirtualDisk)(nil))
+		require.NotEmpty(t, disks)
+		diskPath := disks[0].GetVirtualDevice().Backing.(types.BaseVirtualDeviceFileBackingInfo).
+			GetVirtualDeviceFileBackingInfo().FileName
+		attachedDiskPaths, err := ds.GetAttachedVirtualDiskPaths(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "DC0_H0_VM0", attachedDiskPaths[diskPath])
+	})
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+)
+
+// HealthState is the state of the connection to a vCenter.
+type HealthState string
+
+const (
+	// HealthStateHealthy means that the last connection to the vCenter succeeded.
+	HealthStateHealthy HealthState = "healthy"
+	// HealthStateDegraded means that the last connections to the vCenter failed,
+	// but not enough of them to open the circuit breaker.
+	HealthStateDegraded HealthState = "degraded"
+	// HealthStateDown means that the circuit breaker of the vCenter is open.
+	// The connections fail fast with ErrVCenterUnavailable, except the probes
+	// which are sent on backoff until one of them succeeds.
+	HealthStateDown HealthState = "down"
+
+	// healthFailureThreshold is the number of consecutive connection failures
+	// which open the circuit breaker.
+	healthFailureThreshold = 3
+	// healthInitialProbeBackoff and healthMaxProbeBackoff bound the time between
+	// two probes of a vCenter whose circuit breaker is open.
+	healthInitialProbeBackoff = 10 * time.Second
+	healthMaxProbeBackoff     = 5 * time.Minute
+)
+
+// ErrVCenterUnavailable is returned when connecting to a vCenter whose circuit
+// breaker is open.
+var ErrVCenterUnavailable = errors.New("vCenter is unavailable")
+
+// vCenterUnavailableKey is the context key for the flag which is set when a
+// connection fails fast with ErrVCenterUnavailable.
+type vCenterUnavailableKey struct{}
+
+// WithVCenterUnavailableTracking returns a copy of ctx in which the connections
+// failing fast with ErrVCenterUnavailable are recorded, and a function which
+// reports whether any did. It detects these failures even when the error is
+// reformatted without wrapping by the callers of Connect.
+func WithVCenterUnavailableTracking(ctx context.Context) (context.Context, func() bool) {
+	unavailable := &atomic.Bool{}
+	return context.WithValue(ctx, vCenterUnavailableKey{}, unavailable), unavailable.Load
+}
+
+// markVCenterUnavailable records in ctx that a connection failed fast with
+// ErrVCenterUnavailable.
+func markVCenterUnavailable(ctx context.Context) {
+	if unavailable, ok := ctx.Value(vCenterUnavailableKey{}).(*atomic.Bool); ok {
+		unavailable.Store(true)
+	}
+}
+
+// VCenterHealth is the health of the connection to a vCenter.
+type VCenterHealth struct {
+	// State is the current state of the connection.
+	State HealthState
+	// Since is the time of the last change of State.
+	Since time.Time
+	// LastError is the error of the last failed connection, if the vCenter is
+	// not healthy.
+	LastError error
+}
+
+// HealthChangedHandler is invoked when the health state of the connection to
+// a vCenter changes.
+type HealthChangedHandler func(ctx context.Context, vcHost string, health VCenterHealth)
+
+// healthTracker is the circuit breaker of the connections to a vCenter.
+type healthTracker struct {
+	host string
+	mu   sync.Mutex
+	// health is the current health of the vCenter.
+	health VCenterHealth
+	// failures is the number of consecutive connection failures.
+	failures int
+	// backoff is the time between the probes while the breaker is open.
+	backoff time.Duration
+	// nextProbe is the time after which the next probe can be sent.
+	nextProbe time.Time
+	// probing is true while a probe is in progress.
+	probing bool
+	// prober is true while a goroutine probes the vCenter in the background.
+	prober bool
+}
+
+var (
+	// healthTrackers holds the healthTracker of each vCenter host.
+	healthTrackers = make(map[string]*healthTracker)
+	// healthTrackersLock protects healthTrackers.
+	healthTrackersLock sync.Mutex
+	// healthChangedHandler is invoked when the health state of a vCenter
+	// changes.
+	healthChangedHandler HealthChangedHandler
+	// healthChangedHandlerLock protects healthChangedHandler.
+	healthChangedHandlerLock sync.RWMutex
+)
+
+// SetHealthChangedHandler sets the handler which is invoked when the health
+// state of the connection to a vCenter changes, so that the process running
+// the circuit breaker can publish its own view of the vCenters. Passing nil
+// removes the handler.
+func SetHealthChangedHandler(handler HealthChangedHandler) {
+	healthChangedHandlerLock.Lock()
+	defer healthChangedHandlerLock.Unlock()
+	healthChangedHandler = handler
+}
+
+// getHealthTracker returns the healthTracker of the vCenter host, creating a
+// healthy one if it does not exist.
+func getHealthTracker(host string) *healthTracker {
+	healthTrackersLock.Lock()
+	defer healthTrackersLock.Unlock()
+	tracker, ok := healthTrackers[host]
+	if !ok {
+		tracker = &healthTracker{
+			host:   host,
+			health: VCenterHealth{State: HealthStateHealthy, Since: time.Now()},
+		}
+		healthTrackers[host] = tracker
+		tracker.updateMetric()
+	}
+	return tracker
+}
+
+// GetVCenterHealth returns the health of the vCenters the driver has connected
+// to, keyed by host.
+func GetVCenterHealth() map[string]VCenterHealth {
+	healthTrackersLock.Lock()
+	defer healthTrackersLock.Unlock()
+	health := make(map[string]VCenterHealth, len(healthTrackers))
+	for host, tracker := range healthTrackers {
+		tracker.mu.Lock()
+		health[host] = tracker.health
+		tracker.mu.Unlock()
+	}
+	return health
+}
+
+// IsVCenterDown returns true if the circuit breaker of the vCenter host is
+// open.
+func IsVCenterDown(host string) bool {
+	healthTrackersLock.Lock()
+	tracker, ok := healthTrackers[host]
+	healthTrackersLock.Unlock()
+	if !ok {
+		return false
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return tracker.health.State == HealthStateDown
+}
+
+// AllVCentersDown returns true if the driver has connected to at least one
+// vCenter and the circuit breakers of all of them are open.
+func AllVCentersDown() bool {
+	health := GetVCenterHealth()
+	for _, h := range health {
+		if h.State != HealthStateDown {
+			return false
+		}
+	}
+	return len(health) > 0
+}
+
+// allow returns ErrVCenterUnavailable if the breaker is open and it is not
+// yet time to probe the vCenter. Otherwise, the connection can be attempted
+// and its result must be reported using record.
+func (t *healthTracker) allow() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.health.State != HealthStateDown {
+		return nil
+	}
+	if t.probing || time.Now().Before(t.nextProbe) {
+		return fmt.Errorf("%w: %s is down since %s. Last error: %v", ErrVCenterUnavailable,
+			t.host, t.health.Since.Format(time.RFC3339), t.health.LastError)
+	}
+	t.probing = true
+	return nil
+}
+
+// record updates the state of the vCenter with the result of a connection.
+// It returns true if the breaker was opened by this connection.
+func (t *healthTracker) record(ctx context.Context, err error) bool {
+	log := logger.GetLogger(ctx)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+	if err == nil {
+		t.failures = 0
+		t.backoff = 0
+		t.setState(ctx, HealthStateHealthy, nil)
+		return false
+	}
+	// The connections cancelled by the caller and the failed logins, which show
+	// that the vCenter is reachable, do not count as failures. The connections
+	// which exceeded their deadline count, as an unresponsive vCenter causes them.
+	if errors.Is(ctx.Err(), context.Canceled) || IsInvalidLoginError(ctx, err) {
+		return false
+	}
+	t.failures++
+	if t.failures < healthFailureThreshold {
+		t.setState(ctx, HealthStateDegraded, err)
+		return false
+	}
+	opened := t.health.State != HealthStateDown
+	if opened {
+		t.backoff = healthInitialProbeBackoff
+	} else {
+		t.backoff = min(2*t.backoff, healthMaxProbeBackoff)
+	}
+	t.nextProbe = time.Now().Add(t.backoff)
+	t.setState(ctx, HealthStateDown, err)
+	log.Infof("Next probe of vCenter %q in %v", t.host, t.backoff)
+	return opened
+}
+
+// setState sets the state of the vCenter. The caller must hold t.mu.
+func (t *healthTracker) setState(ctx context.Context, state HealthState, err error) {
+	log := logger.GetLogger(ctx)
+	t.health.LastError = err
+	if t.health.State == state {
+		return
+	}
+	log.Infof("vCenter %q health changed from %q to %q after %d consecutive failures. Last error: %v",
+		t.host, t.health.State, state, t.failures, err)
+	t.health.State = state
+	t.health.Since = time.Now()
+	t.updateMetric()
+	healthChangedHandlerLock.RLock()
+	handler := healthChangedHandler
+	healthChangedHandlerLock.RUnlock()
+	if handler != nil {
+		handler(ctx, t.host, t.health)
+	}
+}
+
+// updateMetric reports the state of the vCenter. The caller must hold t.mu
+// unless t is not shared yet.
+func (t *healthTracker) updateMetric() {
+	for _, state := range []HealthState{HealthStateHealthy, HealthStateDegraded, HealthStateDown} {
+		value := 0.0
+		if t.health.State == state {
+			value = 1
+		}
+		prometheus.VcenterHealthStateGaugeVec.WithLabelValues(t.host, string(state)).Set(value)
+	}
+}
+
+// startProber starts a goroutine which probes the vCenter on backoff until it
+// is healthy again, so that the breaker is closed even if no caller connects
+// to the vCenter in the meantime.
+func (vc *VirtualCenter) startProber(tracker *healthTracker) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if tracker.prober {
+		return
+	}
+	tracker.prober = true
+	go func() {
+		defer func() {
+			tracker.mu.Lock()
+			tracker.prober = false
+			tracker.mu.Unlock()
+		}()
+		for {
+			tracker.mu.Lock()
+			state, wait := tracker.health.State, time.Until(tracker.nextProbe)
+			tracker.mu.Unlock()
+			if state != HealthStateDown {
+				return
+			}
+			time.Sleep(max(wait, time.Second))
+			ctx, log := logger.GetNewContextWithLogger()
+			if err := vc.Connect(ctx); err != nil && !errors.Is(err, ErrVCenterUnavailable) {
+				log.Infof("Probe of vCenter %q failed. Error: %v", tracker.host, err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthTrackerBreaker(t *testing.T) {
+	ctx := context.Background()
+	host := "health-breaker.example.com"
+	tracker := getHealthTracker(host)
+	connErr := errors.New("connection refused")
+
+	// The breaker opens after healthFailureThreshold consecutive failures.
+	for i := 1; i < healthFailureThreshold; i++ {
+		require.NoError(t, tracker.allow())
+		assert.False(t, tracker.record(ctx, connErr))
+		assert.Equal(t, HealthStateDegraded, GetVCenterHealth()[host].State)
+	}
+	require.NoError(t, tracker.allow())
+	assert.True(t, tracker.record(ctx, connErr))
+	assert.True(t, IsVCenterDown(host))
+	assert.ErrorIs(t, tracker.allow(), ErrVCenterUnavailable)
+
+	// A single probe is allowed once the backoff has elapsed, and a failed probe
+	// doubles the backoff.
+	tracker.mu.Lock()
+	tracker.nextProbe = time.Now()
+	tracker.mu.Unlock()
+	require.NoError(t, tracker.allow())
+	assert.ErrorIs(t, tracker.allow(), ErrVCenterUnavailable)
+	assert.False(t, tracker.record(ctx, connErr))
+	assert.Equal(t, 2*healthInitialProbeBackoff, tracker.backoff)
+
+	// A successful probe closes the breaker.
+	tracker.mu.Lock()
+	tracker.nextProbe = time.Now()
+	tracker.mu.Unlock()
+	require.NoError(t, tracker.allow())
+	assert.False(t, tracker.record(ctx, nil))
+	assert.False(t, IsVCenterDown(host))
+	assert.Equal(t, HealthStateHealthy, GetVCenterHealth()[host].State)
+	assert.NoError(t, tracker.allow())
+}
+
+func TestHealthTrackerIgnoresCancelledConnections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tracker := getHealthTracker("health-cancelled.example.com")
+	for i := 0; i < healthFailureThreshold; i++ {
+		assert.False(t, tracker.record(ctx, ctx.Err()))
+	}
+	assert.Equal(t, HealthStateHealthy, tracker.health.State)
+
+	// The connections which exceeded their deadline count as failures.
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	assert.False(t, tracker.record(ctx, ctx.Err()))
+	assert.Equal(t, HealthStateDegraded, tracker.health.State)
+}
+
+func TestVCenterUnavailableTracking(t *testing.T) {
+	host := "health-tracking.example.com"
+	tracker := getHealthTracker(host)
+	for i := 0; i < healthFailureThreshold; i++ {
+		tracker.record(context.Background(), errors.New("connection refused"))
+	}
+	ctx, unavailable := WithVCenterUnavailableTracking(context.Background())
+	assert.False(t, unavailable())
+
+	vc := &VirtualCenter{Config: &VirtualCenterConfig{Host: host}}
+	err := vc.Connect(ctx)
+	assert.ErrorIs(t, err, ErrVCenterUnavailable)
+	assert.True(t, unavailable())
+}
+
+func TestHealthChangedHandler(t *testing.T) {
+	ctx := context.Background()
+	host := "health-handler.example.com"
+	var states []HealthState
+	SetHealthChangedHandler(func(ctx context.Context, vcHost string, health VCenterHealth) {
+		if vcHost == host {
+			states = append(states, health.State)
+		}
+	})
+	defer SetHealthChangedHandler(nil)
+
+	tracker := getHealthTracker(host)
+	connErr := errors.New("connection refused")
+	for i := 0; i < healthFailureThreshold; i++ {
+		tracker.record(ctx, connErr)
+	}
+	tracker.record(ctx, nil)
+	assert.Equal(t, []HealthState{HealthStateDegraded, HealthStateDown, HealthStateHealthy}, states)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
+)
+
+// RequestLane is a class of vCenter requests whose rate is limited separately
+// from the other classes, so that a burst of requests of one class does not
+// delay the requests of the others.
+type RequestLane string
+
+const (
+	// RequestLaneAttach is the lane of the volume attach and detach requests.
+	RequestLaneAttach RequestLane = "attach"
+	// RequestLaneProvision is the lane of the requests creating, deleting or
+	// updating volumes and snapshots.
+	RequestLaneProvision RequestLane = "provision"
+	// RequestLaneQuery is the lane of the other requests.
+	RequestLaneQuery RequestLane = "query"
+	// RequestLaneFullSync is the lane of the requests made by the full sync of
+	// the syncer, whatever their kind.
+	RequestLaneFullSync RequestLane = "fullsync"
+)
+
+// RateLimit is the token bucket configuration of a request lane.
+type RateLimit struct {
+	// QPS is the number of requests per second allowed in the lane. 0 means
+	// that the requests of the lane are not limited.
+	QPS float64
+	// Burst is the number of requests which can be sent at once. The rounded
+	// up QPS is used if it is not set.
+	Burst int
+}
+
+var (
+	// requestLanes maps the names of the requests which are not query requests
+	// to their lane.
+	requestLanes = map[string]RequestLane{
+		"CnsAttachVolume":         RequestLaneAttach,
+		"CnsDetachVolume":         RequestLaneAttach,
+		"CnsCreateVolume":         RequestLaneProvision,
+		"CnsDeleteVolume":         RequestLaneProvision,
+		"CnsExtendVolume":         RequestLaneProvision,
+		"CnsUpdateVolumeMetadata": RequestLaneProvision,
+		"CnsRelocateVolume":       RequestLaneProvision,
+		"CnsReconfigVolumePolicy": RequestLaneProvision,
+		"CnsUnregisterVolume":     RequestLaneProvision,
+		"CnsCreateSnapshots":      RequestLaneProvision,
+		"CnsDeleteSnapshots":      RequestLaneProvision,
+	}
+	// unlimitedRequests are the session management and property collector
+	// long-poll requests, which are never rate limited so that the session is
+	// kept alive and the completion of the CNS tasks is tracked without delay.
+	unlimitedRequests = map[string]bool{
+		"Login":                  true,
+		"LoginByToken":           true,
+		"Logout":                 true,
+		"SessionIsActive":        true,
+		"RetrieveServiceContent": true,
+		"WaitForUpdatesEx":       true,
+		"CancelWaitForUpdates":   true,
+	}
+)
+
+type requestLaneKey struct{}
+
+// WithRequestLane returns a child context whose vCenter requests are rate
+// limited in the given lane, whatever their kind.
+func WithRequestLane(ctx context.Context, lane RequestLane) context.Context {
+	return context.WithValue(ctx, requestLaneKey{}, lane)
+}
+
+// getRequestLane returns the lane of the request, or an empty lane if the
+// request is not rate limited.
+func getRequestLane(ctx context.Context, requestName string) RequestLane {
+	if unlimitedRequests[requestName] {
+		return ""
+	}
+	if lane, ok := ctx.Value(requestLaneKey{}).(RequestLane); ok {
+		return lane
+	}
+	if lane, ok := requestLanes[requestName]; ok {
+		return lane
+	}
+	return RequestLaneQuery
+}
+
+// rateLimiter limits the rate of the requests sent to a vCenter by this
+// process, with one token bucket per lane. The controller and the syncer run
+// in separate containers, so each of them has its own token buckets.
+type rateLimiter struct {
+	host string
+	mu   sync.Mutex
+	// limiters holds the token bucket of the limited lanes.
+	limiters map[RequestLane]*rate.Limiter
+	// fullSyncYield makes the full sync requests wait until no other request
+	// of this process is waiting for a token. The requests of the controller
+	// are not seen by the full sync of the syncer.
+	fullSyncYield bool
+	// waiting is the number of requests other than full sync requests waiting
+	// for a token in this process.
+	waiting int
+	// idle is closed when waiting drops to 0.
+	idle chan struct{}
+}
+
+var (
+	// rateLimiters holds the rateLimiter of each vCenter host.
+	rateLimiters = make(map[string]*rateLimiter)
+	// rateLimitersLock protects rateLimiters.
+	rateLimitersLock sync.Mutex
+)
+
+// setRateLimits sets the rate limits of the requests sent to the vCenter
+// host. The requests of the lanes missing from limits are not limited. The
+// token buckets of the lanes which were already limited are updated in place,
+// so that a config reload does not refill them.
+func setRateLimits(host string, limits map[RequestLane]RateLimit, fullSyncYield bool) {
+	rateLimitersLock.Lock()
+	defer rateLimitersLock.Unlock()
+	r, ok := rateLimiters[host]
+	if !ok {
+		r = &rateLimiter{host: host}
+		rateLimiters[host] = r
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limiters := make(map[RequestLane]*rate.Limiter)
+	for lane, limit := range limits {
+		if limit.QPS <= 0 {
+			continue
+		}
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(limit.QPS))
+		}
+		if limiter, ok := r.limiters[lane]; ok {
+			limiter.SetLimit(rate.Limit(limit.QPS))
+			limiter.SetBurst(burst)
+			limiters[lane] = limiter
+			continue
+		}
+		limiters[lane] = rate.NewLimiter(rate.Limit(limit.QPS), burst)
+	}
+	r.limiters = limiters
+	r.fullSyncYield = fullSyncYield
+}
+
+// getRateLimiter returns the rateLimiter of the vCenter host, or nil if no
+// rate limits are set for it.
+func getRateLimiter(host string) *rateLimiter {
+	rateLimitersLock.Lock()
+	defer rateLimitersLock.Unlock()
+	return rateLimiters[host]
+}
+
+// wait blocks until a request of the lane can be sent or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context, lane RequestLane) error {
+	r.mu.Lock()
+	limiter := r.limiters[lane]
+	var idle chan struct{}
+	if lane == RequestLaneFullSync && r.fullSyncYield && r.waiting > 0 {
+		idle = r.idle
+	}
+	if limiter == nil && idle == nil {
+		r.mu.Unlock()
+		return nil
+	}
+	if lane != RequestLaneFullSync {
+		r.waiting++
+		if r.waiting == 1 {
+			r.idle = make(chan struct{})
+		}
+	}
+	r.mu.Unlock()
+
+	queued := prometheus.VcenterRequestsQueuedGaugeVec.WithLabelValues(r.host, string(lane))
+	queued.Inc()
+	waitStart := time.Now()
+	defer func() {
+		queued.Dec()
+		prometheus.VcenterRequestWaitHistVec.WithLabelValues(r.host, string(lane)).
+			Observe(time.Since(waitStart).Seconds())
+		if lane != RequestLaneFullSync {
+			r.mu.Lock()
+			r.waiting--
+			if r.waiting == 0 {
+				close(r.idle)
+			}
+			r.mu.Unlock()
+		}
+	}()
+
+	// The full sync requests yield to the other requests of this process until
+	// none of them is waiting for a token.
+	for idle != nil {
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		r.mu.Lock()
+		idle = nil
+		if r.waiting > 0 {
+			idle = r.idle
+		}
+		r.mu.Unlock()
+	}
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
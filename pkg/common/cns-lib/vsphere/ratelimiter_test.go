@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+)
+
+func TestGetRequestLane(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, RequestLaneAttach, getRequestLane(ctx, "CnsAttachVolume"))
+	assert.Equal(t, RequestLaneProvision, getRequestLane(ctx, "CnsCreateVolume"))
+	assert.Equal(t, RequestLaneQuery, getRequestLane(ctx, "CnsQueryVolume"))
+	assert.Equal(t, RequestLane(""), getRequestLane(ctx, "WaitForUpdatesEx"))
+
+	fullSyncCtx := WithRequestLane(ctx, RequestLaneFullSync)
+	assert.Equal(t, RequestLaneFullSync, getRequestLane(fullSyncCtx, "CnsUpdateVolumeMetadata"))
+	assert.Equal(t, RequestLane(""), getRequestLane(fullSyncCtx, "SessionIsActive"))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	host := "ratelimiter-wait.example.com"
+	setRateLimits(host, map[RequestLane]RateLimit{
+		RequestLaneQuery: {QPS: 1, Burst: 1},
+	}, false)
+	limiter := getRateLimiter(host)
+	require.NotNil(t, limiter)
+	ctx := context.Background()
+
+	// The burst is sent at once, the next query request waits for a token
+	// while the unlimited lanes are not delayed.
+	require.NoError(t, limiter.wait(ctx, RequestLaneQuery))
+	require.NoError(t, limiter.wait(ctx, RequestLaneAttach))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	assert.Error(t, limiter.wait(timeoutCtx, RequestLaneQuery))
+
+	// Disabling the limit unblocks the lane.
+	setRateLimits(host, nil, false)
+	assert.NoError(t, limiter.wait(ctx, RequestLaneQuery))
+}
+
+func TestRateLimiterFullSyncYield(t *testing.T) {
+	host := "ratelimiter-yield.example.com"
+	setRateLimits(host, map[RequestLane]RateLimit{
+		RequestLaneAttach: {QPS: 5, Burst: 1},
+	}, true)
+	limiter := getRateLimiter(host)
+	require.NotNil(t, limiter)
+	ctx := context.Background()
+	require.NoError(t, limiter.wait(ctx, RequestLaneAttach))
+
+	// The full sync request is sent only after the queued attach request.
+	attachDone := make(chan struct{})
+	go func() {
+		defer close(attachDone)
+		assert.NoError(t, limiter.wait(ctx, RequestLaneAttach))
+	}()
+	assert.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.waiting == 1
+	}, 5*time.Second, time.Millisecond)
+	require.NoError(t, limiter.wait(ctx, RequestLaneFullSync))
+	select {
+	case <-attachDone:
+	default:
+		t.Fatal("full sync request was sent before the queued attach request")
+	}
+}
+
+func TestRateLimitsReload(t *testing.T) {
+	host := "ratelimiter-reload.example.com"
+	vc := &VirtualCenter{}
+	vc.SetConfig(&VirtualCenterConfig{Host: host, RateLimits: map[RequestLane]RateLimit{
+		RequestLaneAttach: {QPS: 1, Burst: 1},
+	}})
+	limiter := getRateLimiter(host)
+	require.NotNil(t, limiter)
+	attachLimiter := limiter.limiters[RequestLaneAttach]
+	require.NotNil(t, attachLimiter)
+
+	// A reloaded config updates the token bucket of the lane in place.
+	vc.SetConfig(&VirtualCenterConfig{Host: host, RateLimits: map[RequestLane]RateLimit{
+		RequestLaneAttach: {QPS: 10, Burst: 5},
+	}, FullSyncYield: true})
+	assert.Same(t, attachLimiter, limiter.limiters[RequestLaneAttach])
+	assert.Equal(t, rate.Limit(10), attachLimiter.Limit())
+	assert.Equal(t, 5, attachLimiter.Burst())
+	assert.True(t, limiter.fullSyncYield)
+
+	vc.SetConfig(&VirtualCenterConfig{Host: host})
+	assert.Empty(t, limiter.limiters)
+}
+
+func TestGetRateLimits(t *testing.T) {
+	assert.Nil(t, getRateLimits(&config.VirtualCenterConfig{}))
+	// The full sync lane gets its own bucket with the query limits by default.
+	assert.Equal(t, map[RequestLane]RateLimit{
+		RequestLaneQuery:    {QPS: 5, Burst: 10},
+		RequestLaneFullSync: {QPS: 5, Burst: 10},
+	}, getRateLimits(&config.VirtualCenterConfig{QueryQPS: 5, QueryBurst: 10}))
+	assert.Equal(t, map[RequestLane]RateLimit{
+		RequestLaneAttach:   {QPS: 20},
+		RequestLaneQuery:    {QPS: 5, Burst: 10},
+		RequestLaneFullSync: {QPS: 1},
+	}, getRateLimits(&config.VirtualCenterConfig{AttachQPS: 20, QueryQPS: 5, QueryBurst: 10, FullSyncQPS: 1}))
+}
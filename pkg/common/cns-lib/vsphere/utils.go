@@ -195,12 +195,24 @@ func GetVirtualCenterConfig(ctx context.Context, cfg *config.Config) (*VirtualCe
 		Thumbprint:                  vcThumbprint,
 		Username:                    cfg.VirtualCenter[host].User,
 		Password:                    cfg.VirtualCenter[host].Password,
+		CredentialSource:            cfg.VirtualCenter[host].CredentialSource,
+		UserFile:                    cfg.VirtualCenter[host].UserFile,
+		PasswordFile:                cfg.VirtualCenter[host].PasswordFile,
+		CredentialExecCommand:       cfg.VirtualCenter[host].CredentialExecCommand,
+		CredentialExecArgs:          getCredentialExecArgs(cfg.VirtualCenter[host].CredentialExecArgs),
 		Insecure:                    cfg.VirtualCenter[host].InsecureFlag,
 		TargetvSANFileShareClusters: targetvSANClustersForFile,
 		QueryLimit:                  cfg.Global.QueryLimit,
 		ListVolumeThreshold:         cfg.Global.ListVolumeThreshold,
 		MigrationDataStoreURL:       cfg.VirtualCenter[host].MigrationDataStoreURL,
 		FileVolumeActivated:         cfg.VirtualCenter[host].FileVolumeActivated,
+		CreateVolumeTimeoutInSec:    cfg.Global.CreateVolumeTimeoutInSec,
+		DeleteVolumeTimeoutInSec:    cfg.Global.DeleteVolumeTimeoutInSec,
+		AttachVolumeTimeoutInSec:    cfg.Global.AttachVolumeTimeoutInSec,
+		DetachVolumeTimeoutInSec:    cfg.Global.DetachVolumeTimeoutInSec,
+		ExpandVolumeTimeoutInSec:    cfg.Global.ExpandVolumeTimeoutInSec,
+		RateLimits:                  getRateLimits(cfg.VirtualCenter[host]),
+		FullSyncYield:               cfg.VirtualCenter[host].FullSyncYield,
 	}
 
 	log.Debugf("Setting the queryLimit = %v, ListVolumeThreshold = %v", vcConfig.QueryLimit, vcConfig.ListVolumeThreshold)
@@ -242,11 +254,23 @@ func GetVirtualCenterConfigs(ctx context.Context, cfg *config.Config) ([]*Virtua
 			Thumbprint:                  cfg.VirtualCenter[vCenterIP].Thumbprint,
 			Username:                    cfg.VirtualCenter[vCenterIP].User,
 			Password:                    cfg.VirtualCenter[vCenterIP].Password,
+			CredentialSource:            cfg.VirtualCenter[vCenterIP].CredentialSource,
+			UserFile:                    cfg.VirtualCenter[vCenterIP].UserFile,
+			PasswordFile:                cfg.VirtualCenter[vCenterIP].PasswordFile,
+			CredentialExecCommand:       cfg.VirtualCenter[vCenterIP].CredentialExecCommand,
+			CredentialExecArgs:          getCredentialExecArgs(cfg.VirtualCenter[vCenterIP].CredentialExecArgs),
 			Insecure:                    cfg.VirtualCenter[vCenterIP].InsecureFlag,
 			TargetvSANFileShareClusters: targetvSANClustersForFile,
 			QueryLimit:                  cfg.Global.QueryLimit,
 			ListVolumeThreshold:         cfg.Global.ListVolumeThreshold,
 			FileVolumeActivated:         cfg.VirtualCenter[vCenterIP].FileVolumeActivated,
+			CreateVolumeTimeoutInSec:    cfg.Global.CreateVolumeTimeoutInSec,
+			DeleteVolumeTimeoutInSec:    cfg.Global.DeleteVolumeTimeoutInSec,
+			AttachVolumeTimeoutInSec:    cfg.Global.AttachVolumeTimeoutInSec,
+			DetachVolumeTimeoutInSec:    cfg.Global.DetachVolumeTimeoutInSec,
+			ExpandVolumeTimeoutInSec:    cfg.Global.ExpandVolumeTimeoutInSec,
+			RateLimits:                  getRateLimits(cfg.VirtualCenter[vCenterIP]),
+			FullSyncYield:               cfg.VirtualCenter[vCenterIP].FullSyncYield,
 		}
 		if vcConfig.CAFile == "" {
 			vcConfig.CAFile = cfg.Global.CAFile
@@ -266,6 +290,32 @@ func GetVirtualCenterConfigs(ctx context.Context, cfg *config.Config) ([]*Virtua
 	return VirtualCenterConfigs, nil
 }
 
+// getRateLimits returns the rate limits of the request lanes which are limited
+// in the vCenter configuration, or nil if no lane is limited. The full sync
+// lane has its own token bucket, with the query limits if it has none set.
+func getRateLimits(vcConfig *config.VirtualCenterConfig) map[RequestLane]RateLimit {
+	var rateLimits map[RequestLane]RateLimit
+	fullSyncLimit := RateLimit{QPS: vcConfig.FullSyncQPS, Burst: vcConfig.FullSyncBurst}
+	if fullSyncLimit.QPS <= 0 {
+		fullSyncLimit = RateLimit{QPS: vcConfig.QueryQPS, Burst: vcConfig.QueryBurst}
+	}
+	for lane, limit := range map[RequestLane]RateLimit{
+		RequestLaneAttach:    {QPS: vcConfig.AttachQPS, Burst: vcConfig.AttachBurst},
+		RequestLaneProvision: {QPS: vcConfig.ProvisionQPS, Burst: vcConfig.ProvisionBurst},
+		RequestLaneQuery:     {QPS: vcConfig.QueryQPS, Burst: vcConfig.QueryBurst},
+		RequestLaneFullSync:  fullSyncLimit,
+	} {
+		if limit.QPS <= 0 {
+			continue
+		}
+		if rateLimits == nil {
+			rateLimits = make(map[RequestLane]RateLimit)
+		}
+		rateLimits[lane] = limit
+	}
+	return rateLimits
+}
+
 // GetVcenterIPs returns list of vCenter IPs from VSphereConfig.
 func GetVcenterIPs(cfg *config.Config) ([]string, error) {
 	var err error
@@ -307,14 +357,18 @@ func CompareKubernetesMetadata(ctx context.Context, k8sMetaData *cnstypes.CnsKub
 	return labelsMatch
 }
 
-// Signer decodes the certificate and private key and returns SAML token needed
-// for authentication.
-func signer(ctx context.Context, client *vim25.Client, username string, password string) (*sts.Signer, error) {
-	pemBlock, _ := pem.Decode([]byte(username))
+// Signer returns the SAML token from the credentials, or decodes the
+// certificate and private key and returns SAML token needed for
+// authentication.
+func signer(ctx context.Context, client *vim25.Client, creds *Credentials) (*sts.Signer, error) {
+	if creds.Token != "" {
+		return &sts.Signer{Token: creds.Token}, nil
+	}
+	pemBlock, _ := pem.Decode([]byte(creds.Username))
 	if pemBlock == nil {
 		return nil, nil
 	}
-	certificate, err := tls.X509KeyPair([]byte(username), []byte(password))
+	certificate, err := tls.X509KeyPair([]byte(creds.Username), []byte(creds.Password))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load X509 key pair. Error: %+v", err)
 	}
@@ -342,12 +396,16 @@ func GetTagManager(ctx context.Context, vc *VirtualCenter) (*tags.Manager, error
 	}
 
 	restClient := rest.NewClient(vc.Client.Client)
-	signer, err := signer(ctx, vc.Client.Client, vc.Config.Username, vc.Config.Password)
+	creds, err := vc.getCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the credentials. Error: %v", err)
+	}
+	signer, err := signer(ctx, vc.Client.Client, creds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the Signer. Error: %v", err)
 	}
 	if signer == nil {
-		user := url.UserPassword(vc.Config.Username, vc.Config.Password)
+		user := url.UserPassword(creds.Username, creds.Password)
 		err = restClient.Login(ctx, user)
 	} else {
 		err = restClient.LoginByToken(restClient.WithSigner(ctx, signer))
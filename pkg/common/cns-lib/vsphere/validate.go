@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+)
+
+const (
+	// Checks run by ValidateVirtualCenters.
+	ValidationCheckConnection = "connection"
+	ValidationCheckPrivileges = "privileges"
+
+	// validationLoginTimeout bounds the single login attempt made to each
+	// vCenter by ValidateVirtualCenters.
+	validationLoginTimeout = 30 * time.Second
+)
+
+var (
+	// rootFolderPrivileges are the privileges the CSI user needs on the root
+	// folder of the vCenter to query the volumes and the storage policies.
+	rootFolderPrivileges = []string{"Cns.Searchable", "StorageProfile.View"}
+	// datacenterPrivileges are the privileges the CSI user needs on the
+	// datacenters.
+	datacenterPrivileges = []string{"System.Read"}
+)
+
+// ValidateVirtualCenters connects to the vCenters of cfg and checks that the
+// configured datacenters and topology categories exist and that the CSI user
+// has the privileges needed by the driver. The issues found are added to the
+// report. The connections are not shared with the driver, and each vCenter is
+// logged in once without the retries and the circuit breaker of Connect, so
+// that an unreachable vCenter or invalid credentials are reported quickly.
+func ValidateVirtualCenters(ctx context.Context, cfg *config.Config, report *config.ValidationReport) {
+	vcConfigs, err := GetVirtualCenterConfigs(ctx, cfg)
+	if err != nil {
+		report.AddError(ValidationCheckConnection, "", "failed to get the vCenter configs: %v", err)
+		return
+	}
+	var categories []string
+	if zone, region := strings.TrimSpace(cfg.Labels.Zone), strings.TrimSpace(cfg.Labels.Region); zone != "" {
+		categories = append(categories, zone)
+		if region != "" {
+			categories = append(categories, region)
+		}
+	} else if strings.TrimSpace(cfg.Labels.TopologyCategories) != "" {
+		for _, category := range strings.Split(cfg.Labels.TopologyCategories, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	useragent, err := config.GetSessionUserAgent(ctx)
+	if err != nil {
+		report.AddError(ValidationCheckConnection, "", "failed to get the session user agent: %v", err)
+		return
+	}
+	for _, vcConfig := range vcConfigs {
+		validateVirtualCenter(ctx, &VirtualCenter{Config: vcConfig, ClientMutex: &sync.Mutex{}}, useragent,
+			categories, report)
+	}
+}
+
+// validateVirtualCenter runs the checks of ValidateVirtualCenters on vc.
+func validateVirtualCenter(ctx context.Context, vc *VirtualCenter, useragent string, categories []string,
+	report *config.ValidationReport) {
+	section := config.VirtualCenterSection(vc.Config.Host)
+	if err := loginOnce(ctx, vc, useragent); err != nil {
+		report.AddError(ValidationCheckConnection, section, "failed to connect to vCenter: %v", err)
+		return
+	}
+	defer func() {
+		_ = vc.Disconnect(ctx)
+	}()
+
+	var datacenters []*Datacenter
+	if len(vc.Config.DatacenterPaths) != 0 {
+		for _, dcPath := range vc.Config.DatacenterPaths {
+			dcs, err := vc.getDatacenters(ctx, []string{dcPath})
+			if err != nil {
+				report.AddError(config.ValidationCheckDatacenters, section,
+					"datacenter %q is not found: %v", dcPath, err)
+				continue
+			}
+			datacenters = append(datacenters, dcs...)
+		}
+	} else {
+		dcList, err := find.NewFinder(vc.Client.Client, false).DatacenterList(ctx, "*")
+		if err != nil {
+			report.AddError(config.ValidationCheckDatacenters, section, "failed to list the datacenters: %v", err)
+		} else if len(dcList) == 0 {
+			report.AddError(config.ValidationCheckDatacenters, section, "no datacenter is found")
+		}
+		for _, dcObj := range dcList {
+			datacenters = append(datacenters, &Datacenter{Datacenter: dcObj, VirtualCenterHost: vc.Config.Host})
+		}
+	}
+
+	if len(categories) > 0 {
+		tagManager, err := GetTagManager(ctx, vc)
+		if err != nil {
+			report.AddError(config.ValidationCheckTopologyCategories, section,
+				"failed to create the tag manager: %v", err)
+		} else {
+			for _, category := range categories {
+				if _, err := tagManager.GetCategory(ctx, category); err != nil {
+					report.AddError(config.ValidationCheckTopologyCategories, section,
+						"topology category %q is not found: %v", category, err)
+				}
+			}
+		}
+	}
+
+	validatePrivileges(ctx, vc, section, datacenters, report)
+}
+
+// loginOnce logs in a new client of vc with a single attempt bounded by
+// validationLoginTimeout.
+func loginOnce(ctx context.Context, vc *VirtualCenter, useragent string) error {
+	loginCtx, cancel := context.WithTimeout(ctx, validationLoginTimeout)
+	defer cancel()
+	creds, err := vc.getCredentials(loginCtx)
+	if err != nil {
+		return err
+	}
+	client, err := vc.newClient(loginCtx, useragent, creds)
+	if err != nil {
+		return err
+	}
+	vc.Client = client
+	vc.setSessionCredentials(creds)
+	return nil
+}
+
+// validatePrivileges checks that the user of the session of vc has the
+// privileges needed by the driver on the root folder and the datacenters.
+func validatePrivileges(ctx context.Context, vc *VirtualCenter, section string, datacenters []*Datacenter,
+	report *config.ValidationReport) {
+	userSession, err := vc.Client.SessionManager.UserSession(ctx)
+	if err != nil || userSession == nil {
+		report.AddError(ValidationCheckPrivileges, section, "failed to get the user session: %v", err)
+		return
+	}
+	authMgr := object.NewAuthorizationManager(vc.Client.Client)
+	check := func(entities []types.ManagedObjectReference, names []string, privileges []string) {
+		result, err := authMgr.HasUserPrivilegeOnEntities(ctx, entities, userSession.UserName, privileges)
+		if err != nil {
+			report.AddError(ValidationCheckPrivileges, section, "failed to check privileges %v of user %q: %v",
+				privileges, userSession.UserName, err)
+			return
+		}
+		for i, entityPrivilege := range result {
+			for _, privilege := range entityPrivilege.PrivAvailability {
+				if !privilege.IsGranted {
+					report.AddError(ValidationCheckPrivileges, section, "user %q does not have privilege %q on %s",
+						userSession.UserName, privilege.PrivId, names[i])
+				}
+			}
+		}
+	}
+	check([]types.ManagedObjectReference{vc.Client.ServiceContent.RootFolder}, []string{"the root folder"},
+		rootFolderPrivileges)
+	if len(datacenters) == 0 {
+		return
+	}
+	entities := make([]types.ManagedObjectReference, 0, len(datacenters))
+	names := make([]string, 0, len(datacenters))
+	for _, dc := range datacenters {
+		entities = append(entities, dc.Reference())
+		names = append(names, "datacenter "+dc.InventoryPath)
+	}
+	check(entities, names, datacenterPrivileges)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vsphere
+
+import (
+	"context"
+	"crypto/tls"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/simulator"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+)
+
+func TestValidateVirtualCenterLogin(t *testing.T) {
+	ctx := context.Background()
+	model := simulator.VPX()
+	defer model.Remove()
+	require.NoError(t, model.Create())
+	model.Service.TLS = new(tls.Config)
+	model.Service.Listen = &url.URL{User: url.UserPassword("user1", "password1")}
+	s := model.Service.NewServer()
+	defer s.Close()
+	port, err := strconv.Atoi(s.URL.Port())
+	require.NoError(t, err)
+	newVC := func(password string) *VirtualCenter {
+		return &VirtualCenter{
+			Config: &VirtualCenterConfig{
+				Host:     s.URL.Hostname(),
+				Port:     port,
+				Username: "user1",
+				Password: password,
+				Insecure: true,
+			},
+			ClientMutex: &sync.Mutex{},
+		}
+	}
+
+	// Invalid credentials are reported at once, without the login retries of
+	// Connect, and do not change the health of the vCenter.
+	healthBefore := GetVCenterHealth()[s.URL.Hostname()]
+	report := &config.ValidationReport{Valid: true}
+	start := time.Now()
+	validateVirtualCenter(ctx, newVC("invalid"), "test", nil, report)
+	assert.Less(t, time.Since(start), validationLoginTimeout)
+	assert.False(t, report.Valid)
+	require.NotEmpty(t, report.Findings)
+	assert.Equal(t, ValidationCheckConnection, report.Findings[0].Check)
+	assert.Equal(t, healthBefore, GetVCenterHealth()[s.URL.Hostname()])
+
+	report = &config.ValidationReport{Valid: true}
+	validateVirtualCenter(ctx, newVC("password1"), "test", nil, report)
+	assert.True(t, report.Valid, "unexpected findings: %+v", report.Findings)
+}
@@ -43,8 +43,11 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"github.com/vmware/govmomi/vsan"
 	"github.com/vmware/govmomi/vslm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/tracing"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
@@ -88,11 +91,21 @@ type VirtualCenter struct {
 	VslmClient *vslm.Client
 	// ClientMutex is used for exclusive connection creation.
 	ClientMutex *sync.Mutex
+
+	// credentialProvider provides the credentials for credentialProviderConfig.
+	credentialProvider       CredentialProvider
+	credentialProviderConfig *VirtualCenterConfig
+	// sessionCredentials are the credentials of the current client session.
+	// The credential fields are protected by credentialsLock.
+	sessionCredentials *Credentials
 }
 
 type MetricRoundTripper struct {
 	roundTripper soap.RoundTripper
 	clientName   string
+	// vcHost is the vCenter host the requests are sent to, whose rate limits
+	// apply to the requests.
+	vcHost string
 }
 
 var (
@@ -124,6 +137,17 @@ type VirtualCenterConfig struct {
 	Username string
 	// Password represents the virtual center password in clear text.
 	Password string
+	// CredentialSource specifies where the credentials are read from. The
+	// Username and Password are used when it is empty or "config".
+	CredentialSource string
+	// UserFile and PasswordFile represent the files from which the credentials
+	// are read when CredentialSource is "file".
+	UserFile     string
+	PasswordFile string
+	// CredentialExecCommand and CredentialExecArgs represent the command run to
+	// obtain the credentials when CredentialSource is "exec".
+	CredentialExecCommand string
+	CredentialExecArgs    []string
 	// Specifies the path to a CA certificate in PEM format. This has no effect
 	// if Insecure is enabled. Optional; if not configured, the system's CA
 	// certificates will be used.
@@ -150,6 +174,21 @@ type VirtualCenterConfig struct {
 	// ListVolumeThreshold specifies the maximum number of differences in volume that
 	// can exist between CNS and kubernetes
 	ListVolumeThreshold int
+	// CreateVolumeTimeoutInSec, DeleteVolumeTimeoutInSec, AttachVolumeTimeoutInSec,
+	// DetachVolumeTimeoutInSec and ExpandVolumeTimeoutInSec specify the maximum time
+	// to wait for the CNS task of the corresponding volume operation. 0 means no
+	// timeout other than the deadline of the CSI operation.
+	CreateVolumeTimeoutInSec int
+	DeleteVolumeTimeoutInSec int
+	AttachVolumeTimeoutInSec int
+	DetachVolumeTimeoutInSec int
+	ExpandVolumeTimeoutInSec int
+	// RateLimits holds the rate limits of the requests sent to the virtual
+	// center, by lane. The requests of the lanes without a limit are not limited.
+	RateLimits map[RequestLane]RateLimit
+	// FullSyncYield makes the full sync requests wait until no other request
+	// of this process is waiting for its rate limit.
+	FullSyncYield bool
 	// Specifies whether to verify the server's certificate chain. Set to true to
 	// skip verification.
 	Insecure bool
@@ -162,6 +201,24 @@ type VirtualCenterConfig struct {
 
 // NewClient creates a new govmomi Client instance.
 func (vc *VirtualCenter) NewClient(ctx context.Context, useragent string) (*govmomi.Client, error) {
+	log := logger.GetLogger(ctx)
+	creds, err := vc.getCredentials(ctx)
+	if err != nil {
+		log.Errorf("failed to get credentials for vc. err: %v", err)
+		return nil, err
+	}
+	client, err := vc.newClient(ctx, useragent, creds)
+	if err != nil {
+		return nil, err
+	}
+	vc.setSessionCredentials(creds)
+	return client, nil
+}
+
+// newClient creates a new govmomi Client instance logged in using the given
+// credentials.
+func (vc *VirtualCenter) newClient(ctx context.Context, useragent string,
+	creds *Credentials) (*govmomi.Client, error) {
 	log := logger.GetLogger(ctx)
 	if vc.Config.Scheme == "" {
 		vc.Config.Scheme = DefaultScheme
@@ -207,7 +264,7 @@ func (vc *VirtualCenter) NewClient(ctx context.Context, useragent string) (*govm
 		SessionManager: session.NewManager(vimClient),
 	}
 
-	err = vc.login(ctx, client)
+	err = vc.login(ctx, client, creds)
 	if err != nil {
 		log.Errorf("failed to login to vc. err: %v", err)
 		return nil, err
@@ -225,28 +282,43 @@ func (vc *VirtualCenter) NewClient(ctx context.Context, useragent string) (*govm
 		return nil, errors.New("nil session obtained from session manager")
 	}
 	log.Infof("New session ID for '%s' = %s", s.UserName, s.Key)
+	if creds.Username == "" {
+		// Use the user of the token login for the privilege checks.
+		vc.setSessionUsername(s.UserName)
+	}
 
 	if vc.Config.RoundTripperCount == 0 {
 		vc.Config.RoundTripperCount = DefaultRoundTripperCount
 	}
+	setRateLimits(vc.Config.Host, vc.Config.RateLimits, vc.Config.FullSyncYield)
+	if len(vc.Config.RateLimits) > 0 {
+		log.Infof("Rate limiting the requests to vCenter host %q: %+v, full sync yield: %t",
+			vc.Config.Host, vc.Config.RateLimits, vc.Config.FullSyncYield)
+	}
 	rt := vim25.Retry(client.RoundTripper, vim25.TemporaryNetworkError(vc.Config.RoundTripperCount))
-	client.RoundTripper = &MetricRoundTripper{clientName: "soap", roundTripper: rt}
+	client.RoundTripper = &MetricRoundTripper{clientName: "soap", roundTripper: rt, vcHost: vc.Config.Host}
 	return client, nil
 }
 
-// login calls SessionManager.LoginByToken if certificate and private key are
-// configured. Otherwise, calls SessionManager.Login with user and password.
-func (vc *VirtualCenter) login(ctx context.Context, client *govmomi.Client) error {
+// login calls SessionManager.LoginByToken if a SAML token, or certificate and
+// private key are provided. Otherwise, calls SessionManager.Login with user and
+// password.
+func (vc *VirtualCenter) login(ctx context.Context, client *govmomi.Client, creds *Credentials) error {
 	log := logger.GetLogger(ctx)
 	var err error
 
-	b, _ := pem.Decode([]byte(vc.Config.Username))
+	if creds.Token != "" {
+		header := soap.Header{Security: &sts.Signer{Token: creds.Token}}
+		return client.SessionManager.LoginByToken(client.Client.WithHeader(ctx, header))
+	}
+
+	b, _ := pem.Decode([]byte(creds.Username))
 	if b == nil {
 		return client.SessionManager.Login(ctx,
-			neturl.UserPassword(vc.Config.Username, vc.Config.Password))
+			neturl.UserPassword(creds.Username, creds.Password))
 	}
 
-	cert, err := tls.X509KeyPair([]byte(vc.Config.Username), []byte(vc.Config.Password))
+	cert, err := tls.X509KeyPair([]byte(creds.Username), []byte(creds.Password))
 	if err != nil {
 		log.Errorf("failed to load X509 key pair with err: %v", err)
 		return err
@@ -291,7 +363,25 @@ func (vc *VirtualCenter) cleanupVCClient(ctx context.Context) {
 // ensuring each retry happens after the previous lockout window expires.
 // For any other failure, it fails immediately without retry.
 // After each login attempt, the client is cleaned up to avoid resource leaks.
+// Once the connections to the vCenter have failed repeatedly, its circuit
+// breaker opens and Connect fails fast with ErrVCenterUnavailable until a probe
+// of the vCenter succeeds.
 func (vc *VirtualCenter) Connect(ctx context.Context) error {
+	tracker := getHealthTracker(vc.Config.Host)
+	if err := tracker.allow(); err != nil {
+		markVCenterUnavailable(ctx)
+		return err
+	}
+	err := vc.connectWithLoginRetry(ctx)
+	if tracker.record(ctx, err) {
+		vc.startProber(tracker)
+	}
+	return err
+}
+
+// connectWithLoginRetry connects to the vCenter, retrying the logins which fail
+// with an InvalidLogin error.
+func (vc *VirtualCenter) connectWithLoginRetry(ctx context.Context) error {
 	log := logger.GetLogger(ctx)
 
 	vc.ClientMutex.Lock()
@@ -422,15 +512,23 @@ func (vc *VirtualCenter) connect(ctx context.Context) error {
 		}
 		return err
 	}
-	// Recreate PbmClient if created using timed out VC Client.
+	// Recreate the service clients if created using timed out VC Client.
+	return vc.recreateServiceClients(ctx)
+}
+
+// recreateServiceClients recreates the PBM, CNS, Vslm and VSAN clients which
+// have already been created, so that they use the current session of vc.Client.
+func (vc *VirtualCenter) recreateServiceClients(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+	var err error
 	if vc.PbmClient != nil {
 		if vc.PbmClient, err = pbm.NewClient(ctx, vc.Client.Client); err != nil {
 			log.Errorf("failed to create pbm client with err: %v", err)
 			return err
 		}
-		vc.PbmClient.RoundTripper = &MetricRoundTripper{clientName: "pbm", roundTripper: vc.PbmClient.RoundTripper}
+		vc.PbmClient.RoundTripper = &MetricRoundTripper{clientName: "pbm",
+			roundTripper: vc.PbmClient.RoundTripper, vcHost: vc.Config.Host}
 	}
-	// Recreate CNSClient if created using timed out VC Client.
 	if vc.CnsClient != nil {
 		if vc.CnsClient, err = NewCnsClient(ctx, vc.Client.Client); err != nil {
 			log.Errorf("failed to create CNS client on vCenter host %v with err: %v",
@@ -438,7 +536,6 @@ func (vc *VirtualCenter) connect(ctx context.Context) error {
 			return err
 		}
 	}
-	// Recreate VslmClient if created using timed out VC Client.
 	if vc.VslmClient != nil {
 		if vc.VslmClient, err = NewVslmClient(ctx, vc.Client.Client); err != nil {
 			log.Errorf("failed to create Vslm client on vCenter host %v with err: %v",
@@ -446,13 +543,13 @@ func (vc *VirtualCenter) connect(ctx context.Context) error {
 			return err
 		}
 	}
-	// Recreate VSAN client if created using timed out VC Client.
 	if vc.VsanClient != nil {
 		if vc.VsanClient, err = vsan.NewClient(ctx, vc.Client.Client); err != nil {
 			log.Errorf("failed to create vsan client with err: %v", err)
 			return err
 		}
-		vc.VsanClient.RoundTripper = &MetricRoundTripper{clientName: "vsan", roundTripper: vc.VsanClient.RoundTripper}
+		vc.VsanClient.RoundTripper = &MetricRoundTripper{clientName: "vsan",
+			roundTripper: vc.VsanClient.RoundTripper, vcHost: vc.Config.Host}
 	}
 	return nil
 }
@@ -475,7 +572,7 @@ func ReadVCConfigs(ctx context.Context, vc *VirtualCenter) error {
 	for _, newvcconfig := range newVcenterConfigs {
 		if newvcconfig.Host == vc.Config.Host {
 			newvcconfig.ReloadVCConfigForNewClient = true
-			vc.Config = newvcconfig
+			vc.SetConfig(newvcconfig)
 			log.Infof("Successfully set latest VC config for vcenter: %q", vc.Config.Host)
 			foundVCConfig = true
 			break
@@ -851,8 +948,33 @@ func (vc *VirtualCenter) GetAllVirtualMachines(ctx context.Context,
 func (mrt *MetricRoundTripper) RoundTrip(ctx context.Context, req, resp soap.HasFault) error {
 	vreq := reflect.ValueOf(req).Elem().FieldByName("Req").Elem()
 	requestName := vreq.Type().Name()
+	// Only requests made as part of a traced operation are traced, so that
+	// background requests such as the ListView property collector polls do not
+	// create traces of their own.
+	var span trace.Span
+	if tracing.IsTraced(ctx) {
+		ctx, span = tracing.StartSpan(ctx, mrt.clientName+"."+requestName,
+			attribute.String("vsphere.client", mrt.clientName))
+		ctx = tracing.WithOpID(ctx)
+		if opID, ok := ctx.Value(types.ID{}).(string); ok {
+			span.SetAttributes(attribute.String("vsphere.opid", opID))
+		}
+	}
+	if limiter := getRateLimiter(mrt.vcHost); limiter != nil {
+		if lane := getRequestLane(ctx, requestName); lane != "" {
+			if err := limiter.wait(ctx, lane); err != nil {
+				if span != nil {
+					tracing.EndSpan(span, err)
+				}
+				return err
+			}
+		}
+	}
 	requestTime := time.Now()
 	err := mrt.roundTripper.RoundTrip(ctx, req, resp)
+	if span != nil {
+		tracing.EndSpan(span, err)
+	}
 	if err != nil {
 		timeTaken := time.Since(requestTime).Seconds()
 		prometheus.RequestOpsMetric.WithLabelValues(requestName, mrt.clientName, statusFailUnknown).Observe(timeTaken)
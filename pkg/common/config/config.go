@@ -110,6 +110,15 @@ const (
 	ClusterIDConfigMapName = "vsphere-csi-cluster-id"
 	// ClusterVersionv1beta1 refers to the api version of non-legacy cluster
 	ClusterVersionv1beta1 = "cluster.x-k8s.io/v1beta1"
+	// CredentialSourceConfig reads the vCenter credentials from the user and
+	// password set in the config. This is the default credential source.
+	CredentialSourceConfig = "config"
+	// CredentialSourceFile reads the vCenter credentials from the files set in
+	// user-file and password-file.
+	CredentialSourceFile = "file"
+	// CredentialSourceExec obtains the vCenter credentials by running the
+	// command set in credential-exec-command.
+	CredentialSourceExec = "exec"
 )
 
 // Errors
@@ -125,6 +134,19 @@ var (
 	// ErrPasswordMissing is returned when the provided password is empty.
 	ErrPasswordMissing = errors.New("password is missing")
 
+	// ErrInvalidCredentialSource is returned when the provided credential source
+	// is not supported.
+	ErrInvalidCredentialSource = errors.New("invalid credential-source, supported values are " +
+		"\"config\", \"file\" and \"exec\"")
+
+	// ErrCredentialFileMissing is returned when user-file or password-file is
+	// not provided for the "file" credential source.
+	ErrCredentialFileMissing = errors.New("user-file and password-file are required for credential-source \"file\"")
+
+	// ErrCredentialExecCommandMissing is returned when credential-exec-command is
+	// not provided for the "exec" credential source.
+	ErrCredentialExecCommandMissing = errors.New("credential-exec-command is required for credential-source \"exec\"")
+
 	// ErrInvalidVCenterIP is returned when the provided vCenter IP address is
 	// missing from the provided configuration.
 	ErrInvalidVCenterIP = errors.New("vsphere.conf does not have the VirtualCenter IP address specified")
@@ -226,6 +248,21 @@ func FromEnv(ctx context.Context, cfg *Config) error {
 	if v := os.Getenv("VSPHERE_PASSWORD"); v != "" {
 		cfg.Global.Password = v
 	}
+	if v := os.Getenv("VSPHERE_CREDENTIAL_SOURCE"); v != "" {
+		cfg.Global.CredentialSource = v
+	}
+	if v := os.Getenv("VSPHERE_USER_FILE"); v != "" {
+		cfg.Global.UserFile = v
+	}
+	if v := os.Getenv("VSPHERE_PASSWORD_FILE"); v != "" {
+		cfg.Global.PasswordFile = v
+	}
+	if v := os.Getenv("VSPHERE_CREDENTIAL_EXEC_COMMAND"); v != "" {
+		cfg.Global.CredentialExecCommand = v
+	}
+	if v := os.Getenv("VSPHERE_CREDENTIAL_EXEC_ARGS"); v != "" {
+		cfg.Global.CredentialExecArgs = v
+	}
 	if v := os.Getenv("VSPHERE_DATACENTER"); v != "" {
 		cfg.Global.Datacenters = v
 	}
@@ -344,6 +381,113 @@ func isValidvCenterUsernameWithDomain(username string) bool {
 	return match
 }
 
+// ValidateCredentialUsername returns ErrInvalidUsername if the username read
+// from a file or exec plugin credential source is not a fully qualified domain
+// name. Token logins without a username are allowed.
+func ValidateCredentialUsername(username string) error {
+	if username != "" && !isValidvCenterUsernameWithDomain(username) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
+
+// validateRateLimits validates the request rate limits of the vCenter.
+func validateRateLimits(ctx context.Context, vcServer string, vcConfig *VirtualCenterConfig) error {
+	log := logger.GetLogger(ctx)
+	for param, value := range map[string]float64{
+		"attach-qps":      vcConfig.AttachQPS,
+		"attach-burst":    float64(vcConfig.AttachBurst),
+		"provision-qps":   vcConfig.ProvisionQPS,
+		"provision-burst": float64(vcConfig.ProvisionBurst),
+		"query-qps":       vcConfig.QueryQPS,
+		"query-burst":     float64(vcConfig.QueryBurst),
+		"fullsync-qps":    vcConfig.FullSyncQPS,
+		"fullsync-burst":  float64(vcConfig.FullSyncBurst),
+	} {
+		if value < 0 {
+			return logger.LogNewErrorf(log, "invalid value %v for %s of vc %s. Value must not be negative.",
+				value, param, vcServer)
+		}
+	}
+	return nil
+}
+
+// validateCredentialSource validates the credentials of the given vCenter
+// based on its credential source. Settings which are not set for the vCenter
+// are inherited from the Global section.
+func validateCredentialSource(ctx context.Context, cfg *Config, vcServer string,
+	vcConfig *VirtualCenterConfig) error {
+	log := logger.GetLogger(ctx)
+	if vcConfig.CredentialSource == "" {
+		vcConfig.CredentialSource = cfg.Global.CredentialSource
+		if vcConfig.CredentialSource == "" {
+			vcConfig.CredentialSource = CredentialSourceConfig
+		}
+	}
+	switch vcConfig.CredentialSource {
+	case CredentialSourceConfig:
+		if vcConfig.User == "" {
+			vcConfig.User = cfg.Global.User
+			if vcConfig.User == "" {
+				log.Errorf("vcConfig.User is empty for vc %s!", vcServer)
+				return ErrUsernameMissing
+			}
+		}
+
+		// vCenter server username provided in vSphere config secret should contain domain name,
+		// CSI driver will crash if username doesn't contain domain name.
+		if !isValidvCenterUsernameWithDomain(vcConfig.User) {
+			log.Errorf("username %v specified in vSphere config secret is invalid, "+
+				"make sure that username is a fully qualified domain name.", vcConfig.User)
+			return ErrInvalidUsername
+		}
+
+		if vcConfig.Password == "" {
+			vcConfig.Password = cfg.Global.Password
+			if vcConfig.Password == "" {
+				log.Errorf("vcConfig.Password is empty for vc %s!", vcServer)
+				return ErrPasswordMissing
+			}
+		}
+	case CredentialSourceFile:
+		if vcConfig.UserFile == "" {
+			vcConfig.UserFile = cfg.Global.UserFile
+		}
+		if vcConfig.PasswordFile == "" {
+			vcConfig.PasswordFile = cfg.Global.PasswordFile
+		}
+		if vcConfig.UserFile == "" || vcConfig.PasswordFile == "" {
+			log.Errorf("user-file or password-file is empty for vc %s!", vcServer)
+			return ErrCredentialFileMissing
+		}
+		// The username is validated again whenever the file is read, as it
+		// may not be mounted yet.
+		if username, err := os.ReadFile(vcConfig.UserFile); err == nil {
+			if err := ValidateCredentialUsername(strings.TrimSpace(string(username))); err != nil {
+				log.Errorf("username %v in user-file %s is invalid for vc %s, "+
+					"make sure that username is a fully qualified domain name.",
+					strings.TrimSpace(string(username)), vcConfig.UserFile, vcServer)
+				return err
+			}
+		}
+	case CredentialSourceExec:
+		if vcConfig.CredentialExecCommand == "" {
+			vcConfig.CredentialExecCommand = cfg.Global.CredentialExecCommand
+			if vcConfig.CredentialExecArgs == "" {
+				vcConfig.CredentialExecArgs = cfg.Global.CredentialExecArgs
+			}
+		}
+		if vcConfig.CredentialExecCommand == "" {
+			log.Errorf("credential-exec-command is empty for vc %s!", vcServer)
+			return ErrCredentialExecCommandMissing
+		}
+	default:
+		log.Errorf("credential-source %q is invalid for vc %s", vcConfig.CredentialSource, vcServer)
+		return ErrInvalidCredentialSource
+	}
+	return nil
+}
+
 func validateConfig(ctx context.Context, cfg *Config) error {
 	log := logger.GetLogger(ctx)
 	// Fix default global values.
@@ -384,28 +528,11 @@ func validateConfig(ctx context.Context, cfg *Config) error {
 			return ErrInvalidVCenterIP
 		}
 
-		if vcConfig.User == "" {
-			vcConfig.User = cfg.Global.User
-			if vcConfig.User == "" {
-				log.Errorf("vcConfig.User is empty for vc %s!", vcServer)
-				return ErrUsernameMissing
-			}
-		}
-
-		// vCenter server username provided in vSphere config secret should contain domain name,
-		// CSI driver will crash if username doesn't contain domain name.
-		if !isValidvCenterUsernameWithDomain(vcConfig.User) {
-			log.Errorf("username %v specified in vSphere config secret is invalid, "+
-				"make sure that username is a fully qualified domain name.", vcConfig.User)
-			return ErrInvalidUsername
+		if err := validateCredentialSource(ctx, cfg, vcServer, vcConfig); err != nil {
+			return err
 		}
-
-		if vcConfig.Password == "" {
-			vcConfig.Password = cfg.Global.Password
-			if vcConfig.Password == "" {
-				log.Errorf("vcConfig.Password is empty for vc %s!", vcServer)
-				return ErrPasswordMissing
-			}
+		if err := validateRateLimits(ctx, vcServer, vcConfig); err != nil {
+			return err
 		}
 		if vcConfig.VCenterPort == "" {
 			vcConfig.VCenterPort = cfg.Global.VCenterPort
@@ -512,6 +639,18 @@ func validateConfig(ctx context.Context, cfg *Config) error {
 		cfg.Global.ListVolumeThreshold = DefaultListVolumeThreshold
 		log.Debugf("Setting default list volume threshold to %v", cfg.Global.ListVolumeThreshold)
 	}
+
+	for param, timeout := range map[string]int{
+		"create-volume-timeout-insec": cfg.Global.CreateVolumeTimeoutInSec,
+		"delete-volume-timeout-insec": cfg.Global.DeleteVolumeTimeoutInSec,
+		"attach-volume-timeout-insec": cfg.Global.AttachVolumeTimeoutInSec,
+		"detach-volume-timeout-insec": cfg.Global.DetachVolumeTimeoutInSec,
+		"expand-volume-timeout-insec": cfg.Global.ExpandVolumeTimeoutInSec,
+	} {
+		if timeout < 0 {
+			return logger.LogNewErrorf(log, "invalid value %d for %s. Value must not be negative.", timeout, param)
+		}
+	}
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -221,6 +222,62 @@ func TestValidateConfigWithValidUsername2(t *testing.T) {
 	}
 }
 
+func TestValidateConfigWithCredentialSources(t *testing.T) {
+	cfg := &Config{
+		VirtualCenter: map[string]*VirtualCenterConfig{
+			"1.1.1.1": {
+				VCenterPort:  "443",
+				Datacenters:  "dc1",
+				InsecureFlag: true,
+			},
+		},
+	}
+	cfg.Global.CredentialSource = CredentialSourceExec
+	cfg.Global.CredentialExecCommand = "/bin/get-credentials"
+	cfg.Global.CredentialExecArgs = "--audience,vcenter"
+	err := validateConfig(ctx, cfg)
+	if err != nil {
+		t.Errorf("Unexpected error, as valid credential source is specified. Error: %v", err)
+	}
+	if cfg.VirtualCenter["1.1.1.1"].CredentialExecCommand != "/bin/get-credentials" ||
+		cfg.VirtualCenter["1.1.1.1"].CredentialExecArgs != "--audience,vcenter" {
+		t.Errorf("Expected the credential exec plugin to be inherited from global config. Config given - %+v",
+			*cfg.VirtualCenter["1.1.1.1"])
+	}
+
+	cfg.VirtualCenter = map[string]*VirtualCenterConfig{
+		"1.1.1.1": {
+			CredentialSource: CredentialSourceFile,
+			UserFile:         "/etc/vsphere/username",
+			VCenterPort:      "443",
+			Datacenters:      "dc1",
+			InsecureFlag:     true,
+		},
+	}
+	err = validateConfig(ctx, cfg)
+	if err != ErrCredentialFileMissing {
+		t.Errorf("Expected error due to missing password file. Error: %v", err)
+	}
+
+	// The username of a readable user file must be a fully qualified domain name.
+	userFile := filepath.Join(t.TempDir(), "username")
+	if err := os.WriteFile(userFile, []byte("administrator\n"), 0600); err != nil {
+		t.Fatalf("failed to write user file. Error: %v", err)
+	}
+	cfg.VirtualCenter["1.1.1.1"].UserFile = userFile
+	cfg.VirtualCenter["1.1.1.1"].PasswordFile = "/etc/vsphere/password"
+	err = validateConfig(ctx, cfg)
+	if err != ErrInvalidUsername {
+		t.Errorf("Expected error due to username without domain in user file. Error: %v", err)
+	}
+
+	cfg.VirtualCenter["1.1.1.1"].CredentialSource = "vault"
+	err = validateConfig(ctx, cfg)
+	if err != ErrInvalidCredentialSource {
+		t.Errorf("Expected error due to invalid credential source. Error: %v", err)
+	}
+}
+
 func TestSensitiveConfigFieldsRedacted(t *testing.T) {
 	vc := VirtualCenterConfig{
 		User:         "Administrator@vsphere.local",
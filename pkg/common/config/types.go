@@ -61,6 +61,19 @@ type Config struct {
 		User string `gcfg:"user"`
 		// vCenter password in clear text.
 		Password string `gcfg:"password"`
+		// CredentialSource specifies where the vCenter credentials are read from.
+		// Supported values are "config" (default), "file" and "exec".
+		CredentialSource string `gcfg:"credential-source"`
+		// UserFile and PasswordFile specify the files from which the vCenter
+		// username and password are read when CredentialSource is "file".
+		UserFile     string `gcfg:"user-file"`
+		PasswordFile string `gcfg:"password-file"`
+		// CredentialExecCommand specifies the command which is run to obtain the
+		// vCenter credentials when CredentialSource is "exec".
+		CredentialExecCommand string `gcfg:"credential-exec-command"`
+		// CredentialExecArgs is a comma separated list of arguments passed to
+		// CredentialExecCommand.
+		CredentialExecArgs string `gcfg:"credential-exec-args"`
 		// vCenter port.
 		VCenterPort string `gcfg:"port"`
 		// Specifies whether to verify the server's certificate chain. Set to true to
@@ -101,10 +114,27 @@ type Config struct {
 		// ListVolumeThreshold specifies the maximum number of differences in volume that can exist between CNS
 		// and kubernetes
 		ListVolumeThreshold int `gcfg:"list-volume-threshold"`
+
+		// CreateVolumeTimeoutInSec, DeleteVolumeTimeoutInSec, AttachVolumeTimeoutInSec,
+		// DetachVolumeTimeoutInSec and ExpandVolumeTimeoutInSec specify the maximum time to wait
+		// for the CNS task of the corresponding volume operation. Once the timeout expires, the
+		// driver cancels the task if vCenter allows it and returns a TaskTimedOut fault. The next
+		// attempt of a create, delete or expand operation starts a new task if the timed out one was
+		// cancelled, and otherwise waits on it again. Attach and detach do not persist their tasks,
+		// so their next attempt always starts a new task.
+		// Default is 0, which waits until the deadline of the CSI operation.
+		CreateVolumeTimeoutInSec int `gcfg:"create-volume-timeout-insec"`
+		DeleteVolumeTimeoutInSec int `gcfg:"delete-volume-timeout-insec"`
+		AttachVolumeTimeoutInSec int `gcfg:"attach-volume-timeout-insec"`
+		DetachVolumeTimeoutInSec int `gcfg:"detach-volume-timeout-insec"`
+		ExpandVolumeTimeoutInSec int `gcfg:"expand-volume-timeout-insec"`
 	}
 
 	// Snapshot configurations.
 	Snapshot SnapshotConfig
+
+	// Metrics configurations.
+	Metrics MetricsConfig
 }
 
 // ConfigurationInfo is a struct that used to capture config param details
@@ -141,6 +171,19 @@ type VirtualCenterConfig struct {
 	User string `gcfg:"user" sensitive:"true"`
 	// vCenter password in clear text.
 	Password string `gcfg:"password" sensitive:"true"`
+	// CredentialSource specifies where the vCenter credentials are read from.
+	// Supported values are "config" (default), "file" and "exec".
+	CredentialSource string `gcfg:"credential-source"`
+	// UserFile and PasswordFile specify the files from which the vCenter
+	// username and password are read when CredentialSource is "file".
+	UserFile     string `gcfg:"user-file"`
+	PasswordFile string `gcfg:"password-file"`
+	// CredentialExecCommand specifies the command which is run to obtain the
+	// vCenter credentials when CredentialSource is "exec".
+	CredentialExecCommand string `gcfg:"credential-exec-command"`
+	// CredentialExecArgs is a comma separated list of arguments passed to
+	// CredentialExecCommand.
+	CredentialExecArgs string `gcfg:"credential-exec-args"`
 	// vCenter port.
 	VCenterPort string `gcfg:"port"`
 	// Specifies the path to a CA certificate in PEM format. This has no effect if
@@ -159,6 +202,27 @@ type VirtualCenterConfig struct {
 	MigrationDataStoreURL string `gcfg:"migration-datastore-url"`
 	// True if vCenter uses self-signed cert.
 	InsecureFlag bool `gcfg:"insecure-flag"`
+	// AttachQPS and AttachBurst limit the rate of the volume attach and detach
+	// requests sent to the vCenter. A QPS of 0 disables the limit.
+	AttachQPS   float64 `gcfg:"attach-qps"`
+	AttachBurst int     `gcfg:"attach-burst"`
+	// ProvisionQPS and ProvisionBurst limit the rate of the requests creating,
+	// deleting or updating volumes and snapshots.
+	ProvisionQPS   float64 `gcfg:"provision-qps"`
+	ProvisionBurst int     `gcfg:"provision-burst"`
+	// QueryQPS and QueryBurst limit the rate of the other requests.
+	QueryQPS   float64 `gcfg:"query-qps"`
+	QueryBurst int     `gcfg:"query-burst"`
+	// FullSyncQPS and FullSyncBurst limit the rate of the requests made by the
+	// full sync of the syncer. The query limits are used if FullSyncQPS is not
+	// set. The limits apply per container, so the controller and the syncer
+	// each send up to the limit of their lanes.
+	FullSyncQPS   float64 `gcfg:"fullsync-qps"`
+	FullSyncBurst int     `gcfg:"fullsync-burst"`
+	// FullSyncYield makes the full sync requests wait until no other request
+	// of the syncer is waiting for its rate limit. The requests of the
+	// controller do not delay the full sync.
+	FullSyncYield bool `gcfg:"fullsync-yield"`
 	// FileVolumeActivated indicates whether file service has been enabled on any vSAN cluster or not
 	FileVolumeActivated bool
 }
@@ -198,5 +262,19 @@ type SnapshotConfig struct {
 	GranularMaxSnapshotsPerBlockVolumeInVVOL int `gcfg:"granular-max-snapshots-per-block-volume-vvol"`
 }
 
+// MetricsConfig contains the configuration of the optional labels of the CSI
+// and CNS operation histograms.
+type MetricsConfig struct {
+	// OptionalLabels is a comma separated list of the optional labels populated
+	// in the operation histograms. Supported labels are "vc", "datastore_type"
+	// and "storage_policy". The labels which are not listed are left empty.
+	OptionalLabels string `gcfg:"optional-labels"`
+	// StoragePolicies is a comma separated allowlist of the storage policies
+	// reported in the "storage_policy" label. Storage policies are identified
+	// by name, or by ID if the StorageClass only sets the ID, as in Supervisor
+	// clusters. Other storage policies are reported as "other".
+	StoragePolicies string `gcfg:"storage-policies"`
+}
+
 // EnvClusterFlavor is the k8s cluster type on which CSI Driver is being deployed
 const EnvClusterFlavor = "CLUSTER_FLAVOR"
@@ -0,0 +1,243 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	cnstypes "github.com/vmware/govmomi/cns/types"
+	vsanfstypes "github.com/vmware/govmomi/vsan/vsanfs/types"
+	"gopkg.in/gcfg.v1"
+)
+
+const (
+	// ValidationSeverityError is the severity of the findings which prevent the
+	// driver from starting or working properly.
+	ValidationSeverityError = "error"
+	// ValidationSeverityWarning is the severity of the findings which are
+	// ignored by the driver but likely to be mistakes.
+	ValidationSeverityWarning = "warning"
+
+	// Checks run by ValidateConfigFile.
+	ValidationCheckParse              = "parse"
+	ValidationCheckDuplicateSection   = "duplicate-section"
+	ValidationCheckConfig             = "config"
+	ValidationCheckTopologyCategories = "topology-categories"
+	ValidationCheckNetPermissions     = "net-permissions"
+	ValidationCheckDatacenters        = "datacenters"
+
+	// maxTopologyCategories is the maximum number of topology categories.
+	maxTopologyCategories = 5
+)
+
+// ValidationFinding is an issue found while validating a configuration.
+type ValidationFinding struct {
+	Severity string `json:"severity"`
+	// Check is the check which found the issue, e.g. "net-permissions".
+	Check string `json:"check"`
+	// Section is the config section the issue was found in, if any, e.g.
+	// `VirtualCenter "vc1.example.com"`.
+	Section string `json:"section,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationReport is the machine-readable result of the validation of a
+// configuration file.
+type ValidationReport struct {
+	Path   string `json:"path"`
+	Flavor string `json:"flavor"`
+	// Valid is false if any finding has the error severity.
+	Valid    bool                `json:"valid"`
+	Findings []ValidationFinding `json:"findings"`
+}
+
+// AddError adds a finding with the error severity to the report.
+func (r *ValidationReport) AddError(check, section, format string, args ...interface{}) {
+	r.Valid = false
+	r.Findings = append(r.Findings, ValidationFinding{Severity: ValidationSeverityError, Check: check,
+		Section: section, Message: fmt.Sprintf(format, args...)})
+}
+
+// AddWarning adds a finding with the warning severity to the report.
+func (r *ValidationReport) AddWarning(check, section, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, ValidationFinding{Severity: ValidationSeverityWarning, Check: check,
+		Section: section, Message: fmt.Sprintf(format, args...)})
+}
+
+// VirtualCenterSection returns the name of the config section of the vCenter
+// host, as used in the Section of the findings.
+func VirtualCenterSection(host string) string {
+	return fmt.Sprintf("VirtualCenter %q", host)
+}
+
+// sectionHeaderRegex matches the header of a config section, capturing its
+// name and its subsection name if any.
+var sectionHeaderRegex = regexp.MustCompile(`^\s*\[\s*([A-Za-z0-9_-]+)(?:\s+"((?:[^"\\]|\\.)*)")?\s*\]`)
+
+// ValidateConfigFile parses the configuration file at path for the cluster
+// flavor using ReadConfig or ReadGCConfig and runs the static validation of
+// the configuration. Unlike the validation run by the driver at start, it
+// reports all the issues found instead of the first one. The parsed
+// configuration is returned if it is valid.
+func ValidateConfigFile(ctx context.Context, path string,
+	flavor cnstypes.CnsClusterFlavor) (*ValidationReport, *Config) {
+	report := &ValidationReport{Path: path, Flavor: string(flavor), Valid: true, Findings: []ValidationFinding{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		report.AddError(ValidationCheckParse, "", "failed to read config file: %v", err)
+		return report, nil
+	}
+	validateSectionHeaders(data, report)
+
+	// Non-fatal errors such as unknown variables are ignored by the driver.
+	rawCfg := &Config{}
+	if err := gcfg.ReadInto(rawCfg, bytes.NewReader(data)); err != nil {
+		if gcfg.FatalOnly(err) != nil {
+			report.AddError(ValidationCheckParse, "", "%v", err)
+			return report, nil
+		}
+		report.AddWarning(ValidationCheckParse, "", "%v", err)
+	}
+
+	var cfg *Config
+	if flavor == cnstypes.CnsClusterFlavorGuest {
+		cfg, err = ReadGCConfig(ctx, bytes.NewReader(data))
+	} else {
+		cfg, err = ReadConfig(ctx, bytes.NewReader(data))
+		validateTopologyCategories(rawCfg, report)
+		validateNetPermissions(rawCfg, report)
+		validateDatacenters(rawCfg, report)
+	}
+	if err != nil {
+		report.AddError(ValidationCheckConfig, "", "%v", err)
+		return report, nil
+	}
+	if !report.Valid {
+		return report, nil
+	}
+	return report, cfg
+}
+
+// validateSectionHeaders reports the sections defined more than once. gcfg
+// merges them silently, so the values of the last one override the others.
+func validateSectionHeaders(data []byte, report *ValidationReport) {
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		match := sectionHeaderRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		// Section names are case insensitive while subsection names are not.
+		section := strings.ToLower(match[1])
+		if match[2] != "" {
+			section += fmt.Sprintf(" %q", match[2])
+		}
+		if firstLine, ok := seen[section]; ok {
+			report.AddError(ValidationCheckDuplicateSection, strings.TrimSpace(scanner.Text()),
+				"section at line %d is already defined at line %d", lineNum, firstLine)
+			continue
+		}
+		seen[section] = lineNum
+	}
+}
+
+// validateTopologyCategories validates the list of topology categories.
+func validateTopologyCategories(cfg *Config, report *ValidationReport) {
+	if strings.TrimSpace(cfg.Labels.TopologyCategories) == "" {
+		return
+	}
+	categories := strings.Split(cfg.Labels.TopologyCategories, ",")
+	if len(categories) > maxTopologyCategories {
+		report.AddError(ValidationCheckTopologyCategories, "Labels",
+			"%d topology categories are given. At most %d are allowed", len(categories), maxTopologyCategories)
+	}
+	seen := make(map[string]bool)
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			report.AddError(ValidationCheckTopologyCategories, "Labels",
+				"topology-categories %q contains an empty category", cfg.Labels.TopologyCategories)
+			continue
+		}
+		if seen[category] {
+			report.AddError(ValidationCheckTopologyCategories, "Labels",
+				"topology category %q is given more than once", category)
+		}
+		seen[category] = true
+	}
+}
+
+// validateNetPermissions validates the permissions and the IPs of all the
+// NetPermissions sections.
+func validateNetPermissions(cfg *Config, report *ValidationReport) {
+	for name, netPerm := range cfg.NetPermissions {
+		section := fmt.Sprintf("NetPermissions %q", name)
+		switch netPerm.Permissions {
+		case "", vsanfstypes.VsanFileShareAccessTypeNO_ACCESS, vsanfstypes.VsanFileShareAccessTypeREAD_ONLY,
+			vsanfstypes.VsanFileShareAccessTypeREAD_WRITE:
+		default:
+			report.AddError(ValidationCheckNetPermissions, section,
+				"invalid permissions %q. Supported values are %q, %q and %q", netPerm.Permissions,
+				vsanfstypes.VsanFileShareAccessTypeREAD_WRITE, vsanfstypes.VsanFileShareAccessTypeREAD_ONLY,
+				vsanfstypes.VsanFileShareAccessTypeNO_ACCESS)
+		}
+		ips := strings.TrimSpace(netPerm.Ips)
+		if ips == "" || ips == "*" {
+			continue
+		}
+		if net.ParseIP(ips) == nil {
+			if _, _, err := net.ParseCIDR(ips); err != nil {
+				report.AddError(ValidationCheckNetPermissions, section,
+					"invalid ips %q. It must be \"*\", an IP address or a subnet in CIDR notation", netPerm.Ips)
+			}
+		}
+	}
+}
+
+// validateDatacenters validates the format of the datacenter lists.
+func validateDatacenters(cfg *Config, report *ValidationReport) {
+	validate := func(section, datacenters string) {
+		if strings.TrimSpace(datacenters) == "" {
+			return
+		}
+		seen := make(map[string]bool)
+		for _, dc := range strings.Split(datacenters, ",") {
+			dc = strings.TrimSpace(dc)
+			if dc == "" {
+				report.AddError(ValidationCheckDatacenters, section,
+					"datacenters %q contains an empty datacenter", datacenters)
+				continue
+			}
+			if seen[dc] {
+				report.AddWarning(ValidationCheckDatacenters, section, "datacenter %q is given more than once", dc)
+			}
+			seen[dc] = true
+		}
+	}
+	validate("Global", cfg.Global.Datacenters)
+	for host, vcConfig := range cfg.VirtualCenter {
+		validate(VirtualCenterSection(host), vcConfig.Datacenters)
+	}
+}
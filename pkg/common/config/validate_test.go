@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cnstypes "github.com/vmware/govmomi/cns/types"
+)
+
+func writeTestConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "csi-vsphere.conf")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestValidateConfigFileValid(t *testing.T) {
+	t.Setenv("CLUSTER_FLAVOR", "")
+	path := writeTestConfig(t, `
+[Global]
+cluster-id = "cluster1"
+
+[VirtualCenter "1.1.1.1"]
+user = "Administrator@vsphere.local"
+password = "Password"
+datacenters = "dc1, dc2"
+
+[NetPermissions "A"]
+ips = "10.20.30.0/24"
+permissions = "READ_ONLY"
+`)
+	report, cfg := ValidateConfigFile(ctx, path, cnstypes.CnsClusterFlavorVanilla)
+	if !report.Valid || len(report.Findings) != 0 || cfg == nil {
+		t.Fatalf("expected a valid config without findings. Report: %+v", report)
+	}
+	if cfg.VirtualCenter["1.1.1.1"].Datacenters != "dc1, dc2" {
+		t.Errorf("unexpected datacenters %q", cfg.VirtualCenter["1.1.1.1"].Datacenters)
+	}
+}
+
+func TestValidateConfigFileReportsAllIssues(t *testing.T) {
+	t.Setenv("CLUSTER_FLAVOR", "")
+	path := writeTestConfig(t, `
+[Global]
+cluster-id = "cluster1"
+unknown-key = "value"
+datacenters = "dc1,,dc1"
+
+[Labels]
+topology-categories = "k8s-zone,,k8s-zone"
+
+[VirtualCenter "1.1.1.1"]
+user = "Administrator@vsphere.local"
+password = "Password"
+datacenters = "dc1,"
+
+[VirtualCenter "1.1.1.1"]
+datacenters = "dc1"
+
+[NetPermissions "A"]
+ips = "10.20.30.0/33"
+permissions = "READ_WRITE"
+
+[NetPermissions "B"]
+permissions = "WRITE_ONLY"
+`)
+	report, cfg := ValidateConfigFile(ctx, path, cnstypes.CnsClusterFlavorVanilla)
+	if report.Valid || cfg != nil {
+		t.Fatalf("expected an invalid config. Report: %+v", report)
+	}
+	counts := make(map[string]int)
+	for _, finding := range report.Findings {
+		counts[finding.Severity+"/"+finding.Check]++
+	}
+	expected := map[string]int{
+		ValidationSeverityWarning + "/" + ValidationCheckParse:            1,
+		ValidationSeverityError + "/" + ValidationCheckDuplicateSection:   1,
+		ValidationSeverityError + "/" + ValidationCheckTopologyCategories: 2,
+		ValidationSeverityError + "/" + ValidationCheckNetPermissions:     2,
+		ValidationSeverityError + "/" + ValidationCheckConfig:             1,
+		ValidationSeverityError + "/" + ValidationCheckDatacenters:        1,
+		ValidationSeverityWarning + "/" + ValidationCheckDatacenters:      1,
+	}
+	for key, count := range expected {
+		if counts[key] != count {
+			t.Errorf("expected %d findings for %s, got %d. Report: %+v", count, key, counts[key], report)
+		}
+	}
+}
+
+func TestValidateConfigFileParseError(t *testing.T) {
+	path := writeTestConfig(t, "[VirtualCenter \"1.1.1.1\"\nuser = ")
+	report, cfg := ValidateConfigFile(ctx, path, cnstypes.CnsClusterFlavorVanilla)
+	if report.Valid || cfg != nil || len(report.Findings) != 1 || report.Findings[0].Check != ValidationCheckParse {
+		t.Fatalf("expected a single parse error. Report: %+v", report)
+	}
+}
@@ -59,6 +59,10 @@ const (
 	// CSITaskResultEmptyFault is the fault type when taskResult is empty.
 	CSITaskResultEmptyFault = "csi.fault.TaskResultEmpty"
 
+	// CSITaskTimedOutFault is the fault type when a CNS task does not complete within
+	// the operation timeout. The task may still be running on vCenter.
+	CSITaskTimedOutFault = "csi.fault.TaskTimedOut"
+
 	// CSIInternalFault is the fault type returned when CSI internal error occurs.
 	CSIInternalFault = "csi.fault.Internal"
 	// CSINotFoundFault is the fault type returned when object required is not found.
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// OptionalLabelVC is the optional label holding the vCenter host of an
+	// operation.
+	OptionalLabelVC = "vc"
+	// OptionalLabelDatastoreType is the optional label holding the type of the
+	// datastore of an operation, e.g. "vsan".
+	OptionalLabelDatastoreType = "datastore_type"
+	// OptionalLabelStoragePolicy is the optional label holding the storage
+	// policy of an operation.
+	OptionalLabelStoragePolicy = "storage_policy"
+
+	// OtherStoragePolicy is the value of the storage_policy label for the
+	// storage policies which are not in the allowlist.
+	OtherStoragePolicy = "other"
+)
+
+// optionalLabelNames are the names of the optional labels, in the order they
+// are appended to the labels of an OpsHistogramVec.
+var optionalLabelNames = []string{OptionalLabelVC, OptionalLabelDatastoreType, OptionalLabelStoragePolicy}
+
+// optionalLabelsConfig holds the optional labels which are populated and the
+// allowlist of the storage policies reported in the storage_policy label.
+type optionalLabelsConfig struct {
+	enabled         map[string]bool
+	storagePolicies map[string]bool
+}
+
+// optionalLabels holds the optionalLabelsConfig in effect. A nil value means
+// that the optional labels are left empty.
+var optionalLabels atomic.Pointer[optionalLabelsConfig]
+
+// ConfigureOptionalLabels sets the optional labels populated in the CSI and
+// CNS operation histograms. labels is a comma separated list of optional label
+// names and storagePolicies a comma separated allowlist of storage policies.
+// The storage policies which are not in the allowlist are reported as
+// OtherStoragePolicy to bound the cardinality of the histograms.
+func ConfigureOptionalLabels(labels, storagePolicies string) error {
+	cfg := &optionalLabelsConfig{
+		enabled:         make(map[string]bool),
+		storagePolicies: make(map[string]bool),
+	}
+	for _, label := range splitList(labels) {
+		if !slices.Contains(optionalLabelNames, label) {
+			return fmt.Errorf("unsupported optional metric label %q. Supported labels: %s",
+				label, strings.Join(optionalLabelNames, ", "))
+		}
+		cfg.enabled[label] = true
+	}
+	for _, policy := range splitList(storagePolicies) {
+		cfg.storagePolicies[policy] = true
+	}
+	optionalLabels.Store(cfg)
+	return nil
+}
+
+// splitList splits a comma separated list and drops the empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// OpTarget holds the values of the optional labels of an operation. A value is
+// only reported if its label is enabled using ConfigureOptionalLabels, and is
+// left empty otherwise.
+type OpTarget struct {
+	// VC is the vCenter host the operation is run on.
+	VC string
+	// DatastoreType is the type of the datastore of the volume.
+	DatastoreType string
+	// StoragePolicy is the name, or the ID if the name is not known, of the
+	// storage policy of the volume.
+	StoragePolicy string
+}
+
+// labels returns the optional labels of the target.
+func (t OpTarget) labels() prometheus.Labels {
+	labels := prometheus.Labels{
+		OptionalLabelVC:            "",
+		OptionalLabelDatastoreType: "",
+		OptionalLabelStoragePolicy: "",
+	}
+	cfg := optionalLabels.Load()
+	if cfg == nil {
+		return labels
+	}
+	if cfg.enabled[OptionalLabelVC] {
+		labels[OptionalLabelVC] = t.VC
+	}
+	if cfg.enabled[OptionalLabelDatastoreType] {
+		labels[OptionalLabelDatastoreType] = t.DatastoreType
+	}
+	if cfg.enabled[OptionalLabelStoragePolicy] && t.StoragePolicy != "" {
+		if cfg.storagePolicies[t.StoragePolicy] {
+			labels[OptionalLabelStoragePolicy] = t.StoragePolicy
+		} else {
+			labels[OptionalLabelStoragePolicy] = OtherStoragePolicy
+		}
+	}
+	return labels
+}
+
+// OpsHistogramVec is a histogram vector of operations which has the optional
+// labels of OpTarget in addition to its own labels.
+type OpsHistogramVec struct {
+	vec *prometheus.HistogramVec
+}
+
+// newOpsHistogramVec creates and registers an OpsHistogramVec with the given
+// labels followed by the optional labels.
+func newOpsHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *OpsHistogramVec {
+	return &OpsHistogramVec{
+		vec: promauto.NewHistogramVec(opts, append(labelNames, optionalLabelNames...)),
+	}
+}
+
+// WithLabelValues returns the histogram for the given values of the labels of
+// the vector, with the optional labels left empty.
+func (v *OpsHistogramVec) WithLabelValues(lvs ...string) prometheus.Observer {
+	return v.vec.WithLabelValues(append(lvs, make([]string, len(optionalLabelNames))...)...)
+}
+
+// ForTarget returns the histograms of the operations run on target, whose
+// optional labels are set from target.
+func (v *OpsHistogramVec) ForTarget(target OpTarget) prometheus.ObserverVec {
+	return v.vec.MustCurryWith(target.labels())
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpTargetLabels(t *testing.T) {
+	t.Cleanup(func() { optionalLabels.Store(nil) })
+	target := OpTarget{VC: "vc1.example.com", DatastoreType: "vsan", StoragePolicy: "gold"}
+	noLabels := prometheus.Labels{
+		OptionalLabelVC:            "",
+		OptionalLabelDatastoreType: "",
+		OptionalLabelStoragePolicy: "",
+	}
+	assert.Equal(t, noLabels, target.labels())
+
+	require.NoError(t, ConfigureOptionalLabels("vc, storage_policy", "gold,silver"))
+	assert.Equal(t, prometheus.Labels{
+		OptionalLabelVC:            "vc1.example.com",
+		OptionalLabelDatastoreType: "",
+		OptionalLabelStoragePolicy: "gold",
+	}, target.labels())
+
+	// Storage policies missing from the allowlist are reported as "other".
+	target.StoragePolicy = "bronze"
+	assert.Equal(t, OtherStoragePolicy, target.labels()[OptionalLabelStoragePolicy])
+	target.StoragePolicy = ""
+	assert.Equal(t, "", target.labels()[OptionalLabelStoragePolicy])
+
+	assert.Error(t, ConfigureOptionalLabels("vc,namespace", ""))
+	require.NoError(t, ConfigureOptionalLabels("", ""))
+	assert.Equal(t, noLabels, OpTarget{VC: "vc1.example.com"}.labels())
+}
+
+func TestOpsHistogramVec(t *testing.T) {
+	t.Cleanup(func() { optionalLabels.Store(nil) })
+	require.NoError(t, ConfigureOptionalLabels("vc,datastore_type", ""))
+	vec := newOpsHistogramVec(prometheus.HistogramOpts{
+		Name: "vsphere_test_ops_histogram",
+	}, []string{"optype", "status"})
+
+	vec.WithLabelValues(PrometheusCnsCreateVolumeOpType, PrometheusPassStatus).Observe(1)
+	vec.ForTarget(OpTarget{VC: "vc1.example.com", DatastoreType: "vsan"}).
+		WithLabelValues(PrometheusCnsCreateVolumeOpType, PrometheusPassStatus).Observe(2)
+
+	metricFamilies, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	var labelSets []map[string]string
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != "vsphere_test_ops_histogram" {
+			continue
+		}
+		for _, metric := range metricFamily.GetMetric() {
+			labelSet := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labelSet[label.GetName()] = label.GetValue()
+			}
+			labelSets = append(labelSets, labelSet)
+		}
+	}
+	assert.ElementsMatch(t, []map[string]string{
+		{"optype": PrometheusCnsCreateVolumeOpType, "status": PrometheusPassStatus,
+			OptionalLabelVC: "", OptionalLabelDatastoreType: "", OptionalLabelStoragePolicy: ""},
+		{"optype": PrometheusCnsCreateVolumeOpType, "status": PrometheusPassStatus,
+			OptionalLabelVC: "vc1.example.com", OptionalLabelDatastoreType: "vsan", OptionalLabelStoragePolicy: ""},
+	}, labelSets)
+}
@@ -49,6 +49,17 @@ const (
 	PrometheusListSnapshotsOpType = "list-snapshot"
 	// PrometheusListVolumeOpType represents the ListVolumes operation.
 	PrometheusListVolumeOpType = "list-volume"
+	// PrometheusGetCapacityOpType represents the GetCapacity operation.
+	PrometheusGetCapacityOpType = "get-capacity"
+	// PrometheusGetVolumeOpType represents the ControllerGetVolume operation.
+	PrometheusGetVolumeOpType = "get-volume"
+	// PrometheusModifyVolumeOpType represents the ControllerModifyVolume operation.
+	PrometheusModifyVolumeOpType = "modify-volume"
+
+	// PrometheusNodeStageVolumeOpType represents the NodeStageVolume operation.
+	PrometheusNodeStageVolumeOpType = "node-stage-volume"
+	// PrometheusNodePublishVolumeOpType represents the NodePublishVolume operation.
+	PrometheusNodePublishVolumeOpType = "node-publish-volume"
 
 	// CNS operation types
 
@@ -80,6 +91,8 @@ const (
 	PrometheusCnsClearVolumeControlFlagsOpType = "clear-volume-control-flags"
 	// PrometheusCnsRelocateVolumeOpType represents the RelocateVolume operation.
 	PrometheusCnsRelocateVolumeOpType = "relocate-volume"
+	// PrometheusCnsUpdateVolumePolicyOpType represents the ReconfigVolumePolicy operation.
+	PrometheusCnsUpdateVolumePolicyOpType = "update-volume-policy"
 	// PrometheusCnsConfigureVolumeACLOpType represents the ConfigureVolumeAcl operation.
 	PrometheusCnsConfigureVolumeACLOpType = "configure-volume-acl"
 	// PrometheusQuerySnapshotsOpType represents QuerySnapshots operation.
@@ -130,8 +143,8 @@ var (
 	}, []string{"version"})
 
 	// CsiControlOpsHistVec is a histogram vector metric to observe various control
-	// operations in CSI.
-	CsiControlOpsHistVec = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	// operations in CSI. It also has the optional labels described in OpTarget.
+	CsiControlOpsHistVec = newOpsHistogramVec(prometheus.HistogramOpts{
 		Name: "vsphere_csi_volume_ops_histogram",
 		Help: "Histogram vector for CSI volume operations.",
 		// Creating more buckets for operations that takes few seconds and less buckets
@@ -146,8 +159,9 @@ var (
 
 	// CnsControlOpsHistVec is a histogram vector metric to observe various control
 	// operations on CNS. Note that this captures the time taken by CNS into a bucket
-	// as seen by the client(CSI in this case).
-	CnsControlOpsHistVec = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	// as seen by the client(CSI in this case). It also has the optional labels
+	// described in OpTarget.
+	CnsControlOpsHistVec = newOpsHistogramVec(prometheus.HistogramOpts{
 		Name: "vsphere_cns_volume_ops_histogram",
 		Help: "Histogram vector for CNS operations.",
 		// Creating more buckets for operations that takes few seconds and less buckets
@@ -159,6 +173,42 @@ var (
 		// Possible status - "pass", "fail"
 		[]string{"optype", "status"})
 
+	// NodeOpsHistVec is a histogram vector metric to observe the node operations
+	// which make a volume usable by a pod on the node. It is exposed by the node
+	// plugin, so the node is identified by the scrape target.
+	NodeOpsHistVec = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vsphere_csi_node_ops_histogram",
+		Help:    "Histogram vector for CSI node volume operations.",
+		Buckets: []float64{0.5, 1, 2, 5, 10, 15, 30, 60, 120},
+	},
+		// Possible voltype - "unknown", "block", "file"
+		// Possible optype - "node-stage-volume", "node-publish-volume"
+		// Possible status - "pass", "fail"
+		[]string{"voltype", "optype", "status"})
+
+	// NodeDeviceDiscoveryHistVec is a histogram vector metric to observe the time
+	// taken by the node plugin to find the disk of an attached block volume when
+	// staging it. A "fail" status indicates that the disk was not found on the
+	// node.
+	NodeDeviceDiscoveryHistVec = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vsphere_csi_node_device_discovery_seconds",
+		Help:    "Histogram vector for the discovery of the disks of attached volumes on the node.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5},
+	},
+		// Possible status - "pass", "fail"
+		[]string{"status"})
+
+	// AttachQueueDepthGaugeVec is a gauge metric to observe, per node, the number
+	// of ControllerPublishVolume and ControllerUnpublishVolume calls in progress.
+	// It is updated by TrackAttachQueueDepth, which deletes the gauge of a node
+	// once it has no calls in progress.
+	AttachQueueDepthGaugeVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vsphere_csi_attach_queue_depth",
+		Help: "Number of attach and detach operations in progress, per node.",
+	},
+		// Possible optype - "attach-volume", "detach-volume"
+		[]string{"node", "optype"})
+
 	// VolumeHealthGaugeVec is a gauge metric to observe the number of accessible and inaccessible volumes.
 	VolumeHealthGaugeVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "vsphere_volume_health_gauge",
@@ -185,6 +235,16 @@ var (
 		Buckets: []float64{2, 5, 10, 15, 20, 25, 30, 60, 120, 180},
 	}, []string{"request", "client", "status"})
 
+	// VcenterCredentialRotationsCounterVec is a counter vector metric to observe
+	// in-place rotations of vCenter credentials. A "fail" status indicates that
+	// vCenter rejected the rotated credentials.
+	VcenterCredentialRotationsCounterVec = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vsphere_vcenter_credential_rotations_total",
+		Help: "Number of vCenter credential rotations, per vCenter and status.",
+	},
+		// Possible status - "pass", "fail"
+		[]string{"vc", "status"})
+
 	// CnsVolumePVMissingGaugeVec is a gauge metric that tracks, per vCenter,
 	// the number of CNS volumes whose matching Kubernetes PV was not found in
 	// the most recent full-sync cycle. It is reset to the new count at the end
@@ -208,4 +268,65 @@ var (
 		Name: "vsphere_cns_volume_pv_retained",
 		Help: "Number of CNS volumes with ReclaimPolicy=Retain PVs in Released/Available phase, per vCenter.",
 	}, []string{"vc"})
+
+	// ListViewPendingTasksGaugeVec is a gauge metric to observe, per vCenter, the
+	// number of CNS tasks tracked by the ListView whose callers are still waiting
+	// for the result. A growing value indicates that vCenter completes the tasks
+	// slower than they are created.
+	ListViewPendingTasksGaugeVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vsphere_cns_listview_pending_tasks",
+		Help: "Number of CNS tasks waiting for their result in the ListView, per vCenter.",
+	}, []string{"vc"})
+
+	// ListViewTaskMapSizeGaugeVec is a gauge metric to observe, per vCenter, the
+	// number of entries in the TaskMap of the ListView, including the tasks
+	// marked for removal which are not purged yet.
+	ListViewTaskMapSizeGaugeVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vsphere_cns_listview_taskmap_size",
+		Help: "Number of tasks in the ListView TaskMap, per vCenter.",
+	}, []string{"vc"})
+
+	// VolumeLocksHeldGauge is a gauge metric to observe the number of volumes
+	// with an ongoing node operation.
+	VolumeLocksHeldGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "vsphere_csi_volume_locks_held",
+		Help: "Number of volumes with an ongoing node operation.",
+	})
+
+	// VolumeLockContentionsCounter is a counter metric to observe the node
+	// operations rejected because another operation on the same volume was in
+	// progress.
+	VolumeLockContentionsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "vsphere_csi_volume_lock_contentions_total",
+		Help: "Number of node operations rejected because the volume was locked by another operation.",
+	})
+
+	// VcenterRequestsQueuedGaugeVec is a gauge metric to observe, per vCenter and
+	// request lane, the number of requests waiting for their rate limit.
+	VcenterRequestsQueuedGaugeVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vsphere_vcenter_requests_queued",
+		Help: "Number of vCenter requests waiting for their rate limit, per vCenter and lane.",
+	},
+		// Possible lane - "attach", "provision", "query", "fullsync"
+		[]string{"vc", "lane"})
+
+	// VcenterRequestWaitHistVec is a histogram vector metric to observe the time
+	// the rate limited vCenter requests waited before being sent.
+	VcenterRequestWaitHistVec = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vsphere_vcenter_request_wait_seconds",
+		Help:    "Histogram vector of the time vCenter requests waited for their rate limit.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30, 60},
+	},
+		// Possible lane - "attach", "provision", "query", "fullsync"
+		[]string{"vc", "lane"})
+
+	// VcenterHealthStateGaugeVec is a gauge metric to observe the health of the
+	// connection to each vCenter. The gauge of the current state is 1 and the
+	// others are 0.
+	VcenterHealthStateGaugeVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vsphere_vcenter_health_state",
+		Help: "Health state of the connection to the vCenter, per vCenter.",
+	},
+		// Possible state - "healthy", "degraded", "down"
+		[]string{"vc", "state"})
 )
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"sync"
+)
+
+// attachQueueDepth is the key of the attach and detach operations in progress
+// on a node.
+type attachQueueDepth struct {
+	node   string
+	opType string
+}
+
+var (
+	attachQueueDepthsMutex sync.Mutex
+	// attachQueueDepths is the number of attach and detach operations in
+	// progress, per node and operation type.
+	attachQueueDepths = make(map[attachQueueDepth]int)
+)
+
+// TrackAttachQueueDepth increments AttachQueueDepthGaugeVec for an attach or
+// detach operation starting on the node, and returns the function to call
+// once the operation is done. The gauge of the node is deleted when no more
+// operations are in progress on it, so that the nodes removed from the cluster
+// are not reported forever.
+func TrackAttachQueueDepth(node, opType string) func() {
+	key := attachQueueDepth{node: node, opType: opType}
+	attachQueueDepthsMutex.Lock()
+	attachQueueDepths[key]++
+	AttachQueueDepthGaugeVec.WithLabelValues(node, opType).Set(float64(attachQueueDepths[key]))
+	attachQueueDepthsMutex.Unlock()
+	return func() {
+		attachQueueDepthsMutex.Lock()
+		defer attachQueueDepthsMutex.Unlock()
+		attachQueueDepths[key]--
+		if attachQueueDepths[key] > 0 {
+			AttachQueueDepthGaugeVec.WithLabelValues(node, opType).Set(float64(attachQueueDepths[key]))
+			return
+		}
+		delete(attachQueueDepths, key)
+		AttachQueueDepthGaugeVec.DeleteLabelValues(node, opType)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackAttachQueueDepth(t *testing.T) {
+	done1 := TrackAttachQueueDepth("node1", PrometheusAttachVolumeOpType)
+	done2 := TrackAttachQueueDepth("node1", PrometheusAttachVolumeOpType)
+	gauge := AttachQueueDepthGaugeVec.WithLabelValues("node1", PrometheusAttachVolumeOpType)
+	assert.Equal(t, float64(2), testutil.ToFloat64(gauge))
+
+	done1()
+	assert.Equal(t, float64(1), testutil.ToFloat64(gauge))
+	done2()
+	assert.False(t, AttachQueueDepthGaugeVec.DeleteLabelValues("node1", PrometheusAttachVolumeOpType),
+		"the gauge of an idle node should be deleted")
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+)
+
+const (
+	// EnvTracingExporter is the environment variable which selects the
+	// exporter of the traces. Tracing is disabled if it is not set.
+	EnvTracingExporter = "TRACING_EXPORTER"
+	// EnvTracingFilePath is the environment variable which sets the file the
+	// traces are written to when the file exporter is selected.
+	EnvTracingFilePath = "TRACING_FILE_PATH"
+
+	// ExporterOTLP exports the traces to an OTLP gRPC endpoint. The endpoint
+	// and its options are set using the standard OTEL_EXPORTER_OTLP_*
+	// environment variables.
+	ExporterOTLP = "otlp"
+	// ExporterFile writes the traces as JSON to the file set in
+	// EnvTracingFilePath, for offline debugging.
+	ExporterFile = "file"
+
+	// tracerName is the name of the tracer used for all spans of the driver.
+	tracerName = "sigs.k8s.io/vsphere-csi-driver"
+)
+
+// ShutdownFunc flushes the pending spans and stops the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// InitTracing sets up the global tracer provider with the exporter selected
+// in EnvTracingExporter. If tracing is not enabled, the global no-op tracer
+// provider is retained and spans are not recorded. The sampler can be set
+// using the standard OTEL_TRACES_SAMPLER environment variables.
+func InitTracing(ctx context.Context, serviceName string) (ShutdownFunc, error) {
+	log := logger.GetLogger(ctx)
+	exporterType := strings.ToLower(strings.TrimSpace(os.Getenv(EnvTracingExporter)))
+	if exporterType == "" {
+		log.Info("Tracing is disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	var err error
+	switch exporterType {
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to create OTLP trace exporter. Error: %v", err)
+		}
+	case ExporterFile:
+		filePath := os.Getenv(EnvTracingFilePath)
+		if filePath == "" {
+			return nil, logger.LogNewErrorf(log, "%s must be set when the %q trace exporter is used",
+				EnvTracingFilePath, ExporterFile)
+		}
+		file, err = os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to open trace file %q. Error: %v", filePath, err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, logger.LogNewErrorf(log, "failed to create file trace exporter. Error: %v", err)
+		}
+	default:
+		return nil, logger.LogNewErrorf(log, "invalid trace exporter %q set in %s. Supported exporters: %s, %s",
+			exporterType, EnvTracingExporter, ExporterOTLP, ExporterFile)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		log.Warnf("failed to merge trace resource attributes. Error: %v", err)
+		res = resource.NewSchemaless(attribute.String("service.name", serviceName))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	log.Infof("Tracing is enabled with the %q exporter for service %q", exporterType, serviceName)
+	return func(ctx context.Context) error {
+		err := tracerProvider.Shutdown(ctx)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// StartSpan starts a span with the given name as a child of the span in ctx,
+// if any, and returns a context holding the new span.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err, if any, on the span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// IsTraced returns true if ctx holds a valid span.
+func IsTraced(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
+
+// GetTraceID returns the trace ID of the span in ctx, or an empty string if
+// ctx does not hold a valid span.
+func GetTraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// WithOpID returns a copy of ctx which sets the vCenter operation ID of the
+// requests made with it to the trace ID of the span in ctx. The operation ID
+// is recorded by vCenter in its logs and as the ActivationId of the tasks
+// created by the request, which links the CNS tasks back to the trace. ctx is
+// returned as is if it is not traced or already has an operation ID.
+func WithOpID(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(types.ID{}).(string); ok {
+		return ctx
+	}
+	traceID := GetTraceID(ctx)
+	if traceID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, types.ID{}, traceID)
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor which starts a span for
+// every CSI RPC. The trace context propagated by the caller in the request
+// metadata, if any, is used as the parent of the span.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		}
+		ctx, span := otel.Tracer(tracerName).Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod)))
+		resp, err := handler(ctx, req)
+		EndSpan(span, err)
+		return resp, err
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to the propagation.TextMapCarrier
+// interface.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of the key.
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set sets the value of the key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns the keys of the metadata.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
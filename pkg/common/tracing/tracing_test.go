@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testTraceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testTraceCtx = "00-" + testTraceID + "-00f067aa0ba902b7-01"
+)
+
+func TestWithOpID(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, WithOpID(ctx).Value(types.ID{}))
+
+	traceID, err := trace.TraceIDFromHex(testTraceID)
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	tracedCtx := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	assert.Equal(t, testTraceID, WithOpID(tracedCtx).Value(types.ID{}))
+
+	// An operation ID set by the caller is retained.
+	opIDCtx := context.WithValue(tracedCtx, types.ID{}, "caller-opid")
+	assert.Equal(t, "caller-opid", WithOpID(opIDCtx).Value(types.ID{}))
+}
+
+func TestInitTracingWithFileExporter(t *testing.T) {
+	ctx := context.Background()
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	filePath := filepath.Join(t.TempDir(), "traces.json")
+	t.Setenv(EnvTracingExporter, ExporterFile)
+	t.Setenv(EnvTracingFilePath, filePath)
+	shutdown, err := InitTracing(ctx, "vsphere-csi-controller")
+	require.NoError(t, err)
+
+	// The trace context propagated in the request metadata is the parent of
+	// the RPC span.
+	var rpcTraceID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		rpcTraceID = GetTraceID(ctx)
+		_, span := StartSpan(ctx, "volume.Manager.CreateVolume")
+		EndSpan(span, errors.New("create failed"))
+		return nil, nil
+	}
+	rpcCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("traceparent", testTraceCtx))
+	_, err = UnaryServerInterceptor()(rpcCtx, nil,
+		&grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, rpcTraceID)
+
+	require.NoError(t, shutdown(ctx))
+	traces, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(traces), "/csi.v1.Controller/CreateVolume")
+	assert.Contains(t, string(traces), "volume.Manager.CreateVolume")
+	assert.Contains(t, string(traces), "create failed")
+	assert.Contains(t, string(traces), testTraceID)
+}
+
+func TestInitTracingWithInvalidExporter(t *testing.T) {
+	t.Setenv(EnvTracingExporter, "zipkin")
+	_, err := InitTracing(context.Background(), "vsphere-csi-controller")
+	assert.Error(t, err)
+}
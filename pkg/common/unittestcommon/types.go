@@ -174,6 +174,10 @@ func (m *MockVolumeManager) ExpandVolume(ctx context.Context, volumeID string, s
 	extraParams interface{}) (string, error) {
 	return "", nil
 }
+func (m *MockVolumeManager) UpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+	targetDatastore *types.ManagedObjectReference) (string, error) {
+	return "", nil
+}
 func (m *MockVolumeManager) ResetManager(ctx context.Context, vcenter *vsphere.VirtualCenter) error {
 	return nil
 }
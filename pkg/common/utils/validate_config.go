@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	cnstypes "github.com/vmware/govmomi/cns/types"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+)
+
+// ValidateConfig validates the configuration file at path without starting
+// the driver and writes the config.ValidationReport as JSON to out. If connect
+// is true and the static validation succeeds, the vCenters are also checked
+// using cnsvsphere.ValidateVirtualCenters. It returns the exit code of the
+// validation: 0 if the configuration is valid, 1 otherwise.
+func ValidateConfig(ctx context.Context, path string, connect bool, out io.Writer) int {
+	flavor, err := config.GetClusterFlavor(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get the cluster flavor: %v\n", err)
+		return 1
+	}
+	report, cfg := config.ValidateConfigFile(ctx, path, flavor)
+	// The guest clusters do not connect to vCenter.
+	if connect && cfg != nil && flavor != cnstypes.CnsClusterFlavorGuest {
+		// The vCenter session user agent is built from the config at the
+		// config path.
+		if err := os.Setenv(config.EnvVSphereCSIConfig, path); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set %s: %v\n", config.EnvVSphereCSIConfig, err)
+			return 1
+		}
+		cnsvsphere.ValidateVirtualCenters(ctx, cfg, report)
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to print the validation report: %v\n", err)
+		return 1
+	}
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
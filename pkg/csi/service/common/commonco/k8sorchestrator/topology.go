@@ -190,6 +190,7 @@ type wcpControllerVolumeTopology struct {
 // commoncotypes.ControllerTopologyService interface.
 func (c *K8sOrchestrator) InitTopologyServiceInController(ctx context.Context) (
 	commoncotypes.ControllerTopologyService, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 
 	if c.clusterFlavor == cnstypes.CnsClusterFlavorVanilla {
@@ -646,6 +647,7 @@ func topoCRDeleted(obj interface{}) {
 // InitTopologyServiceInNode returns a singleton implementation of the commoncotypes.NodeTopologyService interface.
 func (c *K8sOrchestrator) InitTopologyServiceInNode(ctx context.Context) (
 	commoncotypes.NodeTopologyService, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 
 	nodeVolumeTopologyInstanceLock.RLock()
@@ -707,6 +709,7 @@ func (c *K8sOrchestrator) InitTopologyServiceInNode(ctx context.Context) (
 // GetNodeTopologyLabels uses the CSINodeTopology CR to retrieve topology information of a node.
 func (volTopology *nodeVolumeTopology) GetNodeTopologyLabels(ctx context.Context, nodeInfo *commoncotypes.NodeInfo) (
 	map[string]string, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 	var err error
 
@@ -985,6 +988,7 @@ func getCSINodeTopologyWatchTimeoutInMin(ctx context.Context) int {
 //	           segments:<key:"failure-domain.beta.kubernetes.io/zone" value:"k8s-zone-us-east" > >
 func (volTopology *controllerVolumeTopology) GetSharedDatastoresInTopology(ctx context.Context,
 	reqParams interface{}) ([]*cnsvsphere.DatastoreInfo, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 	params := reqParams.(commoncotypes.VanillaTopologyFetchDSParams)
 	log.Debugf("Get shared datastores with topologyRequirement: %+v", params.TopologyRequirement)
@@ -1246,6 +1250,7 @@ func (volTopology *controllerVolumeTopology) getTopologySegmentsWithMatchingNode
 // list of node names using the information from CSINodeTopology instances.
 func (volTopology *controllerVolumeTopology) GetTopologyInfoFromNodes(ctx context.Context, reqParams interface{}) (
 	[]map[string]string, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 	params := reqParams.(commoncotypes.VanillaRetrieveTopologyInfoParams)
 	var topologySegments []map[string]string
@@ -1411,6 +1416,7 @@ func verifyAllNodesInTopologyAccessibleToDatastore(ctx context.Context, nodeName
 // clusterMorefs which match the topology requirement.
 func (volTopology *wcpControllerVolumeTopology) GetSharedDatastoresInTopology(ctx context.Context,
 	reqParams interface{}) ([]*cnsvsphere.DatastoreInfo, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 	params := reqParams.(commoncotypes.WCPTopologyFetchDSParams)
 	log.Debugf("Get shared datastores with topologyRequirement: %+v", params.TopologyRequirement)
@@ -1503,6 +1509,7 @@ func (volTopology *wcpControllerVolumeTopology) getClustersMatchingTopologySegme
 // using the information from azClusterMap cache.
 func (volTopology *wcpControllerVolumeTopology) GetTopologyInfoFromNodes(ctx context.Context, reqParams interface{}) (
 	[]map[string]string, error) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemTopology)
 	log := logger.GetLogger(ctx)
 	params := reqParams.(commoncotypes.WCPRetrieveTopologyInfoParams)
 	var topologySegments []map[string]string
@@ -29,6 +29,10 @@ const (
 	// TODO: will make the DefaultGbDiskSize configurable in the future.
 	DefaultGbDiskSize = int64(10)
 
+	// GetCapacityCacheTTL is the duration for which the capacity computed by
+	// GetCapacity is served from cache before being recomputed from vCenter.
+	GetCapacityCacheTTL = 1 * time.Minute
+
 	// DiskTypeBlockVolume is the value for PersistentVolume's attribute "type".
 	DiskTypeBlockVolume = "vSphere CNS Block Volume"
 
@@ -73,6 +77,12 @@ const (
 	// For Example: FsType: "ext4".
 	AttributeFsType = "fstype"
 
+	// AttributeMkfsOptions represents additional mkfs options used to format
+	// a block volume, in the Storage Class. It is passed to the node plugin
+	// through the volume context.
+	// For Example: MkfsOptions: "-E lazy_itable_init=0,lazy_journal_init=0".
+	AttributeMkfsOptions = "mkfsoptions"
+
 	// AttributeStoragePool represents name of the StoragePool on which to place
 	// the PVC. For example: StoragePool: "storagepool-vsandatastore".
 	AttributeStoragePool = "storagepool"
@@ -133,6 +143,9 @@ const (
 	// XFSType represents the xfs filesystem type for block volume.
 	XFSType = "xfs"
 
+	// BtrfsFsType represents the btrfs filesystem type for block volume.
+	BtrfsFsType = "btrfs"
+
 	// NfsV4FsType represents nfs4 mount type.
 	NfsV4FsType = "nfs4"
 
@@ -339,6 +352,10 @@ const (
 	// VSphereCSISnapshotIdDelimiter is the delimiter for concatenating CNS VolumeID and CNS SnapshotID
 	VSphereCSISnapshotIdDelimiter = "+"
 
+	// CloneSourceSnapshotPrefix is the name prefix of the transient snapshot taken
+	// on the source volume when cloning a volume.
+	CloneSourceSnapshotPrefix = "clone-"
+
 	// TopologyLabelsDomain is the domain name used to identify user-defined
 	// topology labels applied on the node by vSphere CSI driver.
 	TopologyLabelsDomain = "topology.csi.vmware.com"
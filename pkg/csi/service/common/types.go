@@ -29,10 +29,15 @@ import (
 
 var (
 	// BlockVolumeCaps represents how the block volume could be accessed.
-	// CNS block volumes support only SINGLE_NODE_WRITER where the volume is
-	// attached to a single node at any given time.
+	// CNS block volumes support only single node writer access modes where
+	// the volume is attached to a single node at any given time.
+	// SINGLE_NODE_SINGLE_WRITER (ReadWriteOncePod) and SINGLE_NODE_MULTI_WRITER
+	// (ReadWriteOnce) are used instead of SINGLE_NODE_WRITER by COs that
+	// support the SINGLE_NODE_MULTI_WRITER capability.
 	BlockVolumeCaps = []csi.VolumeCapability_AccessMode_Mode{
 		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
 	}
 
 	// MultiNodeVolumeCaps represents how the file volume or shared raw block volume
@@ -46,6 +51,17 @@ var (
 		csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
 	}
 
+	// MkfsOptionsAllowlist represents the mkfs options that can be set through
+	// the "mkfsoptions" Storage Class parameter for each filesystem type. All
+	// of them take a value. Options that make mkfs read or write other files
+	// or devices are not allowed.
+	MkfsOptionsAllowlist = map[string][]string{
+		Ext3FsType:  {"-b", "-E", "-i", "-I", "-L", "-m", "-N", "-O", "-T"},
+		Ext4FsType:  {"-b", "-E", "-i", "-I", "-L", "-m", "-N", "-O", "-T"},
+		XFSType:     {"-b", "-i", "-L", "-m", "-n", "-s"},
+		BtrfsFsType: {"-d", "-L", "-m", "-n", "-O", "-s"},
+	}
+
 	// ErrNotFound represents not found error
 	ErrNotFound = errors.New("not found")
 )
@@ -98,6 +114,7 @@ type StorageClassParams struct {
 	StoragePolicyName string
 	CSIMigration      string
 	Datastore         string
+	MkfsOptions       string
 }
 
 type CryptoKeyID struct {
@@ -165,9 +165,10 @@ func GetLabelsMapFromKeyValue(labels []types.KeyValue) map[string]string {
 // IsFileVolumeRequest checks whether the request is to create a CNS file volume.
 func IsFileVolumeRequest(ctx context.Context, capabilities []*csi.VolumeCapability) bool {
 	for _, capability := range capabilities {
-		if capability.AccessMode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY ||
-			capability.AccessMode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER ||
-			capability.AccessMode.Mode == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER {
+		accessMode := capability.GetAccessMode().GetMode()
+		if accessMode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY ||
+			accessMode == csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER ||
+			accessMode == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER {
 			return true
 		}
 	}
@@ -186,6 +187,15 @@ func IsVolumeReadOnly(capability *csi.VolumeCapability) bool {
 	return ro
 }
 
+// IsSingleNodeWriterAccessMode returns true if the given access mode allows
+// the volume to be written from a single node only, i.e. ReadWriteOnce or
+// ReadWriteOncePod.
+func IsSingleNodeWriterAccessMode(accessMode csi.VolumeCapability_AccessMode_Mode) bool {
+	return accessMode == csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER ||
+		accessMode == csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER ||
+		accessMode == csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER
+}
+
 // validateVolumeCapabilities validates the access mode in given volume
 // capabilities in validAccessModes.
 func validateVolumeCapabilities(volCaps []*csi.VolumeCapability,
@@ -204,11 +214,12 @@ func validateVolumeCapabilities(volCaps []*csi.VolumeCapability,
 				csi.VolumeCapability_AccessMode_Mode_name[int32(volCap.AccessMode.GetMode())], volumeType)
 		}
 
-		if volCap.AccessMode.Mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER {
-			// For ReadWriteOnce access mode we only support following filesystems:
-			// ext3, ext4, xfs for Linux and ntfs for Windows.
+		if IsSingleNodeWriterAccessMode(volCap.AccessMode.Mode) {
+			// For ReadWriteOnce and ReadWriteOncePod access modes we only support following filesystems:
+			// ext3, ext4, xfs, btrfs for Linux and ntfs for Windows.
 			if volCap.GetMount() != nil && !(volCap.GetMount().FsType == Ext4FsType ||
 				volCap.GetMount().FsType == Ext3FsType || volCap.GetMount().FsType == XFSType ||
+				volCap.GetMount().FsType == BtrfsFsType ||
 				strings.ToLower(volCap.GetMount().FsType) == NTFSFsType || volCap.GetMount().FsType == "") {
 				return fmt.Errorf("fstype %s not supported for ReadWriteOnce volume creation",
 					volCap.GetMount().FsType)
@@ -243,6 +254,22 @@ func IsValidVolumeCapabilities(ctx context.Context, volCaps []*csi.VolumeCapabil
 	return validateVolumeCapabilities(volCaps, BlockVolumeCaps, BlockVolumeType)
 }
 
+// GetStoragePolicyFromParams returns the storage policy name set in the
+// StorageClass parameters of a volume, or the storage policy ID if no name is
+// set.
+func GetStoragePolicyFromParams(params map[string]string) string {
+	var storagePolicyID string
+	for param, value := range params {
+		switch strings.ToLower(param) {
+		case AttributeStoragePolicyName:
+			return value
+		case AttributeStoragePolicyID:
+			storagePolicyID = value
+		}
+	}
+	return storagePolicyID
+}
+
 // ParseStorageClassParams parses the params in the CSI CreateVolumeRequest API
 // call back to StorageClassParams structure.
 func ParseStorageClassParams(ctx context.Context, params map[string]string) (*StorageClassParams, error) {
@@ -262,6 +289,11 @@ func ParseStorageClassParams(ctx context.Context, params map[string]string) (*St
 			log.Warnf("param 'fstype' is deprecated, please use 'csi.storage.k8s.io/fstype' instead")
 		} else if param == CSIMigrationParams {
 			scParams.CSIMigration = value
+		} else if param == AttributeMkfsOptions {
+			scParams.MkfsOptions = value
+		} else if param == AttributePvName || param == AttributePvcName || param == AttributePvcNamespace {
+			// Added by the external-provisioner with --extra-create-metadata.
+			continue
 		} else {
 			otherParams[param] = value
 		}
@@ -293,6 +325,36 @@ func ParseStorageClassParams(ctx context.Context, params map[string]string) (*St
 	return scParams, nil
 }
 
+// ParseMkfsOptions validates the given mkfs options against the allowlist for
+// the filesystem type and returns them as mkfs arguments. Options are
+// separated by whitespace and each option must be followed by its value.
+func ParseMkfsOptions(fsType string, mkfsOptions string) ([]string, error) {
+	args := strings.Fields(mkfsOptions)
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if fsType == "" {
+		fsType = Ext4FsType
+	}
+	allowedOptions, ok := MkfsOptionsAllowlist[strings.ToLower(fsType)]
+	if !ok {
+		return nil, fmt.Errorf("mkfs options are not supported for fstype %q", fsType)
+	}
+	for i := 0; i < len(args); i += 2 {
+		if !Contains(allowedOptions, args[i]) {
+			return nil, fmt.Errorf("mkfs option %q is not allowed for fstype %q. Allowed options: %v",
+				args[i], fsType, allowedOptions)
+		}
+		if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+			return nil, fmt.Errorf("mkfs option %q requires a value", args[i])
+		}
+		if strings.Contains(args[i+1], "/") {
+			return nil, fmt.Errorf("value %q of mkfs option %q must not contain a path", args[i+1], args[i])
+		}
+	}
+	return args, nil
+}
+
 // GetK8sCloudOperatorServicePort return the port to connect the
 // K8sCloudOperator gRPC service.
 // If environment variable POD_LISTENER_SERVICE_PORT is set and valid,
@@ -343,6 +405,33 @@ func ConvertVolumeHealthStatus(ctx context.Context, volID string, volHealthStatu
 	}
 }
 
+// GetVolumeCondition converts the CNS health status of a volume into a CSI
+// VolumeCondition. A volume whose health status is unknown or not set, e.g.
+// file volumes or volumes whose health has not been computed yet, is not
+// reported as abnormal. Unlike ConvertVolumeHealthStatus, an empty health
+// status is not taken as the volume being inaccessible.
+func GetVolumeCondition(ctx context.Context, volID string, volHealthStatus string) *csi.VolumeCondition {
+	log := logger.GetLogger(ctx)
+	switch volHealthStatus {
+	case string(pbmtypes.PbmHealthStatusForEntityGreen), string(pbmtypes.PbmHealthStatusForEntityYellow):
+		return &csi.VolumeCondition{
+			Abnormal: false,
+			Message:  fmt.Sprintf("volume is accessible, health status: %q", volHealthStatus),
+		}
+	case string(pbmtypes.PbmHealthStatusForEntityRed):
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume is inaccessible, health status: %q", volHealthStatus),
+		}
+	default:
+		log.Debugf("Volume health status of volume %s is unknown: %q", volID, volHealthStatus)
+		return &csi.VolumeCondition{
+			Abnormal: false,
+			Message:  "volume health status is unknown",
+		}
+	}
+}
+
 // ParseCSISnapshotID parses the SnapshotID from CSI RPC such as DeleteSnapshot, CreateVolume from snapshot
 // into a pair of CNS VolumeID and CNS SnapshotID.
 func ParseCSISnapshotID(csiSnapshotID string) (string, string, error) {
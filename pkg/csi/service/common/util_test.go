@@ -191,6 +191,36 @@ func TestValidVolumeCapabilitiesForBlock(t *testing.T) {
 	if err := IsValidVolumeCapabilities(ctx, volCap); err != nil {
 		t.Errorf("Block VolCap = %+v failed validation!", volCap)
 	}
+	// fstype=ext4 and mode=SINGLE_NODE_SINGLE_WRITER
+	volCap = []*csi.VolumeCapability{
+		{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{
+					FsType: "ext4",
+				},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			},
+		},
+	}
+	if err := IsValidVolumeCapabilities(ctx, volCap); err != nil {
+		t.Errorf("Block VolCap = %+v failed validation!", volCap)
+	}
+	// volumeMode=block and accessMode=SINGLE_NODE_MULTI_WRITER
+	volCap = []*csi.VolumeCapability{
+		{
+			AccessType: &csi.VolumeCapability_Block{
+				Block: &csi.VolumeCapability_BlockVolume{},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+			},
+		},
+	}
+	if err := IsValidVolumeCapabilities(ctx, volCap); err != nil {
+		t.Errorf("Block VolCap = %+v failed validation!", volCap)
+	}
 }
 
 func TestInvalidVolumeCapabilitiesForBlock(t *testing.T) {
@@ -227,6 +257,23 @@ func TestInvalidVolumeCapabilitiesForBlock(t *testing.T) {
 	if err := IsValidVolumeCapabilities(ctx, volCap); err == nil {
 		t.Errorf("Invalid Block VolCap = %+v passed validation!", volCap)
 	}
+
+	// Invalid case: fstype=nfs4 and mode=SINGLE_NODE_SINGLE_WRITER
+	volCap = []*csi.VolumeCapability{
+		{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{
+					FsType: "nfs4",
+				},
+			},
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			},
+		},
+	}
+	if err := IsValidVolumeCapabilities(ctx, volCap); err == nil {
+		t.Errorf("Invalid Block VolCap = %+v passed validation!", volCap)
+	}
 }
 
 func TestValidVolumeCapabilitiesForFile(t *testing.T) {
@@ -422,6 +469,25 @@ func TestParseStorageClassParamsWithValidParams(t *testing.T) {
 	}
 }
 
+func TestParseStorageClassParamsWithExtraCreateMetadata(t *testing.T) {
+	params := map[string]string{
+		AttributeStoragePolicyName: "policy1",
+		AttributePvName:            "pv",
+		AttributePvcName:           "pvc",
+		AttributePvcNamespace:      "ns",
+	}
+	expectedScParams := &StorageClassParams{
+		StoragePolicyName: "policy1",
+	}
+	actualScParams, err := ParseStorageClassParams(ctx, params)
+	if err != nil {
+		t.Errorf("failed to parse params: %+v", params)
+	}
+	if !isStorageClassParamsEqual(expectedScParams, actualScParams) {
+		t.Errorf("Expected: %+v\n Actual: %+v", expectedScParams, actualScParams)
+	}
+}
+
 func TestParseStorageClassParamsWithMigrationEnabledNagative(t *testing.T) {
 	params := map[string]string{
 		CSIMigrationParams:                   "true",
@@ -897,6 +963,10 @@ func (m *cbtFlagsMockVolumeManager) ExpandVolume(context.Context, string, int64,
 	interface{}) (string, error) {
 	return "", nil
 }
+func (m *cbtFlagsMockVolumeManager) UpdateVolumePolicy(context.Context, string, string,
+	*vim25types.ManagedObjectReference) (string, error) {
+	return "", nil
+}
 func (m *cbtFlagsMockVolumeManager) ResetManager(context.Context,
 	*cnsvsphere.VirtualCenter) error {
 	return nil
@@ -1158,3 +1228,62 @@ func TestIsValidChangeId(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMkfsOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsType      string
+		mkfsOptions string
+		want        []string
+		wantErr     bool
+	}{
+		{name: "empty options", fsType: Ext4FsType, mkfsOptions: "", want: nil},
+		{name: "ext4 options", fsType: Ext4FsType, mkfsOptions: "-b 4096  -i 8192",
+			want: []string{"-b", "4096", "-i", "8192"}},
+		{name: "default fstype", fsType: "", mkfsOptions: "-m 1", want: []string{"-m", "1"}},
+		{name: "xfs options", fsType: XFSType, mkfsOptions: "-n size=8192", want: []string{"-n", "size=8192"}},
+		{name: "btrfs options", fsType: BtrfsFsType, mkfsOptions: "-L data", want: []string{"-L", "data"}},
+		{name: "option not allowed", fsType: Ext4FsType, mkfsOptions: "-F -b 4096", wantErr: true},
+		{name: "option not allowed for fstype", fsType: XFSType, mkfsOptions: "-E stride=16", wantErr: true},
+		{name: "missing value", fsType: Ext4FsType, mkfsOptions: "-b", wantErr: true},
+		{name: "value is an option", fsType: Ext4FsType, mkfsOptions: "-b -i 8192", wantErr: true},
+		{name: "value is a path", fsType: Ext4FsType, mkfsOptions: "-L /dev/sdb", wantErr: true},
+		{name: "unsupported fstype", fsType: NfsV4FsType, mkfsOptions: "-b 4096", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMkfsOptions(tt.fsType, tt.mkfsOptions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMkfsOptions(%q, %q) error = %v, wantErr %v", tt.fsType, tt.mkfsOptions, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetVolumeCondition(t *testing.T) {
+	tests := []struct {
+		name         string
+		healthStatus string
+		wantAbnormal bool
+	}{
+		{name: "green", healthStatus: "green", wantAbnormal: false},
+		{name: "yellow", healthStatus: "yellow", wantAbnormal: false},
+		{name: "red", healthStatus: "red", wantAbnormal: true},
+		{name: "unknown", healthStatus: "unknown", wantAbnormal: false},
+		{name: "not set", healthStatus: "", wantAbnormal: false},
+		{name: "unexpected", healthStatus: "purple", wantAbnormal: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := GetVolumeCondition(ctx, "vol-1", tt.healthStatus)
+			if condition.GetAbnormal() != tt.wantAbnormal {
+				t.Errorf("GetVolumeCondition(%q) abnormal = %v, want %v", tt.healthStatus,
+					condition.GetAbnormal(), tt.wantAbnormal)
+			}
+			if condition.GetMessage() == "" {
+				t.Errorf("GetVolumeCondition(%q) returned empty message", tt.healthStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
+)
+
+const (
+	// VCCredentialRejectedReason is the reason of the event recorded when
+	// vCenter rejects rotated credentials.
+	VCCredentialRejectedReason = "VCCredentialRejected"
+	// VCHealthyReason is the reason of the event recorded when the connections
+	// to a vCenter succeed again.
+	VCHealthyReason = "VCHealthy"
+	// VCDegradedReason is the reason of the event recorded when the
+	// connections to a vCenter start failing.
+	VCDegradedReason = "VCDegraded"
+	// VCUnavailableReason is the reason of the event recorded when the circuit
+	// breaker of a vCenter opens.
+	VCUnavailableReason = "VCUnavailable"
+)
+
+// InitVCEventRecorder records events on the pod of the running container
+// whenever vCenter rejects rotated credentials, and whenever the health of the
+// connections of the container to a vCenter changes. As the controller and the
+// syncer run their own circuit breakers, each of them reports its own view of
+// the vCenters.
+func InitVCEventRecorder(ctx context.Context, component string) error {
+	log := logger.GetLogger(ctx)
+	podName, err := os.Hostname()
+	if err != nil {
+		return logger.LogNewErrorf(log, "failed to get pod name. Error: %v", err)
+	}
+	k8sClient, err := k8s.NewClient(ctx)
+	if err != nil {
+		return logger.LogNewErrorf(log, "failed to create kubernetes client. Error: %v", err)
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(
+		&typedcorev1.EventSinkImpl{
+			Interface: k8sClient.CoreV1().Events(""),
+		},
+	)
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: component})
+	pod := &v1.ObjectReference{
+		Kind:       "Pod",
+		APIVersion: "v1",
+		Namespace:  GetCSINamespace(),
+		Name:       podName,
+	}
+	cnsvsphere.SetCredentialRejectedHandler(func(ctx context.Context, vcHost string, err error) {
+		recorder.Eventf(pod, v1.EventTypeWarning, VCCredentialRejectedReason,
+			"vCenter %q rejected the rotated credentials: %v", vcHost, err)
+	})
+	cnsvsphere.SetHealthChangedHandler(func(ctx context.Context, vcHost string, health cnsvsphere.VCenterHealth) {
+		switch health.State {
+		case cnsvsphere.HealthStateHealthy:
+			recorder.Eventf(pod, v1.EventTypeNormal, VCHealthyReason, "vCenter %q is healthy", vcHost)
+		case cnsvsphere.HealthStateDegraded:
+			recorder.Eventf(pod, v1.EventTypeWarning, VCDegradedReason,
+				"connections to vCenter %q are failing: %v", vcHost, health.LastError)
+		case cnsvsphere.HealthStateDown:
+			recorder.Eventf(pod, v1.EventTypeWarning, VCUnavailableReason,
+				"vCenter %q is unavailable, failing its requests fast until it recovers: %v",
+				vcHost, health.LastError)
+		}
+	})
+	return nil
+}
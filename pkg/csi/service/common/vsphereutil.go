@@ -1197,6 +1197,34 @@ func CreateSnapshotUtil(ctx context.Context, volumeManager cnsvolume.Manager, vo
 	return csiSnapshotID, cnsSnapshotInfo, nil
 }
 
+// QueryVolumeWithHealthStatusUtil queries CNS for the volume with the given
+// volumeID, selecting its type, backing object details and health status.
+// A codes.NotFound error is returned if CNS does not know the volume.
+func QueryVolumeWithHealthStatusUtil(ctx context.Context, volumeManager cnsvolume.Manager, volumeID string) (
+	*cnstypes.CnsVolume, string, error) {
+	log := logger.GetLogger(ctx)
+	queryFilter := cnstypes.CnsQueryFilter{
+		VolumeIds: []cnstypes.CnsVolumeId{{Id: volumeID}},
+	}
+	querySelection := cnstypes.CnsQuerySelection{
+		Names: []string{
+			string(cnstypes.QuerySelectionNameTypeVolumeType),
+			string(cnstypes.QuerySelectionNameTypeBackingObjectDetails),
+			string(cnstypes.QuerySelectionNameTypeHealthStatus),
+		},
+	}
+	queryResult, err := utils.QueryVolumeUtil(ctx, volumeManager, queryFilter, &querySelection)
+	if err != nil {
+		return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+			"failed to query volume %q. Error: %+v", volumeID, err)
+	}
+	if len(queryResult.Volumes) == 0 {
+		return nil, csifault.CSINotFoundFault, logger.LogNewErrorCodef(log, codes.NotFound,
+			"volume %q not found", volumeID)
+	}
+	return &queryResult.Volumes[0], "", nil
+}
+
 // DeleteSnapshotUtil is the helper function to delete CNS snapshot for given snapshotId
 func DeleteSnapshotUtil(ctx context.Context, volumeManager cnsvolume.Manager, csiSnapshotID string,
 	extraParams interface{}) (*cnsvolume.CnsSnapshotInfo, error) {
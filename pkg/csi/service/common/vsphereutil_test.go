@@ -80,6 +80,10 @@ func (m *mockVolumeManager) ExpandVolume(ctx context.Context, volumeID string, s
 	extraParams interface{}) (string, error) {
 	return "", nil
 }
+func (m *mockVolumeManager) UpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+	targetDatastore *types.ManagedObjectReference) (string, error) {
+	return "", nil
+}
 func (m *mockVolumeManager) ResetManager(ctx context.Context, vcenter *vsphere.VirtualCenter) error {
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/node"
 	cnsconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
@@ -194,6 +195,10 @@ func (driver *vsphereCSIDriver) BeforeServe(ctx context.Context) error {
 			}
 		}
 	}
+	if err := prometheus.ConfigureOptionalLabels(cfg.Metrics.OptionalLabels,
+		cfg.Metrics.StoragePolicies); err != nil {
+		return logger.LogNewErrorf(log, "invalid Metrics configuration. Error: %v", err)
+	}
 	if err := driver.cnscs.Init(cfg, Version); err != nil {
 		log.Errorf("failed to init controller. Error: %+v", err)
 		return err
@@ -0,0 +1,304 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// SubsystemFullSync is the subsystem of the full sync logs.
+	SubsystemFullSync = "fullsync"
+	// SubsystemListView is the subsystem of the ListView task tracking logs.
+	SubsystemListView = "listview"
+	// SubsystemTopology is the subsystem of the topology logs.
+	SubsystemTopology = "topology"
+	// SubsystemAdmission is the subsystem of the admission webhook logs.
+	SubsystemAdmission = "admission"
+
+	// DefaultLogLevelTTL is the time after which a log level set through the
+	// log level endpoint is reverted if no TTL is given.
+	DefaultLogLevelTTL = 30 * time.Minute
+)
+
+// levelState holds the log levels in effect. It is replaced as a whole on
+// every change so that it can be read without locking on every log call.
+type levelState struct {
+	global     zapcore.Level
+	subsystems map[string]zapcore.Level
+}
+
+var (
+	// levels holds the current levelState. A nil value means that the default
+	// level applies to all subsystems.
+	levels atomic.Pointer[levelState]
+	// levelMutex serializes the changes of levels and revertTimers.
+	levelMutex sync.Mutex
+	// revertTimers holds the timers reverting the levels set with a TTL, keyed
+	// by subsystem. The global level uses the empty key.
+	revertTimers = map[string]*time.Timer{}
+)
+
+// levelCore is a zapcore.Core which filters the entries using the level of
+// its subsystem, which can be changed at runtime. The wrapped core is built
+// with the lowest level so that it does not filter any entry itself.
+type levelCore struct {
+	zapcore.Core
+	subsystem string
+}
+
+// Enabled returns true if the level is enabled for the subsystem of the core.
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= getLevel(c.subsystem)
+}
+
+// Level returns the level of the subsystem of the core.
+func (c *levelCore) Level() zapcore.Level {
+	return getLevel(c.subsystem)
+}
+
+// With adds structured context to the core.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), subsystem: c.subsystem}
+}
+
+// Check adds the wrapped core to the checked entry if the level of the entry
+// is enabled for the subsystem of the core.
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// WithSubsystem returns a new child context whose logger logs with the level
+// of the given subsystem. The subsystem is added to the name of the logger.
+func WithSubsystem(ctx context.Context, subsystem string) context.Context {
+	subsystemLogger := getLogger(ctx).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			return &levelCore{Core: lc.Core, subsystem: subsystem}
+		}
+		return core
+	})).Named(subsystem)
+	return context.WithValue(ctx, loggerKey{}, subsystemLogger)
+}
+
+// getDefaultLevel returns the level used when no level is set at runtime.
+func getDefaultLevel() zapcore.Level {
+	if defaultLogLevel == DevelopmentLogLevel {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// getLevel returns the level in effect for the subsystem. The global level
+// applies to the subsystems without a level of their own.
+func getLevel(subsystem string) zapcore.Level {
+	state := levels.Load()
+	if state == nil {
+		return getDefaultLevel()
+	}
+	if level, ok := state.subsystems[subsystem]; ok && subsystem != "" {
+		return level
+	}
+	return state.global
+}
+
+// SetLevel sets the log level of the subsystem, or the global level if the
+// subsystem is empty. If ttl is positive, the level is reverted after ttl.
+func SetLevel(subsystem string, level zapcore.Level, ttl time.Duration) {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	state := copyLevelState()
+	if subsystem == "" {
+		state.global = level
+	} else {
+		state.subsystems[subsystem] = level
+	}
+	levels.Store(state)
+
+	if timer, ok := revertTimers[subsystem]; ok {
+		timer.Stop()
+		delete(revertTimers, subsystem)
+	}
+	if ttl > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			levelMutex.Lock()
+			defer levelMutex.Unlock()
+			// The level may have been set again after the timer fired.
+			if revertTimers[subsystem] != timer {
+				return
+			}
+			resetLevel(subsystem)
+			GetLoggerWithNoContext().Infof("Reverted the log level of %s after %v", levelName(subsystem), ttl)
+		})
+		revertTimers[subsystem] = timer
+	}
+}
+
+// ResetLevel reverts the log level of the subsystem to the global level, or
+// the global level to the default level if the subsystem is empty.
+func ResetLevel(subsystem string) {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	resetLevel(subsystem)
+}
+
+// resetLevel reverts the log level of the subsystem. The caller must hold
+// levelMutex.
+func resetLevel(subsystem string) {
+	if timer, ok := revertTimers[subsystem]; ok {
+		timer.Stop()
+		delete(revertTimers, subsystem)
+	}
+	state := copyLevelState()
+	if subsystem == "" {
+		state.global = getDefaultLevel()
+	} else {
+		delete(state.subsystems, subsystem)
+	}
+	levels.Store(state)
+}
+
+// copyLevelState returns a copy of the current levelState. The caller must
+// hold levelMutex.
+func copyLevelState() *levelState {
+	state := &levelState{global: getDefaultLevel(), subsystems: map[string]zapcore.Level{}}
+	if current := levels.Load(); current != nil {
+		state.global = current.global
+		for subsystem, level := range current.subsystems {
+			state.subsystems[subsystem] = level
+		}
+	}
+	return state
+}
+
+func levelName(subsystem string) string {
+	if subsystem == "" {
+		return "all subsystems"
+	}
+	return fmt.Sprintf("subsystem %q", subsystem)
+}
+
+// LogLevels is the representation of the log levels in effect returned by
+// the log level endpoint.
+type LogLevels struct {
+	Global     string            `json:"global"`
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+}
+
+// LogLevelRequest is the body of a PUT request to the log level endpoint.
+type LogLevelRequest struct {
+	// Level is the zap level to set, e.g. "debug".
+	Level string `json:"level"`
+	// Subsystem is the subsystem whose level is set. The global level is set
+	// if it is empty.
+	Subsystem string `json:"subsystem,omitempty"`
+	// TTL is the duration after which the level is reverted, e.g. "15m".
+	// DefaultLogLevelTTL is used if it is empty and "0s" disables the revert.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// GetLevels returns the log levels in effect.
+func GetLevels() LogLevels {
+	state := levels.Load()
+	if state == nil {
+		return LogLevels{Global: getDefaultLevel().String()}
+	}
+	logLevels := LogLevels{Global: state.global.String()}
+	if len(state.subsystems) > 0 {
+		logLevels.Subsystems = make(map[string]string, len(state.subsystems))
+		for subsystem, level := range state.subsystems {
+			logLevels.Subsystems[subsystem] = level.String()
+		}
+	}
+	return logLevels
+}
+
+// NewLogLevelHandler returns an http.Handler which changes the log levels at
+// runtime. GET returns the levels in effect, PUT sets a level using a
+// LogLevelRequest body and DELETE reverts the level of the subsystem given in
+// the "subsystem" query parameter, or the global level if it is not given.
+func NewLogLevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := GetLoggerWithNoContext()
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req LogLevelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("failed to decode request: %v", err), http.StatusBadRequest)
+				return
+			}
+			level, err := zapcore.ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ttl := DefaultLogLevelTTL
+			if req.TTL != "" {
+				ttl, err = time.ParseDuration(req.TTL)
+				if err != nil || ttl < 0 {
+					http.Error(w, fmt.Sprintf("invalid ttl %q", req.TTL), http.StatusBadRequest)
+					return
+				}
+			}
+			SetLevel(req.Subsystem, level, ttl)
+			log.Infof("Set the log level of %s to %q with ttl %v", levelName(req.Subsystem), level, ttl)
+		case http.MethodDelete:
+			subsystem := r.URL.Query().Get("subsystem")
+			ResetLevel(subsystem)
+			log.Infof("Reverted the log level of %s", levelName(subsystem))
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(GetLevels()); err != nil {
+			log.Errorf("failed to encode log levels. Error: %v", err)
+		}
+	})
+}
+
+// StartLogLevelServer serves the log level endpoint at /loglevel on the given
+// address in the background. The server is not started if addr is empty.
+func StartLogLevelServer(ctx context.Context, addr string) {
+	log := GetLogger(ctx)
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/loglevel", NewLogLevelHandler())
+	go func() {
+		log.Infof("Starting the log level server on %s", addr)
+		server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		if err := server.ListenAndServe(); err != nil {
+			log.Errorf("log level server on %s stopped. Error: %v", addr, err)
+		}
+	}()
+}
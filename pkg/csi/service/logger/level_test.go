@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObservedContext returns a context whose logger writes to the returned
+// observer through a levelCore.
+func newObservedContext(t *testing.T) (context.Context, *observer.ObservedLogs) {
+	t.Cleanup(func() {
+		ResetLevel(SubsystemFullSync)
+		ResetLevel("")
+		levels.Store(nil)
+	})
+	core, logs := observer.New(zapcore.DebugLevel)
+	observedLogger := zap.New(&levelCore{Core: core})
+	return context.WithValue(context.Background(), loggerKey{}, observedLogger), logs
+}
+
+func TestSubsystemLogLevel(t *testing.T) {
+	ctx, logs := newObservedContext(t)
+	fullSyncCtx := WithSubsystem(ctx, SubsystemFullSync)
+
+	GetLogger(ctx).Debug("global debug")
+	GetLogger(fullSyncCtx).Debug("fullsync debug")
+	assert.Zero(t, logs.Len())
+
+	// Raising the verbosity of a subsystem does not change the others.
+	SetLevel(SubsystemFullSync, zapcore.DebugLevel, 0)
+	GetLogger(ctx).Debug("global debug")
+	GetLogger(fullSyncCtx).With("vc", "vc1").Debug("fullsync debug")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "fullsync debug", logs.All()[0].Message)
+	assert.Equal(t, SubsystemFullSync, logs.All()[0].LoggerName)
+
+	// A subsystem level takes precedence over the global level.
+	SetLevel("", zapcore.ErrorLevel, 0)
+	GetLogger(ctx).Info("global info")
+	GetLogger(fullSyncCtx).Info("fullsync info")
+	assert.Equal(t, 2, logs.Len())
+
+	ResetLevel(SubsystemFullSync)
+	GetLogger(fullSyncCtx).Info("fullsync info")
+	assert.Equal(t, 2, logs.Len())
+	assert.Equal(t, LogLevels{Global: "error"}, GetLevels())
+}
+
+func TestLogLevelRevertedAfterTTL(t *testing.T) {
+	ctx, logs := newObservedContext(t)
+	SetLevel("", zapcore.DebugLevel, 50*time.Millisecond)
+	GetLogger(ctx).Debug("debug")
+	assert.Equal(t, 1, logs.Len())
+	assert.Eventually(t, func() bool {
+		return GetLevels().Global == zapcore.InfoLevel.String()
+	}, 5*time.Second, 10*time.Millisecond)
+	GetLogger(ctx).Debug("debug")
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	newObservedContext(t)
+	handler := NewLogLevelHandler()
+
+	req := httptest.NewRequest(http.MethodPut, "/loglevel",
+		strings.NewReader(`{"level": "debug", "subsystem": "listview", "ttl": "1h"}`))
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var logLevels LogLevels
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &logLevels))
+	assert.Equal(t, LogLevels{Global: "info", Subsystems: map[string]string{SubsystemListView: "debug"}}, logLevels)
+
+	req = httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(`{"level": "verbose"}`))
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/loglevel?subsystem=listview", nil)
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, LogLevels{Global: "info"}, GetLevels())
+}
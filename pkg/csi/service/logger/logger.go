@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc/codes"
@@ -62,9 +63,14 @@ func GetLogger(ctx context.Context) *zap.SugaredLogger {
 }
 
 // NewContextWithLogger returns a new child context with context UUID set
-// using key CtxId.
+// using key CtxId. If ctx holds a trace span, its trace ID is used instead of
+// a random UUID so that the logs can be correlated with the trace.
 func NewContextWithLogger(ctx context.Context) context.Context {
-	newCtx := withFields(ctx, zap.String(LogCtxIDKey, uuid.New().String()))
+	traceID := uuid.New().String()
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		traceID = spanContext.TraceID().String()
+	}
+	newCtx := withFields(ctx, zap.String(LogCtxIDKey, traceID))
 	return newCtx
 }
 
@@ -97,13 +103,19 @@ func newLogger() *zap.Logger {
 		return logger
 	}
 
+	// The level of the entries is checked by levelCore, so that it can be
+	// changed at runtime, instead of the level of the config.
+	wrapLevelCore := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core}
+	})
 	if defaultLogLevel == DevelopmentLogLevel {
-		logger, _ = zap.NewDevelopment()
+		logger, _ = zap.NewDevelopment(wrapLevelCore)
 	} else {
 		loggerConfig := zap.NewProductionConfig()
+		loggerConfig.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
 		loggerConfig.EncoderConfig.TimeKey = "time"
 		loggerConfig.EncoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
-		logger, _ = loggerConfig.Build()
+		logger, _ = loggerConfig.Build(wrapLevelCore)
 	}
 
 	return logger
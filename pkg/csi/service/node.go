@@ -18,7 +18,9 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	cnstypes "github.com/vmware/govmomi/cns/types"
@@ -26,6 +28,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	cnsconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
 	commoncotypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco/types"
@@ -48,10 +51,37 @@ const (
 
 var topologyService commoncotypes.NodeTopologyService
 
+// nodeVolumeType returns the volume type reported in the node metrics for the
+// given volume capability.
+func nodeVolumeType(ctx context.Context, volCap *csi.VolumeCapability) string {
+	if volCap == nil {
+		return prometheus.PrometheusUnknownVolumeType
+	}
+	if common.IsFileVolumeRequest(ctx, []*csi.VolumeCapability{volCap}) {
+		return prometheus.PrometheusFileVolumeType
+	}
+	return prometheus.PrometheusBlockVolumeType
+}
+
+// observeNodeOp records the latency of a node operation which started at
+// start in NodeOpsHistVec.
+func observeNodeOp(volumeType, opType string, start time.Time, err error) {
+	status := prometheus.PrometheusPassStatus
+	if err != nil {
+		status = prometheus.PrometheusFailStatus
+	}
+	prometheus.NodeOpsHistVec.WithLabelValues(volumeType, opType, status).Observe(time.Since(start).Seconds())
+}
+
 func (driver *vsphereCSIDriver) NodeStageVolume(
 	ctx context.Context,
 	req *csi.NodeStageVolumeRequest) (
-	*csi.NodeStageVolumeResponse, error) {
+	_ *csi.NodeStageVolumeResponse, retErr error) {
+	start := time.Now()
+	defer func() {
+		observeNodeOp(nodeVolumeType(ctx, req.GetVolumeCapability()),
+			prometheus.PrometheusNodeStageVolumeOpType, start, retErr)
+	}()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	log.Infof("NodeStageVolume: called with args %+v", req)
@@ -100,6 +130,12 @@ func (driver *vsphereCSIDriver) NodeStageVolume(
 		if err != nil {
 			return nil, err
 		}
+		params.MkfsArgs, err = common.ParseMkfsOptions(params.FsType,
+			req.GetVolumeContext()[common.AttributeMkfsOptions])
+		if err != nil {
+			return nil, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+				"NodeStageVolume failed: invalid mkfs options. Err: %+v", err)
+		}
 
 		// Check that staging path is created by CO and is a directory.
 		params.StagingTarget = req.GetStagingTargetPath()
@@ -178,7 +214,12 @@ func (driver *vsphereCSIDriver) NodeUnstageVolume(
 func (driver *vsphereCSIDriver) NodePublishVolume(
 	ctx context.Context,
 	req *csi.NodePublishVolumeRequest) (
-	*csi.NodePublishVolumeResponse, error) {
+	_ *csi.NodePublishVolumeResponse, retErr error) {
+	start := time.Now()
+	defer func() {
+		observeNodeOp(nodeVolumeType(ctx, req.GetVolumeCapability()),
+			prometheus.PrometheusNodePublishVolumeOpType, start, retErr)
+	}()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	log.Infof("NodePublishVolume: called with args %+v", req)
@@ -300,9 +341,31 @@ func (driver *vsphereCSIDriver) NodeGetVolumeStats(
 			"received empty targetpath %q", targetPath)
 	}
 
+	// Check the volume condition first, as collecting metrics fails or hangs
+	// on a volume whose backing device or NFS mount is gone. Kubelet rejects
+	// responses without usage, so zeroed usage is reported along with the
+	// condition of such a volume.
+	volCondition, err := driver.osUtils.GetVolumeCondition(ctx, volumeID, targetPath)
+	if err != nil {
+		log.Warnf("failed to get volume condition for volume %q at %q. Err: %v", volumeID, targetPath, err)
+	} else if volCondition.Abnormal {
+		log.Warnf("volume %q at %q is abnormal: %s", volumeID, targetPath, volCondition.Message)
+		return &csi.NodeGetVolumeStatsResponse{
+			Usage:           zeroVolumeUsage(),
+			VolumeCondition: volCondition,
+		}, nil
+	}
+
 	volMetrics, err := driver.osUtils.GetMetrics(ctx, targetPath)
 	if err != nil {
-		return nil, logger.LogNewErrorCode(log, codes.Internal, err.Error())
+		log.Warnf("failed to get metrics of volume %q at %q. Err: %v", volumeID, targetPath, err)
+		return &csi.NodeGetVolumeStatsResponse{
+			Usage: zeroVolumeUsage(),
+			VolumeCondition: &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  fmt.Sprintf("failed to get the usage of volume %q at %q: %v", volumeID, targetPath, err),
+			},
+		}, nil
 	}
 
 	available, ok := (*(volMetrics.Available)).AsInt64()
@@ -344,9 +407,19 @@ func (driver *vsphereCSIDriver) NodeGetVolumeStats(
 				Unit:      csi.VolumeUsage_INODES,
 			},
 		},
+		VolumeCondition: volCondition,
 	}, nil
 }
 
+// zeroVolumeUsage returns the zeroed byte and inode usage reported for
+// volumes whose usage cannot be collected.
+func zeroVolumeUsage() []*csi.VolumeUsage {
+	return []*csi.VolumeUsage{
+		{Unit: csi.VolumeUsage_BYTES},
+		{Unit: csi.VolumeUsage_INODES},
+	}
+}
+
 func (driver *vsphereCSIDriver) NodeGetCapabilities(
 	ctx context.Context,
 	req *csi.NodeGetCapabilitiesRequest) (
@@ -375,6 +448,20 @@ func (driver *vsphereCSIDriver) NodeGetCapabilities(
 					},
 				},
 			},
+			{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{
+						Type: csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+					},
+				},
+			},
+			{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{
+						Type: csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
+					},
+				},
+			},
 		},
 	}, nil
 }
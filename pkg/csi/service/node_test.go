@@ -27,6 +27,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	cnsconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco/k8sorchestrator"
 )
 
@@ -312,6 +313,23 @@ func TestNodeGetVolumeStats_InvalidArguments(t *testing.T) {
 	}
 }
 
+func TestNodeGetVolumeStats_UnmountedVolume(t *testing.T) {
+	ctx := context.Background()
+	driver := NewDriver().(*vsphereCSIDriver)
+
+	resp, err := driver.NodeGetVolumeStats(ctx, &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   "test-volume-id",
+		VolumePath: t.TempDir(),
+	})
+
+	// The abnormal condition is reported along with zeroed usage, as kubelet
+	// rejects responses without usage.
+	assert.NoError(t, err)
+	assert.True(t, resp.GetVolumeCondition().GetAbnormal())
+	assert.Contains(t, resp.GetVolumeCondition().GetMessage(), "is not mounted")
+	assert.Equal(t, zeroVolumeUsage(), resp.GetUsage())
+}
+
 func TestNodeExpandVolume_InvalidArguments(t *testing.T) {
 	ctx := context.Background()
 	driver := NewDriver().(*vsphereCSIDriver)
@@ -369,6 +387,8 @@ func TestNodeGetCapabilities(t *testing.T) {
 		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
 		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+		csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	}
 
 	actualCaps := make([]csi.NodeServiceCapability_RPC_Type, 0)
@@ -453,3 +473,26 @@ func TestOsUtilsInitialization(t *testing.T) {
 	// OsUtils is not initialized when CO initialization fails because it happens after CO init
 	assert.Nil(t, driver.osUtils, "OsUtils should not be initialized when CO initialization fails")
 }
+
+func TestNodeVolumeType(t *testing.T) {
+	ctx := context.Background()
+	blockVolCap := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{
+			Mount: &csi.VolumeCapability_MountVolume{FsType: "ext4"},
+		},
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		},
+	}
+	fileVolCap := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{
+			Mount: &csi.VolumeCapability_MountVolume{FsType: "nfs4"},
+		},
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		},
+	}
+	assert.Equal(t, prometheus.PrometheusBlockVolumeType, nodeVolumeType(ctx, blockVolCap))
+	assert.Equal(t, prometheus.PrometheusFileVolumeType, nodeVolumeType(ctx, fileVolCap))
+	assert.Equal(t, prometheus.PrometheusUnknownVolumeType, nodeVolumeType(ctx, nil))
+}
@@ -25,7 +25,9 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/akutz/gofsutil"
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -38,6 +40,7 @@ import (
 	"k8s.io/mount-utils"
 	utilexec "k8s.io/utils/exec"
 
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/mounter"
@@ -48,6 +51,11 @@ const (
 	blockPrefix = "wwn-0x"
 	dmiDir      = "/sys/class/dmi"
 	UUIDPrefix  = "VMware-"
+
+	procMountInfoPath = "/proc/self/mountinfo"
+	// volumeConditionStatTimeout is the time to wait for a stat of an NFS
+	// mount before reporting it as not responding.
+	volumeConditionStatTimeout = 10 * time.Second
 )
 
 // defaultFileMountOptions are the mount flag options used by default while publishing a file volume.
@@ -149,9 +157,11 @@ func (osUtils *OsUtils) getDiskFormat(ctx context.Context, disk string) (string,
 	return fstype, nil
 }
 
-// xfsFormatAndMount mounts volume to the staging path for xfs fstype
-func (osUtils *OsUtils) xfsFormatAndMount(ctx context.Context, source string, target string,
-	fstype string, opts ...string) error {
+// formatAndMount formats the volume with the given mkfs arguments if it is
+// unformatted and mounts it to the staging path. It is used for xfs and btrfs,
+// and for ext3/ext4 when mkfs arguments are given.
+func (osUtils *OsUtils) formatAndMount(ctx context.Context, source string, target string,
+	fstype string, mkfsArgs []string, opts ...string) error {
 	log := logger.GetLogger(ctx)
 	// Check if the disk is already formatted
 	existingFormat, err := osUtils.getDiskFormat(ctx, source)
@@ -169,27 +179,26 @@ func (osUtils *OsUtils) xfsFormatAndMount(ctx context.Context, source string, ta
 		// These options are compatible with Linux kernel versions 5.10 and later.
 		// To create a new filesystem that will be compatible with the older kernel versions, we need to disable
 		// these new features by adding -m bigtime=0,inobtcount=0 to the mkfs.xfs command.
-		kernel, major, err := getKernelVersion(ctx)
-		if err != nil {
-			log.Errorf("xfsFormatAndMount: error while getting kernel version, err: %v", err)
-			return err
-		}
 		var args []string
-		if kernel >= 5 && major >= 10 {
-			args = []string{
-				source,
+		switch fstype {
+		case common.XFSType:
+			kernel, major, err := getKernelVersion(ctx)
+			if err != nil {
+				log.Errorf("formatAndMount: error while getting kernel version, err: %v", err)
+				return err
 			}
-		} else {
-			args = []string{
-				"-m",
-				"bigtime=0",
-				"-m",
-				"inobtcount=0",
-				source,
+			if !(kernel >= 5 && major >= 10) {
+				args = append(args, "-m", "bigtime=0", "-m", "inobtcount=0")
 			}
+		case common.Ext3FsType, common.Ext4FsType:
+			// Start from the defaults mount-utils uses: force mkfs.ext3/ext4 to
+			// format the whole device and reserve no blocks for the super-user.
+			args = append(args, "-F", "-m0")
 		}
+		args = append(args, mkfsArgs...)
+		args = append(args, source)
 
-		log.Infof("xfsFormatAndMount: Disk %q appears to be unformatted, attempting to format as type: %q "+
+		log.Infof("formatAndMount: Disk %q appears to be unformatted, attempting to format as type: %q "+
 			"with options: %v", source, fstype, args)
 		output, err := osUtils.Mounter.Exec.Command("mkfs."+fstype, args...).CombinedOutput()
 		if err != nil {
@@ -199,14 +208,14 @@ func (osUtils *OsUtils) xfsFormatAndMount(ctx context.Context, source string, ta
 			return errors.New(detailedErr)
 		}
 
-		log.Infof("xfsFormatAndMount: Disk successfully formatted (mkfs): %s - %s %s", fstype, source, target)
+		log.Infof("formatAndMount: Disk successfully formatted (mkfs): %s - %s %s", fstype, source, target)
 	}
 
 	// Mount the disk
-	log.Infof("xfsFormatAndMount: Attempting to mount disk %s in %s format at %s", source, fstype, target)
+	log.Infof("formatAndMount: Attempting to mount disk %s in %s format at %s", source, fstype, target)
 	err = osUtils.Mounter.Mount(source, target, fstype, opts)
 	if err != nil {
-		log.Errorf("xfsFormatAndMount: mount of disk %s failed: type:(%q) target:(%q) errcode:(%v)",
+		log.Errorf("formatAndMount: mount of disk %s failed: type:(%q) target:(%q) errcode:(%v)",
 			source, fstype, target, err)
 		return errors.New(err.Error())
 	}
@@ -230,7 +239,14 @@ func (osUtils *OsUtils) NodeStageBlockVolume(
 
 	// Verify if the volume is attached.
 	log.Debugf("nodeStageBlockVolume: Checking if volume is attached to diskID: %v", diskID)
+	discoveryStart := time.Now()
 	volPath, err := osUtils.VerifyVolumeAttached(ctx, diskID)
+	discoveryStatus := prometheus.PrometheusPassStatus
+	if err != nil {
+		discoveryStatus = prometheus.PrometheusFailStatus
+	}
+	prometheus.NodeDeviceDiscoveryHistVec.WithLabelValues(discoveryStatus).Observe(
+		time.Since(discoveryStart).Seconds())
 	if err != nil {
 		log.Errorf("Error checking if volume %q is attached. Parameters: %v", params.VolID, params)
 		return nil, err
@@ -281,10 +297,11 @@ func (osUtils *OsUtils) NodeStageBlockVolume(
 		// Format and mount the device.
 		log.Debugf("nodeStageBlockVolume: Format and mount the device %q at %q with mount flags %v",
 			dev.FullPath, params.StagingTarget, params.MntFlags)
-		if params.FsType == "xfs" {
-			// use internal function for XFS mount, as we want to provide few parameters for mkfs command
-			// which are specific to XFS filesystem
-			err := osUtils.xfsFormatAndMount(ctx, dev.FullPath, params.StagingTarget, params.FsType, params.MntFlags...)
+		if params.FsType == common.XFSType || params.FsType == common.BtrfsFsType || len(params.MkfsArgs) != 0 {
+			// use internal function for XFS and btrfs mount, and when mkfs options are given, as we want to
+			// provide parameters for mkfs command which are specific to the filesystem
+			err := osUtils.formatAndMount(ctx, dev.FullPath, params.StagingTarget, params.FsType,
+				params.MkfsArgs, params.MntFlags...)
 			if err != nil {
 				return nil, logger.LogNewErrorCodef(log, codes.Internal,
 					"error in formating and mounting volume. Parameters: %v err: %v", params, err)
@@ -604,6 +621,19 @@ func (osUtils *OsUtils) PublishMountVol(
 			"volume ID: %q does not appear staged to %q", req.GetVolumeId(), params.StagingTarget)
 	}
 
+	// A ReadWriteOncePod volume must not be published to more than one target.
+	if req.GetVolumeCapability().GetAccessMode().GetMode() ==
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER {
+		for _, m := range devMnts {
+			mountPath := unescape(ctx, m.Path)
+			if mountPath != params.StagingTarget && mountPath != params.Target {
+				return nil, logger.LogNewErrorCodef(log, codes.FailedPrecondition,
+					"volume ID: %q with SINGLE_NODE_SINGLE_WRITER access mode is already published to %q",
+					req.GetVolumeId(), mountPath)
+			}
+		}
+	}
+
 	// Do the bind mount to publish the volume.
 	mntFlags = append(mntFlags, "bind")
 	if params.Ro {
@@ -670,6 +700,12 @@ func (osUtils *OsUtils) PublishBlockVol(
 	} else if len(devMnts) == 1 {
 		// Already mounted, make sure it's what we want.
 		if unescape(ctx, devMnts[0].Path) != params.Target {
+			if req.GetVolumeCapability().GetAccessMode().GetMode() ==
+				csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER {
+				return nil, logger.LogNewErrorCodef(log, codes.FailedPrecondition,
+					"volume ID: %q with SINGLE_NODE_SINGLE_WRITER access mode is already published to %q",
+					req.GetVolumeId(), unescape(ctx, devMnts[0].Path))
+			}
 			return nil, logger.LogNewErrorCode(log, codes.Internal,
 				"device already in use and mounted elsewhere")
 		}
@@ -1140,12 +1176,13 @@ func (osUtils *OsUtils) GetVolumeCapabilityFsType(ctx context.Context,
 		}
 	} else {
 		// For Block volumes we only support following filesystems:
-		// ext3, ext4 and xfs for Linux.
+		// ext3, ext4, xfs and btrfs for Linux.
 		if fsType == "" {
 			log.Infof("empty string fstype observed for block volume. Defaulting to: %s",
 				common.Ext4FsType)
 			fsType = common.Ext4FsType
-		} else if !(fsType == common.Ext4FsType || fsType == common.Ext3FsType || fsType == common.XFSType) {
+		} else if !(fsType == common.Ext4FsType || fsType == common.Ext3FsType || fsType == common.XFSType ||
+			fsType == common.BtrfsFsType) {
 			return "", logger.LogNewErrorCodef(log, codes.FailedPrecondition,
 				"unsupported fsType %q observed for block volume", fsType)
 		}
@@ -1275,3 +1312,150 @@ func (osUtils *OsUtils) IsBlockDevice(ctx context.Context, volumePath string) (b
 	}
 	return deviceInfo.Mode()&os.ModeDevice == os.ModeDevice, nil
 }
+
+// GetVolumeCondition inspects the mount of the volume at the given target
+// path and reports it as abnormal if the target is no longer mounted, the
+// backing device of a block volume is gone, the filesystem was remounted
+// read-only by the kernel, or the NFS mount of a file volume is stale.
+func (osUtils *OsUtils) GetVolumeCondition(ctx context.Context, volID string,
+	target string) (*csi.VolumeCondition, error) {
+	log := logger.GetLogger(ctx)
+	mountInfos, err := mount.ParseMountInfo(procMountInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mounts from %q. Err: %v", procMountInfoPath, err)
+	}
+	var targetMount *mount.MountInfo
+	for i := range mountInfos {
+		if unescape(ctx, mountInfos[i].MountPoint) == target {
+			targetMount = &mountInfos[i]
+		}
+	}
+	if targetMount == nil {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume %q is not mounted at %q", volID, target),
+		}, nil
+	}
+	log.Debugf("GetVolumeCondition: found mount %+v for volume %q", *targetMount, volID)
+
+	if targetMount.FsType == common.NfsFsType || targetMount.FsType == common.NfsV4FsType {
+		if err := statWithTimeout(target, volumeConditionStatTimeout); err != nil {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  fmt.Sprintf("NFS mount of volume %q at %q is stale or not responding: %v", volID, target, err),
+			}, nil
+		}
+		return &csi.VolumeCondition{Abnormal: false, Message: "volume is healthy"}, nil
+	}
+
+	dev, err := osUtils.GetDevFromMount(ctx, target)
+	if err != nil {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("failed to get backing device of volume %q at %q: %v", volID, target, err),
+		}, nil
+	}
+	if dev == nil {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("backing device of volume %q mounted at %q no longer exists", volID, target),
+		}, nil
+	}
+	diskID, err := getDiskIDForDevice(dev.RealDev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up disk ID of device %q. Err: %v", dev.RealDev, err)
+	}
+	if diskID == "" {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("backing device %q of volume %q is not attached to the node", dev.RealDev, volID),
+		}, nil
+	}
+	if isRemountedReadOnly(*targetMount) {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message: fmt.Sprintf("filesystem of volume %q at %q has been remounted read-only, "+
+				"possibly due to I/O errors", volID, target),
+		}, nil
+	}
+	return &csi.VolumeCondition{Abnormal: false, Message: "volume is healthy"}, nil
+}
+
+// getDiskIDForDevice returns the disk ID of the given device from its
+// /dev/disk/by-id entry, or an empty string if there is none.
+func getDiskIDForDevice(device string) (string, error) {
+	devs, err := os.ReadDir(devDiskID)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range devs {
+		if !strings.HasPrefix(f.Name(), blockPrefix) {
+			continue
+		}
+		realDev, err := filepath.EvalSymlinks(filepath.Join(devDiskID, f.Name()))
+		if err != nil {
+			continue
+		}
+		if realDev == device {
+			return strings.TrimPrefix(f.Name(), blockPrefix), nil
+		}
+	}
+	return "", nil
+}
+
+// isRemountedReadOnly returns true if the superblock of the mount is
+// read-only while the mount itself is read-write. This is the case when the
+// kernel remounts a filesystem read-only on errors, whereas a volume that is
+// published read-only has a read-only mount.
+func isRemountedReadOnly(mountInfo mount.MountInfo) bool {
+	hasOption := func(options []string, option string) bool {
+		for _, o := range options {
+			if o == option {
+				return true
+			}
+		}
+		return false
+	}
+	return hasOption(mountInfo.MountOptions, "rw") && hasOption(mountInfo.SuperOptions, "ro")
+}
+
+// inFlightStat is a stat of a volume path which may still be running.
+type inFlightStat struct {
+	done chan struct{}
+	err  error
+}
+
+var (
+	// inFlightStats holds the stats of volume paths which have not returned
+	// yet, so that a hung mount blocks at most one goroutine per path.
+	inFlightStats      = make(map[string]*inFlightStat)
+	inFlightStatsMutex sync.Mutex
+)
+
+// statWithTimeout stats the given path and returns an error if the stat
+// fails or does not return within the timeout, as it hangs on an
+// unresponsive hard NFS mount. If a stat of the path is already in flight,
+// its result is waited for instead of starting another stat.
+func statWithTimeout(path string, timeout time.Duration) error {
+	inFlightStatsMutex.Lock()
+	stat, ok := inFlightStats[path]
+	if !ok {
+		stat = &inFlightStat{done: make(chan struct{})}
+		inFlightStats[path] = stat
+		go func() {
+			_, stat.err = os.Stat(path)
+			inFlightStatsMutex.Lock()
+			delete(inFlightStats, path)
+			inFlightStatsMutex.Unlock()
+			close(stat.done)
+		}()
+	}
+	inFlightStatsMutex.Unlock()
+
+	select {
+	case <-stat.done:
+		return stat.err
+	case <-time.After(timeout):
+		return fmt.Errorf("stat did not return within %v", timeout)
+	}
+}
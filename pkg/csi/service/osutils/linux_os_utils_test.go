@@ -5,8 +5,16 @@ package osutils
 
 import (
 	"context"
+	"errors"
+	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
+	testingexec "k8s.io/utils/exec/testing"
 )
 
 func TestUnescape(t *testing.T) {
@@ -52,3 +60,132 @@ func TestUnescape(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRemountedReadOnly(t *testing.T) {
+	tests := []struct {
+		name         string
+		mountOptions []string
+		superOptions []string
+		expected     bool
+	}{
+		{
+			// Healthy read-write mount.
+			name:         "ReadWrite",
+			mountOptions: []string{"rw", "relatime"},
+			superOptions: []string{"rw", "errors=remount-ro"},
+			expected:     false,
+		},
+		{
+			// Filesystem remounted read-only by the kernel on errors.
+			name:         "RemountedReadOnly",
+			mountOptions: []string{"rw", "relatime"},
+			superOptions: []string{"ro", "errors=remount-ro"},
+			expected:     true,
+		},
+		{
+			// Volume published read-only.
+			name:         "PublishedReadOnly",
+			mountOptions: []string{"ro", "relatime"},
+			superOptions: []string{"ro"},
+			expected:     false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			out := isRemountedReadOnly(mount.MountInfo{
+				MountOptions: test.mountOptions,
+				SuperOptions: test.superOptions,
+			})
+			if out != test.expected {
+				t.Errorf("Expected isRemountedReadOnly to return %t for mount options %v and "+
+					"super options %v, got %t", test.expected, test.mountOptions, test.superOptions, out)
+			}
+		})
+	}
+}
+
+func TestStatWithTimeoutReusesInFlightStat(t *testing.T) {
+	path := t.TempDir()
+	// Simulate a stat of the path hung on an unresponsive mount.
+	hungStat := &inFlightStat{done: make(chan struct{})}
+	inFlightStatsMutex.Lock()
+	inFlightStats[path] = hungStat
+	inFlightStatsMutex.Unlock()
+	defer func() {
+		inFlightStatsMutex.Lock()
+		delete(inFlightStats, path)
+		inFlightStatsMutex.Unlock()
+	}()
+
+	for i := 0; i < 3; i++ {
+		if err := statWithTimeout(path, 10*time.Millisecond); err == nil {
+			t.Fatalf("Expected statWithTimeout to time out while the stat of %s is in flight", path)
+		}
+	}
+	inFlightStatsMutex.Lock()
+	if len(inFlightStats) != 1 || inFlightStats[path] != hungStat {
+		t.Errorf("Expected only the in-flight stat of %s, got %v", path, inFlightStats)
+	}
+	inFlightStatsMutex.Unlock()
+
+	// Once the stat returns, its result is reported.
+	hungStat.err = errors.New("stale file handle")
+	close(hungStat.done)
+	if err := statWithTimeout(path, time.Second); err != hungStat.err {
+		t.Errorf("Expected the result of the in-flight stat, got %v", err)
+	}
+}
+
+func TestStatWithTimeout(t *testing.T) {
+	path := t.TempDir()
+	if err := statWithTimeout(path, time.Second); err != nil {
+		t.Fatalf("Expected stat of %s to succeed, got %v", path, err)
+	}
+	if err := statWithTimeout(path+"/missing", time.Second); !os.IsNotExist(err) {
+		t.Errorf("Expected stat of a missing path to fail with not exist, got %v", err)
+	}
+	inFlightStatsMutex.Lock()
+	defer inFlightStatsMutex.Unlock()
+	if len(inFlightStats) != 0 {
+		t.Errorf("Expected no in-flight stats after the stats returned, got %v", inFlightStats)
+	}
+}
+
+func TestFormatAndMountExt4WithMkfsArgs(t *testing.T) {
+	var mkfsArgs []string
+	fakeExec := &testingexec.FakeExec{}
+	// blkid reports no filesystem, so the disk gets formatted.
+	fakeExec.CommandScript = append(fakeExec.CommandScript,
+		func(cmd string, args ...string) utilexec.Cmd {
+			return &testingexec.FakeCmd{
+				CombinedOutputScript: []testingexec.FakeAction{
+					func() ([]byte, []byte, error) { return nil, nil, nil },
+				},
+			}
+		},
+		func(cmd string, args ...string) utilexec.Cmd {
+			mkfsArgs = args
+			return &testingexec.FakeCmd{
+				CombinedOutputScript: []testingexec.FakeAction{
+					func() ([]byte, []byte, error) { return nil, nil, nil },
+				},
+			}
+		},
+	)
+	osUtils := &OsUtils{
+		Mounter: &mount.SafeFormatAndMount{Interface: mount.NewFakeMounter(nil), Exec: fakeExec},
+	}
+
+	err := osUtils.formatAndMount(context.Background(), "/dev/sdb", "/staging", "ext4",
+		[]string{"-b", "4096"})
+	if err != nil {
+		t.Fatalf("formatAndMount failed: %v", err)
+	}
+	want := []string{"-F", "-m0", "-b", "4096", "/dev/sdb"}
+	if strings.Join(mkfsArgs, " ") != strings.Join(want, " ") {
+		t.Errorf("unexpected mkfs.ext4 args, want %v, got %v", want, mkfsArgs)
+	}
+}
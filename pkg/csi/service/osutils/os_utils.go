@@ -39,6 +39,8 @@ type NodeStageParams struct {
 	StagingTarget string
 	// Mount flags/options intended to be used while running the mount command.
 	MntFlags []string
+	// Additional arguments for the mkfs command used to format the volume.
+	MkfsArgs []string
 	// Read-only flag.
 	Ro bool
 }
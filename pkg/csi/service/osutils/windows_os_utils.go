@@ -269,6 +269,20 @@ func (osUtils *OsUtils) GetMetrics(ctx context.Context, path string) (*k8svol.Me
 	return metrics, nil
 }
 
+// GetVolumeCondition reports the volume at the given target path as abnormal
+// if the target path no longer exists. Other conditions are not detected on
+// windows.
+func (osUtils *OsUtils) GetVolumeCondition(ctx context.Context, volID string,
+	target string) (*csi.VolumeCondition, error) {
+	if _, err := os.Stat(target); err != nil {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume %q is not accessible at %q: %v", volID, target, err),
+		}, nil
+	}
+	return &csi.VolumeCondition{Abnormal: false, Message: "volume is healthy"}, nil
+}
+
 // GetBlockSizeBytes returns the Block size in bytes
 func (osUtils *OsUtils) GetBlockSizeBytes(ctx context.Context, devicePath string) (int64, error) {
 	mounter, err := GetMounter(ctx, osUtils)
@@ -17,6 +17,8 @@ limitations under the License.
 package service
 
 import (
+	"context"
+	"errors"
 	"net"
 	"os"
 	"strings"
@@ -24,6 +26,10 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/tracing"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 
 	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
@@ -114,7 +120,8 @@ func (s *nonBlockingGRPCServer) serve(endpoint string, ids csi.IdentityServer,
 		return logger.LogNewErrorf(log, "failed to listen: %v", err)
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(),
+		vCenterAvailabilityInterceptor()))
 	s.server = server
 
 	// Register the CSI services.
@@ -165,3 +172,29 @@ func (s *nonBlockingGRPCServer) serve(endpoint string, ids csi.IdentityServer,
 	}
 	return nil
 }
+
+// vCenterAvailabilityInterceptor returns a gRPC interceptor which fails the
+// controller RPCs fast with codes.Unavailable while the circuit breakers of
+// all the vCenters are open, and returns codes.Unavailable for the RPCs which
+// failed because the circuit breaker of their vCenter is open.
+func vCenterAvailabilityInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, "/csi.v1.Controller/") ||
+			info.FullMethod == "/csi.v1.Controller/ControllerGetCapabilities" {
+			return handler(ctx, req)
+		}
+		if cnsvsphere.AllVCentersDown() {
+			return nil, status.Errorf(codes.Unavailable, "%s failed: %v", info.FullMethod,
+				cnsvsphere.ErrVCenterUnavailable)
+		}
+		// The handlers format the errors of failed connections without wrapping
+		// them, so the connections failing fast are tracked in the context.
+		ctx, vCenterUnavailable := cnsvsphere.WithVCenterUnavailableTracking(ctx)
+		resp, err := handler(ctx, req)
+		if err != nil && (errors.Is(err, cnsvsphere.ErrVCenterUnavailable) || vCenterUnavailable()) {
+			return resp, status.Error(codes.Unavailable, status.Convert(err).Message())
+		}
+		return resp, err
+	}
+}
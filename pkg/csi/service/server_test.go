@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+)
+
+func TestVCenterAvailabilityInterceptor(t *testing.T) {
+	ctx := context.Background()
+	// Open the circuit breaker of one vCenter by failing its connections, while
+	// another vCenter is only degraded by a single failure.
+	degradedVC := &cnsvsphere.VirtualCenter{
+		Config:      &cnsvsphere.VirtualCenterConfig{Host: "interceptor-degraded.example.com", Port: 1},
+		ClientMutex: &sync.Mutex{},
+	}
+	assert.Error(t, degradedVC.Connect(ctx))
+	downHost := "interceptor-down.example.com"
+	downVC := &cnsvsphere.VirtualCenter{
+		Config:      &cnsvsphere.VirtualCenterConfig{Host: downHost, Port: 1},
+		ClientMutex: &sync.Mutex{},
+	}
+	for !cnsvsphere.IsVCenterDown(downHost) {
+		_ = downVC.Connect(ctx)
+	}
+
+	interceptor := vCenterAvailabilityInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"}
+	// The handler formats the connection error without wrapping it.
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		err := downVC.Connect(ctx)
+		return nil, status.Errorf(codes.Internal, "failed to connect to vCenter. Error: %v", err)
+	}
+	_, err := interceptor(ctx, nil, info, handler)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// Other failures are returned as is.
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, errors.New("volume not found").Error())
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
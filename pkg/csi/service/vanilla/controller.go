@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -36,6 +37,7 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/migration"
@@ -172,8 +174,20 @@ var (
 	CNSSnapshotsForListSnapshots = make([]cnstypes.CnsSnapshotQueryResultEntry, 0)
 	CNSVolumeDetailsMap          = make([]map[string]*utils.CnsVolumeDetails, 0)
 	volumeIDToNodeUUIDMap        = make(map[string]string)
+
+	// capacityCache holds the capacity computed by GetCapacity, keyed by
+	// vCenter, volume type, storage class parameters and topology segments.
+	capacityCache      = make(map[string]*capacityCacheEntry)
+	capacityCacheMutex sync.Mutex
 )
 
+// capacityCacheEntry is the capacity computed for a GetCapacity request.
+type capacityCacheEntry struct {
+	availableCapacity int64
+	maximumVolumeSize int64
+	lastRefreshed     time.Time
+}
+
 // New creates a CNS controller.
 func New() csitypes.CnsController {
 	return &controller{
@@ -181,6 +195,22 @@ func New() csitypes.CnsController {
 	}
 }
 
+// opTarget returns the optional metric labels of an operation on the given
+// volume of the given vCenter. Both are empty for the operations which do not
+// target a volume. If the vCenter is not given, the vCenter the volume was
+// created or queried on is used, or the vCenter of the driver if it manages a
+// single vCenter.
+func (c *controller) opTarget(vcHost, volumeID string) prometheus.OpTarget {
+	target := cnsvolume.GetVolumeOpTarget(volumeID)
+	if vcHost != "" {
+		target.VC = vcHost
+	} else if target.VC == "" && c.managers != nil && c.managers.CnsConfig != nil &&
+		len(c.managers.VcenterConfigs) == 1 {
+		target.VC = c.managers.CnsConfig.Global.VCenterIP
+	}
+	return target
+}
+
 // Init is initializing controller struct.
 func (c *controller) Init(config *cnsconfig.Config, version string) error {
 	ctx, log := logger.GetNewContextWithLogger()
@@ -314,6 +344,9 @@ func (c *controller) Init(config *cnsconfig.Config, version string) error {
 	}
 
 	go cnsvolume.ClearInvalidTasksFromListView(true)
+	if err := common.InitVCEventRecorder(ctx, "vsphere-csi-controller"); err != nil {
+		log.Warnf("failed to initialize event recorder for vCenter events. err=%v", err)
+	}
 	cfgPath := cnsconfig.GetConfigPath(ctx)
 
 	watcher, err := fsnotify.NewWatcher()
@@ -403,6 +436,7 @@ func (c *controller) ReloadConfiguration() error {
 	if err != nil {
 		return logger.LogNewErrorf(log, "failed to get VirtualCenterConfigs. err=%v", err)
 	}
+	credentialOnlyChange := true
 	for _, newVCConfig := range newVcenterConfigs {
 		newVCConfig.ReloadVCConfigForNewClient = true
 		if c.managers.VolumeManagers[newVCConfig.Host] == nil {
@@ -421,19 +455,40 @@ func (c *controller) ReloadConfiguration() error {
 		if err != nil {
 			return logger.LogNewErrorf(log, "failed to get VirtualCenter. err=%v", err)
 		}
-		vcenter.Config = newVCConfig
-		err := c.managers.VolumeManagers[newVCConfig.Host].ResetManager(ctx, vcenter)
+		// Login a new session if the credentials are rotated, and keep the
+		// current config if vCenter rejects them.
+		credentialProvider, err := cnsvsphere.NewCredentialProvider(newVCConfig)
+		if err == nil {
+			err = vcenter.RotateCredentials(ctx, credentialProvider)
+		}
+		if err != nil {
+			return logger.LogNewErrorf(log, "failed to rotate credentials for vCenter: %q. err=%v",
+				newVCConfig.Host, err)
+		}
+		vcConfigChanged := !cnsvsphere.IsCredentialOnlyChange(vcenter.Config, newVCConfig)
+		vcenter.SetConfig(newVCConfig)
+		c.managers.VcenterConfigs[newVCConfig.Host] = newVCConfig
+		if !vcConfigChanged {
+			// The managers already use this vCenter instance, whose session
+			// was swapped in place by the credential rotation.
+			continue
+		}
+		credentialOnlyChange = false
+		err = c.managers.VolumeManagers[newVCConfig.Host].ResetManager(ctx, vcenter)
 		if err != nil {
 			return logger.LogNewErrorf(log, "failed to reset updated VC object in volumemanager for vCenter: %q "+
 				"err=%v", newVCConfig.Host, err)
 		}
-		c.managers.VcenterConfigs[newVCConfig.Host] = newVCConfig
 		c.authMgrs[newVCConfig.Host].ResetvCenterInstance(ctx, vcenter)
 	}
 	if newCfg != nil {
 		c.managers.CnsConfig = newCfg
 		log.Debugf("Updated managers.CnsConfig")
 	}
+	if credentialOnlyChange {
+		log.Info("Only vCenter credentials changed. Skipping re-initialization of node manager")
+		return nil
+	}
 	// Re-Initialize Node Manager to cache latest vCenter config.
 	log.Debug("Re-Initializing node manager")
 	c.nodeMgr = &node.Nodes{}
@@ -491,6 +546,18 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 		return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
 			"parsing storage class parameters failed with error: %+v", err)
 	}
+	if scParams.MkfsOptions != "" {
+		var fsType string
+		for _, volCap := range req.GetVolumeCapabilities() {
+			if volCap.GetMount() != nil && volCap.GetMount().GetFsType() != "" {
+				fsType = strings.ToLower(volCap.GetMount().GetFsType())
+			}
+		}
+		if _, err := common.ParseMkfsOptions(fsType, scParams.MkfsOptions); err != nil {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+				"invalid %q parameter: %+v", common.AttributeMkfsOptions, err)
+		}
+	}
 
 	if scParams.CSIMigration == "true" {
 		if len(c.managers.VcenterConfigs) > 1 {
@@ -544,21 +611,45 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 			}
 		}
 	}
-	// Check if requested volume size and source snapshot size matches.
+	// Check if requested volume size and source snapshot or volume size matches.
 	volumeSource := req.GetVolumeContentSource()
-	var contentSourceSnapshotID, snapshotDatastoreURL string
+	var (
+		contentSourceSnapshotID, contentSourceVolumeID string
+		contentSourceVCHost, snapshotDatastoreURL      string
+		contentSourceVolumeManager                     cnsvolume.Manager
+		contentSourceSizeInMB                          int64
+	)
 	if volumeSource != nil {
-		sourceSnapshot := volumeSource.GetSnapshot()
-		if sourceSnapshot == nil {
+		var cnsVolumeID, sourceKind string
+		if sourceSnapshot := volumeSource.GetSnapshot(); sourceSnapshot != nil {
+			sourceKind = "snapshot"
+			contentSourceSnapshotID = sourceSnapshot.GetSnapshotId()
+			cnsVolumeID, _, err = common.ParseCSISnapshotID(contentSourceSnapshotID)
+			if err != nil {
+				return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log,
+					codes.InvalidArgument, err.Error())
+			}
+		} else if sourceVolume := volumeSource.GetVolume(); sourceVolume != nil {
+			sourceKind = "volume"
+			if !commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.BlockVolumeSnapshot) {
+				return nil, csifault.CSIUnimplementedFault, logger.LogNewErrorCode(log, codes.Unimplemented,
+					"cloning a volume requires block volume snapshot support to be enabled")
+			}
+			contentSourceVolumeID = sourceVolume.GetVolumeId()
+			if contentSourceVolumeID == "" {
+				return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
+					"source volume ID must be provided in VolumeContentSource")
+			}
+			// Check if the source volume is migrated vSphere volume.
+			if strings.Contains(contentSourceVolumeID, ".vmdk") {
+				return nil, csifault.CSIUnimplementedFault, logger.LogNewErrorCodef(log, codes.Unimplemented,
+					"cannot clone migrated vSphere volume %q", contentSourceVolumeID)
+			}
+			cnsVolumeID = contentSourceVolumeID
+		} else {
 			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
 				"unsupported VolumeContentSource type")
 		}
-		contentSourceSnapshotID = sourceSnapshot.GetSnapshotId()
-		cnsVolumeID, _, err := common.ParseCSISnapshotID(contentSourceSnapshotID)
-		if err != nil {
-			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log,
-				codes.InvalidArgument, err.Error())
-		}
 		// Get VC, volumeManager for given volumeID.
 		vCenterHost, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, cnsVolumeID,
 			volumeInfoService)
@@ -577,7 +668,7 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 				"VC %q does not support snapshot operations", vCenterHost)
 		}
 
-		// Query capacity in MB and datastore url for block volume snapshot.
+		// Query capacity in MB and datastore url for the source volume.
 		volumeIds := []cnstypes.CnsVolumeId{{Id: cnsVolumeID}}
 		cnsVolumeDetailsMap, err := utils.QueryVolumeDetailsUtil(ctx, volumeManager, volumeIds)
 		if err != nil {
@@ -585,27 +676,40 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 				"failed to retrieve volume details for ID %q. Error: %+v", cnsVolumeID, err)
 		}
 		if _, ok := cnsVolumeDetailsMap[cnsVolumeID]; !ok {
+			if contentSourceVolumeID != "" {
+				return nil, csifault.CSINotFoundFault, logger.LogNewErrorCodef(log, codes.NotFound,
+					"source volume %q not found", cnsVolumeID)
+			}
 			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
 				"CNS query volume failed to find the volume: %q", cnsVolumeID)
 		}
-		snapshotSizeInMB := cnsVolumeDetailsMap[cnsVolumeID].SizeInMB
-		snapshotSizeInBytes := snapshotSizeInMB * common.MbInBytes
-		if volSizeBytes != snapshotSizeInBytes {
+		if contentSourceVolumeID != "" && cnsVolumeDetailsMap[cnsVolumeID].VolumeType != common.BlockVolumeType {
 			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
-				"snapshot size mismatch, requested volume size: %d but source snapshot size: %d",
-				volSizeBytes, snapshotSizeInBytes)
+				"source volume %q is of type %q, only block volumes can be cloned",
+				cnsVolumeID, cnsVolumeDetailsMap[cnsVolumeID].VolumeType)
 		}
-		// Store the datastoreURL of snapshot for future use.
+		contentSourceSizeInMB = cnsVolumeDetailsMap[cnsVolumeID].SizeInMB
+		sourceSizeInBytes := contentSourceSizeInMB * common.MbInBytes
+		if volSizeBytes < sourceSizeInBytes {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+				"%s size mismatch, requested volume size: %d is smaller than source %s size: %d",
+				sourceKind, volSizeBytes, sourceKind, sourceSizeInBytes)
+		}
+		// Store the datastoreURL of the source for future use. A clone is
+		// provisioned from a snapshot of the source volume, so it is bound to
+		// the same datastore as a snapshot would be.
 		snapshotDatastoreURL = cnsVolumeDetailsMap[cnsVolumeID].DatastoreUrl
 		// If DatastoreURL parameter is given in StorageClass, check if
-		// snapshot datastore URL is same as DatastoreURL.
+		// source datastore URL is same as DatastoreURL.
 		if scParams.DatastoreURL != "" {
 			if strings.TrimSpace(snapshotDatastoreURL) != strings.TrimSpace(scParams.DatastoreURL) {
 				return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
-					"datastore URL %q given in storage class does not match the snapshot datastore URL %q.",
-					scParams.DatastoreURL, snapshotDatastoreURL)
+					"datastore URL %q given in storage class does not match the %s datastore URL %q.",
+					scParams.DatastoreURL, sourceKind, snapshotDatastoreURL)
 			}
 		}
+		contentSourceVCHost = vCenterHost
+		contentSourceVolumeManager = volumeManager
 	}
 
 	var createVolumeSpec = common.CreateVolumeSpec{
@@ -615,6 +719,11 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 		VolumeType:              common.BlockVolumeType,
 		ContentSourceSnapshotID: contentSourceSnapshotID,
 	}
+	if volumeSource != nil {
+		// CNS creates the volume with the size of the source. If a larger size
+		// is requested, the volume is expanded once it has been created.
+		createVolumeSpec.CapacityMB = contentSourceSizeInMB
+	}
 	// Check if vCenter task for this volume is already registered as part of
 	// improved idempotency CR.
 	log.Debugf("Checking if vCenter task for volume %s is already registered.", req.Name)
@@ -638,6 +747,21 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 		}
 		break
 	}
+	if contentSourceVolumeID != "" {
+		// The transient snapshot taken on the source volume for the clone can
+		// only be removed once CNS is done creating the volume from it. If the
+		// CreateVolume task is still pending, it is left for the retry which
+		// monitors the task.
+		defer func() {
+			if isCreateVolumeTaskPending(ctx, operationStore, req.Name) {
+				log.Infof("CreateVolume task for clone %q is still pending. Not deleting the snapshot "+
+					"taken on source volume %q", req.Name, contentSourceVolumeID)
+				return
+			}
+			deleteCloneSourceSnapshots(ctx, contentSourceVolumeManager, operationStore, contentSourceVolumeID,
+				req.Name)
+		}()
+	}
 	volumeOperationDetails, err := operationStore.GetRequestDetails(ctx, req.Name)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -755,6 +879,19 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 				"failed to get accessibility requirements by VC. Error: %+v", err)
 		}
 		log.Debugf("Topology accessibility requirements per VC are %+v", vcTopologySegmentsMap)
+		if contentSourceVCHost != "" {
+			// A volume created from a snapshot or another volume can only be
+			// placed in the vCenter which hosts the source volume.
+			sourceTopologySegments, ok := vcTopologySegmentsMap[contentSourceVCHost]
+			if !ok {
+				return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+					"accessibility requirements %+v do not include vCenter %q hosting the volume content source",
+					topologyRequirement, contentSourceVCHost)
+			}
+			vcTopologySegmentsMap = map[string][]map[string]string{
+				contentSourceVCHost: sourceTopologySegments,
+			}
+		}
 	} else {
 		if topologyRequirement != nil {
 			// Get accessibility requirements.
@@ -777,6 +914,23 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 		}
 	}
 
+	if !volTaskAlreadyRegistered && contentSourceVolumeID != "" {
+		// CNS can only create a volume from a snapshot, so the source volume
+		// is cloned through a transient snapshot which is removed once the
+		// new volume has been created.
+		cloneSnapshotID, _, err := common.CreateSnapshotUtil(ctx, contentSourceVolumeManager,
+			contentSourceVolumeID, common.CloneSourceSnapshotPrefix+req.Name,
+			&cnsvolume.CreateSnapshotExtraParams{
+				IsCSITransactionSupportEnabled: isCSITransactionSupportEnabled,
+			})
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to create snapshot on source volume %q for clone %q. Error: %+v",
+				contentSourceVolumeID, req.Name, err)
+		}
+		createVolumeSpec.ContentSourceSnapshotID = cloneSnapshotID
+	}
+
 	if !volTaskAlreadyRegistered {
 		// Iterate through each VC and its accessibility requirements to try and create a volume.
 		// If it fails for any reason, move to the next VC in list.
@@ -993,8 +1147,53 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 			"failed to create volume. Errors encountered: %+v", combinedErrMssgs)
 	}
 
+	if volumeSource != nil {
+		if volumeMgr == nil {
+			volumeMgr, err = GetVolumeManagerFromVCHost(ctx, c.managers, vcHost)
+			if err != nil {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCode(log, codes.Internal, err.Error())
+			}
+		}
+		// CNS creates the volume with the size of the source. The size of the
+		// created volume is queried instead of assuming it is the current size
+		// of the source, which may have changed since an earlier attempt of this
+		// request created the volume without getting to expand it.
+		volumeDetailsMap, err := utils.QueryVolumeDetailsUtil(ctx, volumeMgr,
+			[]cnstypes.CnsVolumeId{volumeInfo.VolumeID})
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to retrieve volume details for ID %q. Error: %+v", volumeInfo.VolumeID.Id, err)
+		}
+		volumeDetails, ok := volumeDetailsMap[volumeInfo.VolumeID.Id]
+		if !ok {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"CNS query volume failed to find the volume: %q", volumeInfo.VolumeID.Id)
+		}
+		if volumeDetails.SizeInMB < volSizeMB {
+			// The expansion is tracked by the volume manager in its own
+			// CnsVolumeOperationRequest keyed on the volume ID. As the volume ID
+			// is persisted with the CreateVolume operation, a retry of this
+			// request resumes the pending expansion instead of creating another
+			// volume.
+			log.Infof("Expanding volume %q from size %d MB to requested size %d MB",
+				volumeInfo.VolumeID.Id, volumeDetails.SizeInMB, volSizeMB)
+			faultType, err = volumeMgr.ExpandVolume(ctx, volumeInfo.VolumeID.Id, volSizeMB, nil)
+			if err != nil {
+				if faultType == "" {
+					faultType = csifault.CSIInternalFault
+				}
+				return nil, faultType, logger.LogNewErrorCodef(log, codes.Internal,
+					"failed to expand volume %q created from volume content source to size %d MB. Error: %+v",
+					volumeInfo.VolumeID.Id, volSizeMB, err)
+			}
+		}
+	}
+
 	attributes := make(map[string]string)
 	attributes[common.AttributeDiskType] = common.DiskTypeBlockVolume
+	if scParams.MkfsOptions != "" {
+		attributes[common.AttributeMkfsOptions] = scParams.MkfsOptions
+	}
 
 	if scParams.CSIMigration == "true" {
 		volumePath, err := volumeMigrationService.GetVolumePath(ctx, volumeInfo.VolumeID.Id)
@@ -1048,7 +1247,7 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 		}
 	}
 
-	// Set the Snapshot VolumeContentSource in the CreateVolumeResponse
+	// Set the Snapshot or Volume VolumeContentSource in the CreateVolumeResponse
 	if contentSourceSnapshotID != "" {
 		resp.Volume.ContentSource = &csi.VolumeContentSource{
 			Type: &csi.VolumeContentSource_Snapshot{
@@ -1057,6 +1256,14 @@ func (c *controller) createBlockVolumeWithPlacementEngineForMultiVC(ctx context.
 				},
 			},
 		}
+	} else if contentSourceVolumeID != "" {
+		resp.Volume.ContentSource = &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{
+				Volume: &csi.VolumeContentSource_VolumeSource{
+					VolumeId: contentSourceVolumeID,
+				},
+			},
+		}
 	}
 	if len(c.managers.VcenterConfigs) > 1 {
 		// Create CNSVolumeInfo CR for the volume ID.
@@ -1178,9 +1385,15 @@ func (c *controller) createFileVolume(ctx context.Context, req *csi.CreateVolume
 			cnsvolumeoperationrequest.TaskInvocationStatusSuccess && volumeOperationDetails.VolumeID != "" {
 			// If task status is successful for this volume, then it means that volume is
 			// already created and there is no need to create it again.
-			log.Infof("File volume with name %q and id %q is already created on CNS with opId: %q.",
-				req.Name, volumeOperationDetails.VolumeID, volumeOperationDetails.OperationDetails.OpID)
+			log.Infof("File volume with name %q and id %q is already created on VC %q with opId: %q.",
+				req.Name, volumeOperationDetails.VolumeID, volumeOperationDetails.OperationDetails.VCenterServer,
+				volumeOperationDetails.OperationDetails.OpID)
 
+			if volumeOperationDetails.OperationDetails.VCenterServer != "" {
+				vcHost = volumeOperationDetails.OperationDetails.VCenterServer
+			} else {
+				vcHost = c.managers.CnsConfig.Global.VCenterIP
+			}
 			volumeID = volumeOperationDetails.VolumeID
 			volTaskAlreadyRegistered = true
 		} else if cnsvolume.IsTaskPending(volumeOperationDetails) {
@@ -1317,13 +1530,10 @@ func (c *controller) createFileVolume(ctx context.Context, req *csi.CreateVolume
 
 				// Filter File service enabled DS from candidate datastores.
 				var fsEnabledCandidateDatastores []*cnsvsphere.DatastoreInfo
-				fsEnabledClusterToDsInfoMap := c.authMgrs[vcHost].GetFsEnabledClusterToDsMap(ctx)
-				for _, datastores := range fsEnabledClusterToDsInfoMap {
-					for _, fsEnabledDatastore := range datastores {
-						for _, ds := range candidateDatastores {
-							if ds.Info.Url == fsEnabledDatastore.Info.Url {
-								fsEnabledCandidateDatastores = append(fsEnabledCandidateDatastores, ds)
-							}
+				for _, fsEnabledDatastore := range c.getFsEnabledDatastores(ctx, vcHost) {
+					for _, ds := range candidateDatastores {
+						if ds.Info.Url == fsEnabledDatastore.Info.Url {
+							fsEnabledCandidateDatastores = append(fsEnabledCandidateDatastores, ds)
 						}
 					}
 				}
@@ -1385,17 +1595,6 @@ func (c *controller) createFileVolume(ctx context.Context, req *csi.CreateVolume
 				}
 				volumeID = volumeInfo.VolumeID.Id
 				log.Infof("volume %q created in vCenter %q.", volumeID, vcHost)
-
-				// In case task was successful from previous run but the CR was not created.
-				if len(c.managers.VcenterConfigs) > 1 {
-					// Create CNSVolumeInfo CR for the volume ID in case of multi VC deployment.
-					err = volumeInfoService.CreateVolumeInfo(ctx, volumeID, vcHost)
-					if err != nil {
-						return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
-							"failed to store volumeID %q for vCenter %q in CNSVolumeInfo CR. Error: %+v",
-							volumeInfo.VolumeID.Id, vcHost, err)
-					}
-				}
 				break
 			}
 			// After iterating over all VCs, if volumeID is still empty, we error out.
@@ -1408,14 +1607,8 @@ func (c *controller) createFileVolume(ctx context.Context, req *csi.CreateVolume
 			}
 		} else {
 			// When topologyRequirement is nil, below code is invoked
-			var fsEnabledClusterToDsInfoMap map[string][]*cnsvsphere.DatastoreInfo
 			vcHost = c.managers.CnsConfig.Global.VCenterIP
-			fsEnabledClusterToDsInfoMap = c.authMgrs[vcHost].GetFsEnabledClusterToDsMap(ctx)
-
-			var filteredDatastores []*cnsvsphere.DatastoreInfo
-			for _, datastores := range fsEnabledClusterToDsInfoMap {
-				filteredDatastores = append(filteredDatastores, datastores...)
-			}
+			filteredDatastores := c.getFsEnabledDatastores(ctx, vcHost)
 			if len(filteredDatastores) == 0 {
 				// when len(filteredDatastore)==0, it means vsan file service is not enabled on any vsan cluster
 				return nil, csifault.CSIVSanFileServiceDisabledFault, logger.LogNewErrorCode(log, codes.FailedPrecondition,
@@ -1439,6 +1632,17 @@ func (c *controller) createFileVolume(ctx context.Context, req *csi.CreateVolume
 
 		}
 	}
+	// The CR is also created when the task was successful in a previous run, in
+	// case the CR was not created then.
+	if len(c.managers.VcenterConfigs) > 1 {
+		// Create CNSVolumeInfo CR for the volume ID in case of multi VC deployment.
+		err = volumeInfoService.CreateVolumeInfo(ctx, volumeID, vcHost)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to store volumeID %q for vCenter %q in CNSVolumeInfo CR. Error: %+v",
+				volumeID, vcHost, err)
+		}
+	}
 
 	attributes := make(map[string]string)
 	attributes[common.AttributeDiskType] = common.DiskTypeFileVolume
@@ -1453,6 +1657,41 @@ func (c *controller) createFileVolume(ctx context.Context, req *csi.CreateVolume
 	return resp, "", nil
 }
 
+// getFsEnabledDatastores returns the datastores of the vSAN file service
+// enabled clusters of the vCenter vcHost on which file volumes can be created.
+// If targetvSANFileShareClusters is set for the vCenter, only the datastores
+// of those clusters are returned.
+func (c *controller) getFsEnabledDatastores(ctx context.Context, vcHost string) []*cnsvsphere.DatastoreInfo {
+	log := logger.GetLogger(ctx)
+	authMgr, ok := c.authMgrs[vcHost]
+	if !ok || authMgr == nil {
+		log.Warnf("authorization service is not initialized for vCenter %q", vcHost)
+		return nil
+	}
+	fsEnabledClusterToDsInfoMap := authMgr.GetFsEnabledClusterToDsMap(ctx)
+	var targetClusters []string
+	if vcConfig, ok := c.managers.VcenterConfigs[vcHost]; ok {
+		targetClusters = vcConfig.TargetvSANFileShareClusters
+	}
+	var datastores []*cnsvsphere.DatastoreInfo
+	if len(targetClusters) == 0 {
+		for _, clusterDatastores := range fsEnabledClusterToDsInfoMap {
+			datastores = append(datastores, clusterDatastores...)
+		}
+		return datastores
+	}
+	for _, targetCluster := range targetClusters {
+		clusterDatastores, ok := fsEnabledClusterToDsInfoMap[strings.TrimSpace(targetCluster)]
+		if !ok {
+			log.Debugf("target vSAN file share cluster %q is not file service enabled in vCenter %q",
+				targetCluster, vcHost)
+			continue
+		}
+		datastores = append(datastores, clusterDatastores...)
+	}
+	return datastores
+}
+
 // CreateVolume is creating CNS Volume using volume request specified in
 // CreateVolumeRequest.
 func (c *controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (
@@ -1513,10 +1752,19 @@ func (c *controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequ
 			}
 		}
 		volumeType = prometheus.PrometheusBlockVolumeType
+		if faultType, err := applyPVCRequestedTopology(ctx, req); err != nil {
+			return nil, faultType, err
+		}
 		return c.createBlockVolumeWithPlacementEngineForMultiVC(ctx, req)
 	}
 	resp, faultType, err := createVolumeInternal()
 	log.Debugf("createVolumeInternal: returns fault %q", faultType)
+	var volumeID string
+	if resp != nil && resp.Volume != nil {
+		volumeID = resp.Volume.VolumeId
+	}
+	target := c.opTarget("", volumeID)
+	target.StoragePolicy = common.GetStoragePolicyFromParams(req.Parameters)
 	if err != nil {
 		if csifault.IsNonStorageFault(faultType) {
 			faultType = csifault.AddCsiNonStoragePrefix(ctx, faultType)
@@ -1524,11 +1772,13 @@ func (c *controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequ
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusCreateVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume created successfully. Volume Handle: %q, PV Name: %q", resp.Volume.VolumeId, req.Name)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -1542,6 +1792,7 @@ func (c *controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequ
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
 	cnsVolumeType := common.UnknownVolumeType
+	target := c.opTarget("", "")
 
 	deleteVolumeInternal := func() (
 		*csi.DeleteVolumeResponse, string, error) {
@@ -1589,6 +1840,7 @@ func (c *controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequ
 			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
 				"failed to get vCenter/volume manager for volume Id: %q. Error: %v", req.VolumeId, err)
 		}
+		target = c.opTarget(vCenterHost, req.VolumeId)
 
 		if cnsVolumeType == common.UnknownVolumeType {
 			cnsVolumeType = common.GetCnsVolumeType(ctx, req.VolumeId)
@@ -1654,11 +1906,13 @@ func (c *controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequ
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusDeleteVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q deleted successfully.", req.VolumeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -1669,9 +1923,11 @@ func (c *controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequ
 func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (
 	*csi.ControllerPublishVolumeResponse, error) {
 	start := time.Now()
+	defer prometheus.TrackAttachQueueDepth(req.GetNodeId(), prometheus.PrometheusAttachVolumeOpType)()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
+	target := c.opTarget("", "")
 
 	controllerPublishVolumeInternal := func() (
 		*csi.ControllerPublishVolumeResponse, string, error) {
@@ -1689,11 +1945,13 @@ func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.Contr
 				"validation for PublishVolume Request: %+v has failed. Error: %v", req, err)
 		}
 		publishInfo := make(map[string]string)
-		_, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, req.VolumeId, volumeInfoService)
+		vCenterHost, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, req.VolumeId,
+			volumeInfoService)
 		if err != nil {
 			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
 				"failed to get volume manager for volume Id: %q. Error: %v", req.VolumeId, err)
 		}
+		target = c.opTarget(vCenterHost, req.VolumeId)
 		// Check whether its a block or file volume.
 		if common.IsFileVolumeRequest(ctx, []*csi.VolumeCapability{req.GetVolumeCapability()}) {
 			volumeType = prometheus.PrometheusFileVolumeType
@@ -1795,11 +2053,13 @@ func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.Contr
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusAttachVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusAttachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusAttachVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q attached successfully to node %q.", req.VolumeId, req.NodeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusAttachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusAttachVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -1810,9 +2070,11 @@ func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.Contr
 func (c *controller) ControllerUnpublishVolume(ctx context.Context, req *csi.ControllerUnpublishVolumeRequest) (
 	*csi.ControllerUnpublishVolumeResponse, error) {
 	start := time.Now()
+	defer prometheus.TrackAttachQueueDepth(req.GetNodeId(), prometheus.PrometheusDetachVolumeOpType)()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
+	target := c.opTarget("", "")
 
 	controllerUnpublishVolumeInternal := func() (
 		*csi.ControllerUnpublishVolumeResponse, string, error) {
@@ -1830,11 +2092,13 @@ func (c *controller) ControllerUnpublishVolume(ctx context.Context, req *csi.Con
 				"validation for UnpublishVolume Request: %+v has failed. Error: %v", req, err)
 		}
 
-		_, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, req.VolumeId, volumeInfoService)
+		vCenterHost, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, req.VolumeId,
+			volumeInfoService)
 		if err != nil {
 			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
 				"failed to get volume manager for volume Id: %q. Error: %v", req.VolumeId, err)
 		}
+		target = c.opTarget(vCenterHost, req.VolumeId)
 		if !strings.Contains(req.VolumeId, ".vmdk") {
 			// Check if volume is file volume using volume ID prefix pattern.
 			// File volumes have "file:" prefix, so we can avoid expensive CNS QueryVolume call.
@@ -1907,11 +2171,13 @@ func (c *controller) ControllerUnpublishVolume(ctx context.Context, req *csi.Con
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusDetachVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDetachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDetachVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q detached successfully from node %q.", req.VolumeId, req.NodeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDetachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDetachVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -1925,6 +2191,7 @@ func (c *controller) ControllerExpandVolume(ctx context.Context, req *csi.Contro
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
+	target := c.opTarget("", "")
 	controllerExpandVolumeInternal := func() (
 		*csi.ControllerExpandVolumeResponse, string, error) {
 		var (
@@ -1963,6 +2230,7 @@ func (c *controller) ControllerExpandVolume(ctx context.Context, req *csi.Contro
 			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
 				"failed to get vCenter/volume manager for volume Id: %q. Error: %v", req.VolumeId, err)
 		}
+		target = c.opTarget(vCenterHost, req.VolumeId)
 
 		isOnlineExpansionSupported, err := vCenterManager.IsOnlineExtendVolumeSupported(ctx, vCenterHost)
 		if err != nil {
@@ -2043,11 +2311,13 @@ func (c *controller) ControllerExpandVolume(ctx context.Context, req *csi.Contro
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusExpandVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusExpandVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusExpandVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q expanded successfully.", req.VolumeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusExpandVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusExpandVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2186,10 +2456,12 @@ func (c *controller) ListVolumes(ctx context.Context, req *csi.ListVolumesReques
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusListVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("", "")).WithLabelValues(
+			volumeType, prometheus.PrometheusListVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("", "")).WithLabelValues(
+			volumeType, prometheus.PrometheusListVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return listVolResponse, err
@@ -2295,12 +2567,209 @@ func (c *controller) processQueryResultsListVolumes(ctx context.Context, startin
 	return entries, nextToken, volumeType, nil
 }
 
+// GetCapacity returns the capacity available for provisioning block volumes
+// with the given storage class parameters in the given topology segment.
+// AvailableCapacity is the total free space across the candidate datastores
+// and MaximumVolumeSize is the free space on the largest of them, as a volume
+// cannot span datastores. Results are cached for common.GetCapacityCacheTTL.
 func (c *controller) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (
 	*csi.GetCapacityResponse, error) {
+	start := time.Now()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
-	log.Infof("GetCapacity: called with args %+v", req)
-	return nil, logger.LogNewErrorCode(log, codes.Unimplemented, "getCapacity")
+	volumeType := prometheus.PrometheusBlockVolumeType
+
+	getCapacityInternal := func() (*csi.GetCapacityResponse, string, error) {
+		log.Infof("GetCapacity: called with args %+v", req)
+		isFileVolumeRequest := common.IsFileVolumeRequest(ctx, req.GetVolumeCapabilities())
+		if isFileVolumeRequest {
+			volumeType = prometheus.PrometheusFileVolumeType
+		}
+		scParams, err := common.ParseStorageClassParams(ctx, req.GetParameters())
+		if err != nil {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+				"parsing storage class parameters failed with error: %+v", err)
+		}
+
+		// Find the topology segments the capacity is requested for, grouped by vCenter.
+		segments := req.GetAccessibleTopology().GetSegments()
+		vcTopologySegmentsMap := make(map[string][]map[string]string)
+		if len(c.managers.VcenterConfigs) > 1 {
+			if len(segments) == 0 {
+				return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
+					"accessible topology cannot be empty for a multi-VC environment")
+			}
+			vcTopologySegmentsMap, err = common.GetAccessibilityRequirementsByVC(ctx,
+				&csi.TopologyRequirement{Preferred: []*csi.Topology{req.GetAccessibleTopology()}})
+			if err != nil {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+					"failed to get vCenter for topology segments %+v. Error: %+v", segments, err)
+			}
+		} else {
+			vcHost := c.managers.CnsConfig.Global.VCenterIP
+			vcTopologySegmentsMap[vcHost] = nil
+			if len(segments) != 0 {
+				vcTopologySegmentsMap[vcHost] = []map[string]string{segments}
+			}
+		}
+
+		var availableCapacity, maximumVolumeSize int64
+		for vcHost, topologySegments := range vcTopologySegmentsMap {
+			capacity, faultType, err := c.getCapacityForVC(ctx, vcHost, scParams, topologySegments,
+				isFileVolumeRequest)
+			if err != nil {
+				return nil, faultType, err
+			}
+			availableCapacity += capacity.availableCapacity
+			if capacity.maximumVolumeSize > maximumVolumeSize {
+				maximumVolumeSize = capacity.maximumVolumeSize
+			}
+		}
+		log.Infof("GetCapacity: available capacity %d bytes, maximum volume size %d bytes",
+			availableCapacity, maximumVolumeSize)
+		return &csi.GetCapacityResponse{
+			AvailableCapacity: availableCapacity,
+			MaximumVolumeSize: &wrapperspb.Int64Value{Value: maximumVolumeSize},
+		}, "", nil
+	}
+
+	resp, faultType, err := getCapacityInternal()
+	if err != nil {
+		if csifault.IsNonStorageFault(faultType) {
+			faultType = csifault.AddCsiNonStoragePrefix(ctx, faultType)
+		}
+		log.Errorf("Operation failed, reporting failure status to Prometheus."+
+			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
+			prometheus.PrometheusGetCapacityOpType, volumeType, faultType)
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("", "")).WithLabelValues(
+			volumeType, prometheus.PrometheusGetCapacityOpType,
+			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
+	} else {
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("", "")).WithLabelValues(
+			volumeType, prometheus.PrometheusGetCapacityOpType,
+			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
+	}
+	return resp, err
+}
+
+// getCapacityForVC returns the capacity available on the datastores of
+// vCenter vcHost which are candidates for provisioning a volume with the
+// given storage class parameters in the given topology segments. The result
+// is served from capacityCache if it was computed within
+// common.GetCapacityCacheTTL.
+func (c *controller) getCapacityForVC(ctx context.Context, vcHost string, scParams *common.StorageClassParams,
+	topologySegments []map[string]string, isFileVolumeRequest bool) (*capacityCacheEntry, string, error) {
+	log := logger.GetLogger(ctx)
+	cacheKey := fmt.Sprintf("%s/%t/%s/%s/%v", vcHost, isFileVolumeRequest, scParams.StoragePolicyName,
+		strings.TrimSpace(scParams.DatastoreURL), topologySegments)
+	capacityCacheMutex.Lock()
+	cached, ok := capacityCache[cacheKey]
+	capacityCacheMutex.Unlock()
+	if ok && time.Since(cached.lastRefreshed) < common.GetCapacityCacheTTL {
+		log.Debugf("GetCapacity: serving capacity for %q from cache", cacheKey)
+		return cached, "", nil
+	}
+
+	vcenter, err := common.GetVCenterFromVCHost(ctx, c.managers.VcenterManager, vcHost)
+	if err != nil {
+		return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+			"failed to get vCenter instance for host %q. Error: %+v", vcHost, err)
+	}
+	var storagePolicyID string
+	if scParams.StoragePolicyName != "" {
+		storagePolicyID, err = vcenter.GetStoragePolicyIDByName(ctx, scParams.StoragePolicyName)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get policy ID for storage policy name %q in vCenter %q. Error: %+v",
+				scParams.StoragePolicyName, vcHost, err)
+		}
+	}
+
+	var candidateDatastores []*cnsvsphere.DatastoreInfo
+	if isFileVolumeRequest {
+		candidateDatastores, err = filterDatastoresByStoragePolicy(ctx, vcenter, c.getFsEnabledDatastores(ctx, vcHost),
+			storagePolicyID)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCode(log, codes.Internal, err.Error())
+		}
+	} else {
+		if len(topologySegments) != 0 {
+			// Policy compatibility is checked by the placement engine.
+			candidateDatastores, err = placementengine.GetSharedDatastores(ctx,
+				placementengine.VanillaSharedDatastoresParams{
+					Vcenter:              vcenter,
+					TopologySegmentsList: topologySegments,
+					StoragePolicyID:      storagePolicyID,
+				})
+			if err != nil {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+					"failed to get shared datastores for topology segments %+v in vCenter %q. Error: %+v",
+					topologySegments, vcHost, err)
+			}
+		} else {
+			candidateDatastores, err = c.nodeMgr.GetSharedDatastoresInK8SCluster(ctx)
+			if err != nil {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+					"failed to get shared datastores in kubernetes cluster. Error: %+v", err)
+			}
+			candidateDatastores, err = filterDatastoresByStoragePolicy(ctx, vcenter, candidateDatastores,
+				storagePolicyID)
+			if err != nil {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCode(log, codes.Internal, err.Error())
+			}
+		}
+		if len(candidateDatastores) != 0 {
+			candidateDatastores, err = c.filterDatastores(ctx, candidateDatastores, vcHost)
+			if err != nil && err != errAllDSFilteredOut {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+					"failed to filter datastores based on authorisation check in vCenter %q. Error: %+v",
+					vcHost, err)
+			}
+		}
+	}
+	if len(candidateDatastores) != 0 {
+		candidateDatastores, err = cnsvsphere.FilterSuspendedDatastores(ctx, candidateDatastores)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to filter suspended datastores in vCenter %q. Error: %+v", vcHost, err)
+		}
+	}
+
+	capacity := &capacityCacheEntry{lastRefreshed: time.Now()}
+	seen := make(map[string]struct{})
+	for _, ds := range candidateDatastores {
+		dsURL := strings.TrimSpace(ds.Info.Url)
+		if scParams.DatastoreURL != "" && dsURL != strings.TrimSpace(scParams.DatastoreURL) {
+			continue
+		}
+		if _, ok := seen[dsURL]; ok {
+			continue
+		}
+		seen[dsURL] = struct{}{}
+		capacity.availableCapacity += ds.Info.FreeSpace
+		if ds.Info.FreeSpace > capacity.maximumVolumeSize {
+			capacity.maximumVolumeSize = ds.Info.FreeSpace
+		}
+	}
+	log.Debugf("GetCapacity: computed capacity %+v for %q from datastores %+v",
+		*capacity, cacheKey, candidateDatastores)
+	storeCapacity(cacheKey, capacity)
+	return capacity, "", nil
+}
+
+// storeCapacity stores the capacity computed for cacheKey in capacityCache.
+// Entries older than common.GetCapacityCacheTTL are evicted at the same time,
+// so that the cache does not grow with the parameters and topology segments
+// of requests which are not repeated.
+func storeCapacity(cacheKey string, capacity *capacityCacheEntry) {
+	capacityCacheMutex.Lock()
+	defer capacityCacheMutex.Unlock()
+	for key, entry := range capacityCache {
+		if time.Since(entry.lastRefreshed) >= common.GetCapacityCacheTTL {
+			delete(capacityCache, key)
+		}
+	}
+	capacityCache[cacheKey] = capacity
 }
 
 // initVolumeMigrationService is a helper method to initialize
@@ -2344,10 +2813,19 @@ func (c *controller) ControllerGetCapabilities(ctx context.Context, req *csi.Con
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_MODIFY_VOLUME,
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
 		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	}
 
+	if commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.BlockVolumeSnapshot) {
+		// Volumes are cloned through a snapshot of the source volume.
+		controllerCaps = append(controllerCaps, csi.ControllerServiceCapability_RPC_CLONE_VOLUME)
+	}
 	if commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.ListVolumes) {
 		controllerCaps = append(controllerCaps, csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
 			csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES)
@@ -2516,11 +2994,13 @@ func (c *controller) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshot
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusCreateSnapshotOpType, volumeType, "NotComputed")
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget(vCenterHost, volumeID)).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateSnapshotOpType,
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Snapshot for volume %q created successfully.", req.GetSourceVolumeId())
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget(vCenterHost, volumeID)).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateSnapshotOpType,
 			prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2586,11 +3066,13 @@ func (c *controller) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshot
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusDeleteSnapshotOpType, volumeType, "NotComputed")
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget(vCenterHost, volumeID)).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteSnapshotOpType,
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Snapshot %q deleted successfully.", req.SnapshotId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget(vCenterHost, volumeID)).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteSnapshotOpType,
 			prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2701,10 +3183,12 @@ func (c *controller) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRe
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusListSnapshotsOpType, volumeType, "NotComputed")
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListSnapshotsOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("", "")).WithLabelValues(
+			volumeType, prometheus.PrometheusListSnapshotsOpType,
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListSnapshotsOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("", "")).WithLabelValues(
+			volumeType, prometheus.PrometheusListSnapshotsOpType,
 			prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2870,18 +3354,231 @@ func processQueryResultsListSnapshots(ctx context.Context, startingToken int,
 	return snapEntries, nextToken, nil
 }
 
+// ControllerGetVolume returns the capacity, published nodes and condition of
+// the given volume. The condition is derived from the health status CNS
+// reports for the volume.
 func (c *controller) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (
 	*csi.ControllerGetVolumeResponse, error) {
+	start := time.Now()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
-	log.Infof("ControllerGetVolume: called with args %+v", req)
-	return nil, logger.LogNewErrorCode(log, codes.Unimplemented, "controllerGetVolume")
+	volumeType := prometheus.PrometheusUnknownVolumeType
+	target := c.opTarget("", "")
+
+	controllerGetVolumeInternal := func() (*csi.ControllerGetVolumeResponse, string, error) {
+		log.Infof("ControllerGetVolume: called with args %+v", req)
+		if req.GetVolumeId() == "" {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
+				"volume ID must be provided")
+		}
+		var err error
+		volumeID := req.GetVolumeId()
+		if strings.Contains(volumeID, ".vmdk") {
+			volumeType = prometheus.PrometheusBlockVolumeType
+			// In case if feature state switch is enabled after controller is
+			// deployed, we need to initialize the volumeMigrationService.
+			if err := initVolumeMigrationService(ctx, c); err != nil {
+				// Error is already wrapped in CSI error code.
+				return nil, csifault.CSIInternalFault, err
+			}
+			volumeID, err = volumeMigrationService.GetVolumeID(ctx,
+				&migration.VolumeSpec{VolumePath: req.GetVolumeId()}, false)
+			if err != nil {
+				return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+					"failed to get VolumeID from volumeMigrationService for volumePath: %q", req.GetVolumeId())
+			}
+		}
+		vCenterHost, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, volumeID, volumeInfoService)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get vCenter/volume manager for volume Id: %q. Error: %v", volumeID, err)
+		}
+		target = c.opTarget(vCenterHost, volumeID)
+		cnsVolume, faultType, err := common.QueryVolumeWithHealthStatusUtil(ctx, volumeManager, volumeID)
+		if err != nil {
+			return nil, faultType, err
+		}
+		volumeType = convertCnsVolumeType(ctx, cnsVolume.VolumeType)
+
+		volume := &csi.Volume{
+			VolumeId: req.GetVolumeId(),
+		}
+		if cnsVolume.BackingObjectDetails != nil {
+			volume.CapacityBytes = cnsVolume.BackingObjectDetails.GetCnsBackingObjectDetails().CapacityInMb *
+				common.MbInBytes
+		}
+		volumeStatus := &csi.ControllerGetVolumeResponse_VolumeStatus{
+			VolumeCondition: common.GetVolumeCondition(ctx, volumeID, cnsVolume.HealthStatus),
+		}
+		// Published nodes are tracked from VolumeAttachments only when
+		// ListVolumes is enabled, which is also when they are advertised.
+		if commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.ListVolumes) {
+			nodeNames := commonco.ContainerOrchestratorUtility.GetNodesForVolumes(ctx,
+				[]string{req.GetVolumeId()})[req.GetVolumeId()]
+			for _, nodeName := range nodeNames {
+				nodeVM, err := c.nodeMgr.GetNodeVMByNameAndUpdateCache(ctx, nodeName)
+				if err != nil {
+					return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+						"failed to get node VM for node %q. Error: %+v", nodeName, err)
+				}
+				volumeStatus.PublishedNodeIds = append(volumeStatus.PublishedNodeIds, nodeVM.UUID)
+			}
+		}
+		return &csi.ControllerGetVolumeResponse{
+			Volume: volume,
+			Status: volumeStatus,
+		}, "", nil
+	}
+
+	resp, faultType, err := controllerGetVolumeInternal()
+	if err != nil {
+		if csifault.IsNonStorageFault(faultType) {
+			faultType = csifault.AddCsiNonStoragePrefix(ctx, faultType)
+		}
+		log.Errorf("Operation failed, reporting failure status to Prometheus."+
+			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
+			prometheus.PrometheusGetVolumeOpType, volumeType, faultType)
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusGetVolumeOpType,
+			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
+	} else {
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusGetVolumeOpType,
+			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
+	}
+	return resp, err
 }
 
+// ControllerModifyVolume changes the storage policy of a block volume to the
+// one named by the "storagepolicyname" mutable parameter of the
+// VolumeAttributesClass. The policy is reconfigured in place if the current
+// datastore is compatible with it, otherwise the volume is relocated to a
+// compatible datastore shared by all nodes.
 func (c *controller) ControllerModifyVolume(ctx context.Context, req *csi.ControllerModifyVolumeRequest) (
 	*csi.ControllerModifyVolumeResponse, error) {
+	start := time.Now()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
-	log.Infof("ControllerModifyVolume: called with args %+v", req)
-	return nil, logger.LogNewErrorCode(log, codes.Unimplemented, "ControllerModifyVolume")
+	volumeType := prometheus.PrometheusBlockVolumeType
+	target := c.opTarget("", "")
+
+	controllerModifyVolumeInternal := func() (*csi.ControllerModifyVolumeResponse, string, error) {
+		log.Infof("ControllerModifyVolume: called with args %+v", req)
+		volumeID := req.GetVolumeId()
+		if volumeID == "" {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
+				"volume ID must be provided")
+		}
+		if len(req.GetMutableParameters()) == 0 {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
+				"mutable parameters must be provided")
+		}
+		var storagePolicyName string
+		for param, value := range req.GetMutableParameters() {
+			if strings.ToLower(param) != common.AttributeStoragePolicyName {
+				return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+					"mutable parameter %q is not supported. Only %q can be modified",
+					param, common.AttributeStoragePolicyName)
+			}
+			storagePolicyName = value
+		}
+		if storagePolicyName == "" {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+				"mutable parameter %q must not be empty", common.AttributeStoragePolicyName)
+		}
+		if strings.Contains(volumeID, ".vmdk") {
+			return nil, csifault.CSIUnimplementedFault, logger.LogNewErrorCodef(log, codes.Unimplemented,
+				"cannot modify migrated vSphere volume %q", volumeID)
+		}
+
+		vcHost, volumeManager, err := getVCenterAndVolumeManagerForVolumeID(ctx, c, volumeID, volumeInfoService)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get vCenter/volume manager for volume Id: %q. Error: %v", volumeID, err)
+		}
+		target = c.opTarget(vcHost, volumeID)
+		vcenter, err := common.GetVCenterFromVCHost(ctx, c.managers.VcenterManager, vcHost)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get vCenter instance for host %q. Error: %+v", vcHost, err)
+		}
+		storagePolicyID, err := vcenter.GetStoragePolicyIDByName(ctx, storagePolicyName)
+		if err != nil {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+				"failed to get policy ID for storage policy name %q in vCenter %q. Error: %+v",
+				storagePolicyName, vcHost, err)
+		}
+
+		queryFilter := cnstypes.CnsQueryFilter{
+			VolumeIds: []cnstypes.CnsVolumeId{{Id: volumeID}},
+		}
+		querySelection := cnstypes.CnsQuerySelection{
+			Names: []string{
+				string(cnstypes.QuerySelectionNameTypeVolumeType),
+				string(cnstypes.QuerySelectionNameTypePolicyId),
+				string(cnstypes.QuerySelectionNameTypeDataStoreUrl),
+			},
+		}
+		queryResult, err := utils.QueryVolumeUtil(ctx, volumeManager, queryFilter, &querySelection)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to query volume %q. Error: %+v", volumeID, err)
+		}
+		if len(queryResult.Volumes) == 0 {
+			return nil, csifault.CSINotFoundFault, logger.LogNewErrorCodef(log, codes.NotFound,
+				"volume %q not found", volumeID)
+		}
+		cnsVolume := queryResult.Volumes[0]
+		if cnsVolume.VolumeType == common.FileVolumeType {
+			volumeType = prometheus.PrometheusFileVolumeType
+			return nil, csifault.CSIUnimplementedFault, logger.LogNewErrorCodef(log, codes.Unimplemented,
+				"cannot change the storage policy of file volume %q", volumeID)
+		}
+		if cnsVolume.StoragePolicyId == storagePolicyID {
+			log.Infof("ControllerModifyVolume: volume %q already has storage policy %q",
+				volumeID, storagePolicyName)
+			return &csi.ControllerModifyVolumeResponse{}, "", nil
+		}
+
+		targetDatastore, faultType, err := c.getTargetDatastoreForPolicyChange(ctx, vcenter, vcHost,
+			cnsVolume.DatastoreUrl, storagePolicyID)
+		if err != nil {
+			return nil, faultType, err
+		}
+		if targetDatastore != nil {
+			log.Infof("ControllerModifyVolume: datastore %q of volume %q is not compatible with storage "+
+				"policy %q. Relocating volume to datastore %q", cnsVolume.DatastoreUrl, volumeID,
+				storagePolicyName, targetDatastore.Info.Url)
+			dsMoRef := targetDatastore.Reference()
+			faultType, err = volumeManager.UpdateVolumePolicy(ctx, volumeID, storagePolicyID, &dsMoRef)
+		} else {
+			faultType, err = volumeManager.UpdateVolumePolicy(ctx, volumeID, storagePolicyID, nil)
+		}
+		if err != nil {
+			return nil, faultType, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to change storage policy of volume %q to %q. Error: %+v",
+				volumeID, storagePolicyName, err)
+		}
+		log.Infof("ControllerModifyVolume: changed storage policy of volume %q to %q",
+			volumeID, storagePolicyName)
+		return &csi.ControllerModifyVolumeResponse{}, "", nil
+	}
+
+	resp, faultType, err := controllerModifyVolumeInternal()
+	if err != nil {
+		if csifault.IsNonStorageFault(faultType) {
+			faultType = csifault.AddCsiNonStoragePrefix(ctx, faultType)
+		}
+		log.Errorf("Operation failed, reporting failure status to Prometheus."+
+			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
+			prometheus.PrometheusModifyVolumeOpType, volumeType, faultType)
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusModifyVolumeOpType,
+			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
+	} else {
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusModifyVolumeOpType,
+			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
+	}
+	return resp, err
 }
@@ -18,25 +18,36 @@ package vanilla
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	cnstypes "github.com/vmware/govmomi/cns/types"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/node"
 	cnsvolume "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/volume"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	csifault "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/fault"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/utils"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsvolumeinfo"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsvolumeoperationrequest"
+	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
 )
 
 // validateVanillaDeleteVolumeRequest is the helper function to validate
@@ -271,3 +282,312 @@ func GetVolumeManagerFromVCHost(ctx context.Context, managers *common.Managers,
 	}
 	return volumeMgr, nil
 }
+
+// filterDatastoresByStoragePolicy returns the datastores from the given list
+// which are compatible with the storage policy storagePolicyID. All datastores
+// are returned if storagePolicyID is empty.
+func filterDatastoresByStoragePolicy(ctx context.Context, vc *vsphere.VirtualCenter,
+	datastores []*vsphere.DatastoreInfo, storagePolicyID string) ([]*vsphere.DatastoreInfo, error) {
+	log := logger.GetLogger(ctx)
+	if storagePolicyID == "" || len(datastores) == 0 {
+		return datastores, nil
+	}
+	var dsMoRefs []types.ManagedObjectReference
+	for _, ds := range datastores {
+		dsMoRefs = append(dsMoRefs, ds.Reference())
+	}
+	compat, err := vc.PbmCheckCompatibility(ctx, dsMoRefs, storagePolicyID)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to find datastore compatibility "+
+			"with storage policy ID %q. vCenter: %q Error: %+v", storagePolicyID, vc.Config.Host, err)
+	}
+	compatibleDsMoids := make(map[string]struct{})
+	for _, ds := range compat.CompatibleDatastores() {
+		compatibleDsMoids[ds.HubId] = struct{}{}
+	}
+	var compatibleDatastores []*vsphere.DatastoreInfo
+	for _, ds := range datastores {
+		if _, exists := compatibleDsMoids[ds.Reference().Value]; exists {
+			compatibleDatastores = append(compatibleDatastores, ds)
+		}
+	}
+	return compatibleDatastores, nil
+}
+
+// getTargetDatastoreForPolicyChange returns nil if the datastore with URL
+// currentDatastoreURL is compatible with the given storage policy, in which
+// case the policy can be reconfigured in place. Otherwise it returns the
+// compatible datastore with the most free space among the datastores shared
+// by all nodes in the vCenter, to which the volume has to be relocated.
+func (c *controller) getTargetDatastoreForPolicyChange(ctx context.Context, vc *vsphere.VirtualCenter,
+	vcHost string, currentDatastoreURL string, storagePolicyID string) (*vsphere.DatastoreInfo, string, error) {
+	log := logger.GetLogger(ctx)
+	var (
+		sharedDatastores []*vsphere.DatastoreInfo
+		err              error
+	)
+	if len(c.managers.VcenterConfigs) > 1 {
+		nodeVMs, err := c.nodeMgr.GetAllNodesByVC(ctx, vcHost)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get nodes in vCenter %q. Error: %+v", vcHost, err)
+		}
+		sharedDatastores, err = vsphere.GetSharedDatastoresForVMs(ctx, nodeVMs)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get shared datastores for nodes in vCenter %q. Error: %+v", vcHost, err)
+		}
+	} else {
+		sharedDatastores, err = c.nodeMgr.GetSharedDatastoresInK8SCluster(ctx)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get shared datastores in kubernetes cluster. Error: %+v", err)
+		}
+	}
+
+	// Look up the current datastore among the shared datastores first, and in
+	// the datacenters of the vCenter if the volume was placed on a datastore
+	// accessible to a subset of the nodes only.
+	var currentDatastore *vsphere.DatastoreInfo
+	for _, ds := range sharedDatastores {
+		if strings.TrimSpace(ds.Info.Url) == strings.TrimSpace(currentDatastoreURL) {
+			currentDatastore = ds
+			break
+		}
+	}
+	if currentDatastore == nil {
+		datacenters, err := vc.GetDatacenters(ctx)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get datacenters in vCenter %q. Error: %+v", vcHost, err)
+		}
+		for _, dc := range datacenters {
+			currentDatastore, err = dc.GetDatastoreInfoByURL(ctx, currentDatastoreURL)
+			if err == nil {
+				break
+			}
+		}
+	}
+	if currentDatastore != nil {
+		compatibleDatastores, err := filterDatastoresByStoragePolicy(ctx, vc,
+			[]*vsphere.DatastoreInfo{currentDatastore}, storagePolicyID)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCode(log, codes.Internal, err.Error())
+		}
+		if len(compatibleDatastores) != 0 {
+			return nil, "", nil
+		}
+	} else {
+		log.Warnf("failed to find datastore with URL %q in vCenter %q", currentDatastoreURL, vcHost)
+	}
+
+	candidateDatastores, err := filterDatastoresByStoragePolicy(ctx, vc, sharedDatastores, storagePolicyID)
+	if err != nil {
+		return nil, csifault.CSIInternalFault, logger.LogNewErrorCode(log, codes.Internal, err.Error())
+	}
+	if len(candidateDatastores) != 0 {
+		candidateDatastores, err = c.filterDatastores(ctx, candidateDatastores, vcHost)
+		if err != nil && err != errAllDSFilteredOut {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to filter datastores based on authorisation check in vCenter %q. Error: %+v",
+				vcHost, err)
+		}
+	}
+	if len(candidateDatastores) != 0 {
+		candidateDatastores, err = vsphere.FilterSuspendedDatastores(ctx, candidateDatastores)
+		if err != nil {
+			return nil, csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to filter suspended datastores in vCenter %q. Error: %+v", vcHost, err)
+		}
+	}
+	var targetDatastore *vsphere.DatastoreInfo
+	for _, ds := range candidateDatastores {
+		if targetDatastore == nil || ds.Info.FreeSpace > targetDatastore.Info.FreeSpace {
+			targetDatastore = ds
+		}
+	}
+	if targetDatastore == nil {
+		return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+			"no datastore shared by all nodes in vCenter %q is compatible with storage policy ID %q",
+			vcHost, storagePolicyID)
+	}
+	return targetDatastore, "", nil
+}
+
+// isCreateVolumeTaskPending returns whether the CreateVolume task of the
+// volume is still pending in CNS as per the operation store.
+func isCreateVolumeTaskPending(ctx context.Context, operationStore cnsvolumeoperationrequest.VolumeOperationRequest,
+	volumeName string) bool {
+	log := logger.GetLogger(ctx)
+	volumeOperationDetails, err := operationStore.GetRequestDetails(ctx, volumeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		// Assume the task is pending, so that the snapshot used by it is kept.
+		log.Warnf("failed to get CreateVolume task details for volume %q. Error: %+v", volumeName, err)
+		return true
+	}
+	return volumeOperationDetails.OperationDetails != nil && cnsvolume.IsTaskPending(volumeOperationDetails)
+}
+
+// deleteCloneSourceSnapshots deletes the transient snapshots taken on the
+// source volume to clone it into the volume with the given name. Once they
+// are deleted, the stored details of creating them are removed too, so that a
+// retry of the clone takes a new snapshot instead of reusing the deleted one.
+func deleteCloneSourceSnapshots(ctx context.Context, volumeManager cnsvolume.Manager,
+	operationStore cnsvolumeoperationrequest.VolumeOperationRequest, sourceVolumeID string, volumeName string) {
+	log := logger.GetLogger(ctx)
+	queryFilter := cnstypes.CnsSnapshotQueryFilter{
+		SnapshotQuerySpecs: []cnstypes.CnsSnapshotQuerySpec{
+			{
+				VolumeId: cnstypes.CnsVolumeId{Id: sourceVolumeID},
+			},
+		},
+		Cursor: &cnstypes.CnsCursor{
+			Offset: 0,
+			Limit:  common.QuerySnapshotLimit,
+		},
+	}
+	queryResultEntries, _, err := utils.QuerySnapshotsUtil(ctx, volumeManager, queryFilter,
+		common.QuerySnapshotLimit)
+	if err != nil {
+		log.Warnf("failed to query the snapshots of source volume %q for clone %q. The snapshot taken "+
+			"for the clone needs to be deleted manually. Error: %+v", sourceVolumeID, volumeName, err)
+		return
+	}
+	// Depending on the idempotency handling, the ID of the source volume is
+	// appended to the description of the snapshot.
+	cloneSnapshotDescriptions := map[string]bool{
+		common.CloneSourceSnapshotPrefix + volumeName:                        true,
+		common.CloneSourceSnapshotPrefix + volumeName + "-" + sourceVolumeID: true,
+	}
+	for _, queryResult := range queryResultEntries {
+		if queryResult.Error != nil || !cloneSnapshotDescriptions[queryResult.Snapshot.Description] {
+			continue
+		}
+		cloneSnapshotID := queryResult.Snapshot.VolumeId.Id + common.VSphereCSISnapshotIdDelimiter +
+			queryResult.Snapshot.SnapshotId.Id
+		if _, err := common.DeleteSnapshotUtil(ctx, volumeManager, cloneSnapshotID, nil); err != nil {
+			log.Warnf("failed to delete snapshot %q taken on source volume %q for clone %q. "+
+				"The snapshot needs to be deleted manually. Error: %+v",
+				cloneSnapshotID, sourceVolumeID, volumeName, err)
+			return
+		}
+	}
+	if err := cnsvolume.ResetCreateSnapshotDetails(ctx, operationStore, sourceVolumeID,
+		common.CloneSourceSnapshotPrefix+volumeName); err != nil {
+		log.Warnf("failed to remove the CreateSnapshot details of the snapshot taken on source volume %q "+
+			"for clone %q. Error: %+v", sourceVolumeID, volumeName, err)
+	}
+}
+
+var (
+	// getPVC returns the PVC with the given namespace and name.
+	getPVC = _getPVC
+
+	pvcClient      clientset.Interface
+	pvcClientMutex sync.Mutex
+)
+
+// _getPVC gets the PVC from the API server with a Kubernetes client created on
+// first use.
+func _getPVC(ctx context.Context, namespace, name string) (*v1.PersistentVolumeClaim, error) {
+	pvcClientMutex.Lock()
+	if pvcClient == nil {
+		client, err := k8s.NewClient(ctx)
+		if err != nil {
+			pvcClientMutex.Unlock()
+			return nil, err
+		}
+		pvcClient = client
+	}
+	client := pvcClient
+	pvcClientMutex.Unlock()
+	return client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// applyPVCRequestedTopology restricts the accessibility requirements of the
+// CreateVolume request to the zones of the csi.vsphere.volume-requested-topology
+// annotation of its PVC, e.g. set by the topology injection of the webhook.
+// The PVC is known from the parameters the external-provisioner adds with
+// --extra-create-metadata. The request is left unchanged if the PVC has no
+// such annotation.
+func applyPVCRequestedTopology(ctx context.Context, req *csi.CreateVolumeRequest) (string, error) {
+	log := logger.GetLogger(ctx)
+	pvcName := req.Parameters[common.AttributePvcName]
+	pvcNamespace := req.Parameters[common.AttributePvcNamespace]
+	if pvcName == "" || pvcNamespace == "" {
+		return "", nil
+	}
+	pvc, err := getPVC(ctx, pvcNamespace, pvcName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return csifault.CSIInternalFault, logger.LogNewErrorCodef(log, codes.Internal,
+			"failed to get PVC %s/%s. Error: %v", pvcNamespace, pvcName, err)
+	}
+	annotation := strings.TrimSpace(pvc.Annotations[common.AnnGuestClusterRequestedTopology])
+	if annotation == "" {
+		return "", nil
+	}
+	var requestedSegments []map[string]string
+	if err := json.Unmarshal([]byte(annotation), &requestedSegments); err != nil {
+		return csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+			"invalid %s annotation %q of PVC %s/%s. Error: %v", common.AnnGuestClusterRequestedTopology,
+			annotation, pvcNamespace, pvcName, err)
+	}
+	if len(requestedSegments) == 0 {
+		return "", nil
+	}
+
+	requested := make([]*csi.Topology, 0, len(requestedSegments))
+	for _, segments := range requestedSegments {
+		requested = append(requested, &csi.Topology{Segments: segments})
+	}
+	requisite, preferred := requested, requested
+	if requirement := req.GetAccessibilityRequirements(); requirement != nil {
+		if len(requirement.Requisite) > 0 {
+			requisite = filterTopologies(requirement.Requisite, requestedSegments)
+			if len(requisite) == 0 {
+				return csifault.CSIInvalidArgumentFault, logger.LogNewErrorCodef(log, codes.InvalidArgument,
+					"requested topology %s of PVC %s/%s is not allowed by the accessibility requirements %+v",
+					annotation, pvcNamespace, pvcName, requirement)
+			}
+		}
+		preferred = filterTopologies(requirement.Preferred, requestedSegments)
+		if len(preferred) == 0 {
+			preferred = requisite
+		}
+	}
+	log.Infof("Restricting the accessibility requirements of volume %q to the requested topology %s of PVC %s/%s",
+		req.Name, annotation, pvcNamespace, pvcName)
+	req.AccessibilityRequirements = &csi.TopologyRequirement{
+		Requisite: requisite,
+		Preferred: preferred,
+	}
+	return "", nil
+}
+
+// filterTopologies returns the topologies matching any of the requested
+// segments.
+func filterTopologies(topologies []*csi.Topology, requestedSegments []map[string]string) []*csi.Topology {
+	var filtered []*csi.Topology
+	for _, topology := range topologies {
+		for _, segments := range requestedSegments {
+			matches := true
+			for key, value := range segments {
+				if topology.GetSegments()[key] != value {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				filtered = append(filtered, topology)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vmware/govmomi/cns"
@@ -33,6 +34,7 @@ import (
 	"github.com/vmware/govmomi/pbm"
 	"github.com/vmware/govmomi/pbm/types"
 	"github.com/vmware/govmomi/vim25"
+	vim25types "github.com/vmware/govmomi/vim25/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/prototext"
@@ -47,6 +49,7 @@ import (
 	cnsvolume "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/volume"
 	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	csifault "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/fault"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/unittestcommon"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
@@ -373,7 +376,7 @@ func TestCreateVolumeWithStoragePolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -395,7 +398,7 @@ func TestCreateVolumeWithStoragePolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -531,7 +534,7 @@ func TestCreateVolumeWithNewlyCreatedStoragePolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -606,7 +609,7 @@ func TestCreateVolumeWithMultipleDatastores(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -778,7 +781,7 @@ func TestExtendVolume(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -796,7 +799,7 @@ func TestExtendVolume(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -833,7 +836,7 @@ func TestExtendVolume(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the expanded volume with ID: %s", volID)
 	}
 
@@ -920,7 +923,7 @@ func TestCompleteControllerFlow(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -938,7 +941,7 @@ func TestCompleteControllerFlow(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1134,7 +1137,7 @@ func TestCreateBlockVolumeSnapshotWithIdempotency(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1152,7 +1155,7 @@ func TestCreateBlockVolumeSnapshotWithIdempotency(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1309,7 +1312,7 @@ func TestCreateBlockVolumeSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1327,7 +1330,7 @@ func TestCreateBlockVolumeSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1450,7 +1453,7 @@ func TestCreateVolumeFromSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1468,7 +1471,7 @@ func TestCreateVolumeFromSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1553,7 +1556,7 @@ func TestCreateVolumeFromSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != restoredVolID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != restoredVolID {
 		t.Fatalf("failed to find the newly created volume from snapshot with ID: %s", restoredVolID)
 	}
 
@@ -1578,7 +1581,7 @@ func TestCreateVolumeFromSnapshot(t *testing.T) {
 		}
 	}()
 
-	// Create a new volume from the snapshot with unexpected request
+	// Create a new volume from the snapshot with a larger size than the snapshot
 	reqCreateFromSnapshot = &csi.CreateVolumeRequest{
 		Name: testVolumeName + "-" + uuid.New().String(),
 		CapacityRange: &csi.CapacityRange{
@@ -1595,6 +1598,65 @@ func TestCreateVolumeFromSnapshot(t *testing.T) {
 		},
 	}
 
+	respCreateFromSnapshot, err = ct.controller.CreateVolume(ctx, reqCreateFromSnapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expandedVolID := respCreateFromSnapshot.Volume.VolumeId
+	if respCreateFromSnapshot.Volume.CapacityBytes != 2*common.GbInBytes {
+		t.Fatalf("expected capacity %d for volume restored from snapshot, got %d",
+			2*common.GbInBytes, respCreateFromSnapshot.Volume.CapacityBytes)
+	}
+
+	// Verify the restored volume has been expanded to the requested size.
+	expandedQueryFilter := cnstypes.CnsQueryFilter{
+		VolumeIds: []cnstypes.CnsVolumeId{
+			{
+				Id: expandedVolID,
+			},
+		},
+	}
+	queryResult, err = ct.vcenter.CnsClient.QueryVolume(ctx, &expandedQueryFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queryResult.Volumes) != 1 {
+		t.Fatalf("failed to find the newly created volume from snapshot with ID: %s", expandedVolID)
+	}
+	expandedSizeMB := queryResult.Volumes[0].BackingObjectDetails.GetCnsBackingObjectDetails().CapacityInMb
+	if expandedSizeMB != 2*common.GbInBytes/common.MbInBytes {
+		t.Fatalf("expected volume %s restored from snapshot to be expanded to %d MB, got %d MB",
+			expandedVolID, 2*common.GbInBytes/common.MbInBytes, expandedSizeMB)
+	}
+
+	defer func() {
+		// Delete the expanded restored volume
+		reqDelete := &csi.DeleteVolumeRequest{
+			VolumeId: expandedVolID,
+		}
+		_, err = ct.controller.DeleteVolume(ctx, reqDelete)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create a new volume from the snapshot with unexpected request
+	reqCreateFromSnapshot = &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 512 * common.MbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{
+				Snapshot: &csi.VolumeContentSource_SnapshotSource{
+					SnapshotId: snapID,
+				},
+			},
+		},
+	}
+
 	_, err = ct.controller.CreateVolume(ctx, reqCreateFromSnapshot)
 	if err != nil {
 		statusErr, ok := status.FromError(err)
@@ -1612,6 +1674,414 @@ func TestCreateVolumeFromSnapshot(t *testing.T) {
 	}
 }
 
+func TestCreateVolumeFromVolume(t *testing.T) {
+	ct := getControllerTest(t)
+
+	// Create.
+	params := make(map[string]string)
+	if v := os.Getenv("VSPHERE_DATASTORE_URL"); v != "" {
+		params[common.AttributeDatastoreURL] = v
+	}
+	capabilities := []*csi.VolumeCapability{
+		{
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			},
+		},
+	}
+
+	reqCreate := &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+	}
+
+	respCreate, err := ct.controller.CreateVolume(ctx, reqCreate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	volID := respCreate.Volume.VolumeId
+
+	defer func() {
+		// Delete the source volume.
+		reqDelete := &csi.DeleteVolumeRequest{
+			VolumeId: volID,
+		}
+		_, err = ct.controller.DeleteVolume(ctx, reqDelete)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Clone the volume with expected request.
+	reqCreateFromVolume := &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{
+				Volume: &csi.VolumeContentSource_VolumeSource{
+					VolumeId: volID,
+				},
+			},
+		},
+	}
+
+	respCreateFromVolume, err := ct.controller.CreateVolume(ctx, reqCreateFromVolume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clonedVolID := respCreateFromVolume.Volume.VolumeId
+	if respCreateFromVolume.Volume.GetContentSource().GetVolume().GetVolumeId() != volID {
+		t.Fatalf("expected content source volume %q in response, got %+v", volID,
+			respCreateFromVolume.Volume.GetContentSource())
+	}
+
+	// Verify the volume has been created.
+	queryFilter := cnstypes.CnsQueryFilter{
+		VolumeIds: []cnstypes.CnsVolumeId{
+			{
+				Id: clonedVolID,
+			},
+		},
+	}
+	queryResult, err := ct.vcenter.CnsClient.QueryVolume(ctx, &queryFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != clonedVolID {
+		t.Fatalf("failed to find the newly cloned volume with ID: %s", clonedVolID)
+	}
+
+	defer func() {
+		// Delete the cloned volume.
+		reqDelete := &csi.DeleteVolumeRequest{
+			VolumeId: clonedVolID,
+		}
+		_, err = ct.controller.DeleteVolume(ctx, reqDelete)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Verify the transient snapshot on the source volume has been removed.
+	respListSnapshots, err := ct.controller.ListSnapshots(ctx, &csi.ListSnapshotsRequest{
+		SourceVolumeId: volID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(respListSnapshots.Entries) != 0 {
+		t.Fatalf("expected no snapshots on source volume %q after clone, got %d",
+			volID, len(respListSnapshots.Entries))
+	}
+
+	// Clone the volume with unexpected request.
+	reqCreateFromVolume = &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 512 * common.MbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{
+				Volume: &csi.VolumeContentSource_VolumeSource{
+					VolumeId: volID,
+				},
+			},
+		},
+	}
+
+	_, err = ct.controller.CreateVolume(ctx, reqCreateFromVolume)
+	if err != nil {
+		statusErr, ok := status.FromError(err)
+		if !ok {
+			t.Fatalf("unable to convert the error: %+v into a grpc status error type", err)
+		}
+		if statusErr.Code() == codes.InvalidArgument {
+			t.Logf("received error as expected when attempting to clone volume, error: %+v", err)
+		} else {
+			t.Fatalf("unexpected error code received, expected: %s received: %s",
+				codes.InvalidArgument.String(), statusErr.Code().String())
+		}
+	} else {
+		t.Fatal("expected error was not received when cloning volume")
+	}
+}
+
+// failingCreateVolumeManager is a volume manager whose CreateVolume fails the
+// given number of times before calling the wrapped volume manager.
+type failingCreateVolumeManager struct {
+	cnsvolume.Manager
+	failures int
+}
+
+func (m *failingCreateVolumeManager) CreateVolume(ctx context.Context, spec *cnstypes.CnsVolumeCreateSpec,
+	extraParams interface{}) (*cnsvolume.CnsVolumeInfo, string, error) {
+	if m.failures > 0 {
+		m.failures--
+		return nil, csifault.CSIInternalFault, errors.New("injected CreateVolume failure")
+	}
+	return m.Manager.CreateVolume(ctx, spec, extraParams)
+}
+
+// TestCreateVolumeFromVolumeRetry verifies a clone whose first attempt failed
+// succeeds on retry with a new snapshot of the source volume, as the snapshot
+// of the failed attempt has been deleted.
+func TestCreateVolumeFromVolumeRetry(t *testing.T) {
+	ct := getControllerTest(t)
+
+	params := make(map[string]string)
+	if v := os.Getenv("VSPHERE_DATASTORE_URL"); v != "" {
+		params[common.AttributeDatastoreURL] = v
+	}
+	capabilities := []*csi.VolumeCapability{
+		{
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			},
+		},
+	}
+	respCreate, err := ct.controller.CreateVolume(ctx, &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	volID := respCreate.Volume.VolumeId
+	defer func() {
+		_, err = ct.controller.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	vcHost := ct.config.Global.VCenterIP
+	volumeManager := ct.controller.managers.VolumeManagers[vcHost]
+	ct.controller.managers.VolumeManagers[vcHost] = &failingCreateVolumeManager{
+		Manager:  volumeManager,
+		failures: 1,
+	}
+	defer func() {
+		ct.controller.managers.VolumeManagers[vcHost] = volumeManager
+	}()
+
+	reqCreateFromVolume := &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{
+				Volume: &csi.VolumeContentSource_VolumeSource{
+					VolumeId: volID,
+				},
+			},
+		},
+	}
+	if _, err = ct.controller.CreateVolume(ctx, reqCreateFromVolume); err == nil {
+		t.Fatal("expected the first attempt to clone the volume to fail")
+	}
+	// The snapshot of the failed attempt is deleted, so its CreateSnapshot
+	// details must not be reused by the retry.
+	_, err = ct.operationStore.GetRequestDetails(ctx,
+		common.CloneSourceSnapshotPrefix+reqCreateFromVolume.Name+"-"+volID)
+	if err == nil {
+		t.Fatal("expected the CreateSnapshot details of the deleted clone snapshot to be removed")
+	}
+
+	respCreateFromVolume, err := ct.controller.CreateVolume(ctx, reqCreateFromVolume)
+	if err != nil {
+		t.Fatalf("failed to clone the volume on retry: %v", err)
+	}
+	defer func() {
+		_, err = ct.controller.DeleteVolume(ctx,
+			&csi.DeleteVolumeRequest{VolumeId: respCreateFromVolume.Volume.VolumeId})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	respListSnapshots, err := ct.controller.ListSnapshots(ctx, &csi.ListSnapshotsRequest{
+		SourceVolumeId: volID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(respListSnapshots.Entries) != 0 {
+		t.Fatalf("expected no snapshots on source volume %q after clone, got %d",
+			volID, len(respListSnapshots.Entries))
+	}
+}
+
+// failingExpandVolumeManager is a volume manager whose ExpandVolume fails the
+// given number of times before calling the wrapped volume manager.
+type failingExpandVolumeManager struct {
+	cnsvolume.Manager
+	failures int
+}
+
+func (m *failingExpandVolumeManager) ExpandVolume(ctx context.Context, volumeID string, size int64,
+	extraParams interface{}) (string, error) {
+	if m.failures > 0 {
+		m.failures--
+		return csifault.CSIInternalFault, errors.New("injected ExpandVolume failure")
+	}
+	return m.Manager.ExpandVolume(ctx, volumeID, size, extraParams)
+}
+
+// TestCreateVolumeFromVolumeExpandRetry verifies a clone created by an attempt
+// which failed to expand it to the requested size is expanded by the retry,
+// even if the source volume has been expanded in between.
+func TestCreateVolumeFromVolumeExpandRetry(t *testing.T) {
+	ct := getControllerTest(t)
+
+	params := make(map[string]string)
+	if v := os.Getenv("VSPHERE_DATASTORE_URL"); v != "" {
+		params[common.AttributeDatastoreURL] = v
+	}
+	capabilities := []*csi.VolumeCapability{
+		{
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			},
+		},
+	}
+	respCreate, err := ct.controller.CreateVolume(ctx, &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	volID := respCreate.Volume.VolumeId
+	defer func() {
+		_, err = ct.controller.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	vcHost := ct.config.Global.VCenterIP
+	volumeManager := ct.controller.managers.VolumeManagers[vcHost]
+	ct.controller.managers.VolumeManagers[vcHost] = &failingExpandVolumeManager{
+		Manager:  volumeManager,
+		failures: 1,
+	}
+	defer func() {
+		ct.controller.managers.VolumeManagers[vcHost] = volumeManager
+	}()
+
+	reqCreateFromVolume := &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 2 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{
+				Volume: &csi.VolumeContentSource_VolumeSource{
+					VolumeId: volID,
+				},
+			},
+		},
+	}
+	if _, err = ct.controller.CreateVolume(ctx, reqCreateFromVolume); err == nil {
+		t.Fatal("expected the first attempt to expand the clone to fail")
+	}
+
+	// Expand the source volume to the requested size of the clone.
+	_, err = ct.controller.ControllerExpandVolume(ctx, &csi.ControllerExpandVolumeRequest{
+		VolumeId: volID,
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 2 * common.GbInBytes,
+		},
+		VolumeCapability: capabilities[0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	respCreateFromVolume, err := ct.controller.CreateVolume(ctx, reqCreateFromVolume)
+	if err != nil {
+		t.Fatalf("failed to clone the volume on retry: %v", err)
+	}
+	clonedVolID := respCreateFromVolume.Volume.VolumeId
+	defer func() {
+		_, err = ct.controller.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: clonedVolID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	queryResult, err := ct.vcenter.CnsClient.QueryVolume(ctx, &cnstypes.CnsQueryFilter{
+		VolumeIds: []cnstypes.CnsVolumeId{{Id: clonedVolID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queryResult.Volumes) != 1 {
+		t.Fatalf("failed to find the cloned volume with ID: %s", clonedVolID)
+	}
+	clonedSizeMB := queryResult.Volumes[0].BackingObjectDetails.GetCnsBackingObjectDetails().CapacityInMb
+	if clonedSizeMB != 2*common.GbInBytes/common.MbInBytes {
+		t.Fatalf("expected cloned volume %s to be expanded to %d MB, got %d MB",
+			clonedVolID, 2*common.GbInBytes/common.MbInBytes, clonedSizeMB)
+	}
+}
+
+// TestIsCreateVolumeTaskPending verifies the snapshot taken for a clone is
+// only deleted once the CreateVolume task using it is no longer pending.
+func TestIsCreateVolumeTaskPending(t *testing.T) {
+	ct := getControllerTest(t)
+
+	volumeName := testVolumeName + "-" + uuid.New().String()
+	if isCreateVolumeTaskPending(ctx, ct.operationStore, volumeName) {
+		t.Fatalf("expected no pending CreateVolume task for volume %q without operation details", volumeName)
+	}
+	for _, taskStatus := range []string{
+		cnsvolumeoperationrequest.TaskInvocationStatusInProgress,
+		cnsvolumeoperationrequest.TaskInvocationStatusTimedOut,
+	} {
+		_ = ct.operationStore.StoreRequestDetails(ctx, cnsvolumeoperationrequest.CreateVolumeOperationRequestDetails(
+			volumeName, "", "", 0, nil, metav1.Now(), "task-1", "", "", taskStatus, "", ""))
+		if !isCreateVolumeTaskPending(ctx, ct.operationStore, volumeName) {
+			t.Fatalf("expected CreateVolume task for volume %q with status %q to be pending", volumeName, taskStatus)
+		}
+	}
+	for _, taskStatus := range []string{
+		cnsvolumeoperationrequest.TaskInvocationStatusSuccess,
+		cnsvolumeoperationrequest.TaskInvocationStatusError,
+	} {
+		_ = ct.operationStore.StoreRequestDetails(ctx, cnsvolumeoperationrequest.CreateVolumeOperationRequestDetails(
+			volumeName, "", "", 0, nil, metav1.Now(), "task-1", "", "", taskStatus, "", ""))
+		if isCreateVolumeTaskPending(ctx, ct.operationStore, volumeName) {
+			t.Fatalf("expected CreateVolume task for volume %q with status %q not to be pending",
+				volumeName, taskStatus)
+		}
+	}
+}
+
 func TestListSnapshotsOnSpecificVolumeAndSnapshot(t *testing.T) {
 	ct := getControllerTest(t)
 
@@ -1656,7 +2126,7 @@ func TestListSnapshotsOnSpecificVolumeAndSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1778,7 +2248,7 @@ func TestListSnapshotsOnSpecificVolume(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -1901,7 +2371,7 @@ func TestListSnapshots(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2023,7 +2493,7 @@ func TestListSnapshotsWithToken(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2155,7 +2625,7 @@ func TestExpandVolumeWithSnapshots(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2267,7 +2737,7 @@ func TestDeleteBlockVolumeSnapshotWithManagedObjectNotFound(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2285,7 +2755,7 @@ func TestDeleteBlockVolumeSnapshotWithManagedObjectNotFound(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2376,7 +2846,7 @@ func TestCreateSnapshotWithManagedObjectNotFound(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2394,7 +2864,7 @@ func TestCreateSnapshotWithManagedObjectNotFound(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2501,7 +2971,7 @@ func TestCreateSnapshotWithCnsSnapshotCreatedFault(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2519,7 +2989,7 @@ func TestCreateSnapshotWithCnsSnapshotCreatedFault(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if len(queryResult.Volumes) != 1 && queryResult.Volumes[0].VolumeId.Id != volID {
+	if len(queryResult.Volumes) != 1 || queryResult.Volumes[0].VolumeId.Id != volID {
 		t.Fatalf("failed to find the newly created volume with ID: %s", volID)
 	}
 
@@ -2601,3 +3071,273 @@ func TestCreateSnapshotWithCnsSnapshotCreatedFault(t *testing.T) {
 		t.Fatal("expected error was not received for create snapshot operation.")
 	}
 }
+
+func TestGetCapacity(t *testing.T) {
+	ct := getControllerTest(t)
+
+	params := make(map[string]string)
+	// PBM simulator defaults.
+	params[common.AttributeStoragePolicyName] = "vSAN Default Storage Policy"
+	if v := os.Getenv("VSPHERE_STORAGE_POLICY_NAME"); v != "" {
+		params[common.AttributeStoragePolicyName] = v
+	}
+	capabilities := []*csi.VolumeCapability{
+		{
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			},
+		},
+	}
+	resp, err := ct.controller.GetCapacity(ctx, &csi.GetCapacityRequest{
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AvailableCapacity <= 0 {
+		t.Fatalf("expected available capacity to be greater than 0, got %d", resp.AvailableCapacity)
+	}
+	if resp.GetMaximumVolumeSize().GetValue() <= 0 ||
+		resp.GetMaximumVolumeSize().GetValue() > resp.AvailableCapacity {
+		t.Fatalf("unexpected maximum volume size %d for available capacity %d",
+			resp.GetMaximumVolumeSize().GetValue(), resp.AvailableCapacity)
+	}
+
+	// A datastore URL that none of the shared datastores match has no capacity.
+	params[common.AttributeDatastoreURL] = "ds:///vmfs/volumes/nonexistent/"
+	resp, err = ct.controller.GetCapacity(ctx, &csi.GetCapacityRequest{
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AvailableCapacity != 0 || resp.GetMaximumVolumeSize().GetValue() != 0 {
+		t.Fatalf("expected no capacity for unknown datastore URL, got available capacity %d, "+
+			"maximum volume size %d", resp.AvailableCapacity, resp.GetMaximumVolumeSize().GetValue())
+	}
+}
+
+// TestStoreCapacity verifies expired GetCapacity results are evicted from the
+// cache when a new result is stored.
+func TestStoreCapacity(t *testing.T) {
+	capacityCacheMutex.Lock()
+	capacityCache["expired"] = &capacityCacheEntry{
+		lastRefreshed: time.Now().Add(-common.GetCapacityCacheTTL),
+	}
+	capacityCache["valid"] = &capacityCacheEntry{lastRefreshed: time.Now()}
+	capacityCacheMutex.Unlock()
+	defer func() {
+		capacityCacheMutex.Lock()
+		delete(capacityCache, "valid")
+		delete(capacityCache, "new")
+		capacityCacheMutex.Unlock()
+	}()
+
+	storeCapacity("new", &capacityCacheEntry{lastRefreshed: time.Now()})
+	capacityCacheMutex.Lock()
+	defer capacityCacheMutex.Unlock()
+	if _, ok := capacityCache["expired"]; ok {
+		t.Fatal("expected the expired capacity to be evicted")
+	}
+	for _, key := range []string{"valid", "new"} {
+		if _, ok := capacityCache[key]; !ok {
+			t.Fatalf("expected capacity %q to be cached", key)
+		}
+	}
+}
+
+func TestGetFsEnabledDatastores(t *testing.T) {
+	ctx := context.Background()
+	newDatastoreInfo := func(url string) *cnsvsphere.DatastoreInfo {
+		return &cnsvsphere.DatastoreInfo{Info: &vim25types.DatastoreInfo{Url: url}}
+	}
+	vc1, vc2 := "vc1.example.com", "vc2.example.com"
+	c := &controller{
+		managers: &common.Managers{
+			VcenterConfigs: map[string]*cnsvsphere.VirtualCenterConfig{
+				vc1: {Host: vc1},
+				vc2: {Host: vc2, TargetvSANFileShareClusters: []string{"domain-c2", "domain-c3"}},
+			},
+		},
+		authMgrs: make(map[string]*common.AuthManager),
+	}
+	for _, vcHost := range []string{vc1, vc2} {
+		c.authMgrs[vcHost], _ = common.GetAuthorizationServiceForTesting(ctx, nil, nil,
+			map[string][]*cnsvsphere.DatastoreInfo{
+				"domain-c1": {newDatastoreInfo("ds:///vmfs/volumes/" + vcHost + "/vsan1/")},
+				"domain-c2": {newDatastoreInfo("ds:///vmfs/volumes/" + vcHost + "/vsan2/")},
+			})
+	}
+
+	getURLs := func(datastores []*cnsvsphere.DatastoreInfo) map[string]bool {
+		urls := make(map[string]bool)
+		for _, ds := range datastores {
+			urls[ds.Info.Url] = true
+		}
+		return urls
+	}
+	// All the file service enabled clusters are used when no target cluster is set.
+	urls := getURLs(c.getFsEnabledDatastores(ctx, vc1))
+	if len(urls) != 2 || !urls["ds:///vmfs/volumes/vc1.example.com/vsan1/"] ||
+		!urls["ds:///vmfs/volumes/vc1.example.com/vsan2/"] {
+		t.Fatalf("unexpected datastores %v for vCenter %q", urls, vc1)
+	}
+	// Only the target clusters of the vCenter are used otherwise.
+	urls = getURLs(c.getFsEnabledDatastores(ctx, vc2))
+	if len(urls) != 1 || !urls["ds:///vmfs/volumes/vc2.example.com/vsan2/"] {
+		t.Fatalf("unexpected datastores %v for vCenter %q", urls, vc2)
+	}
+	if datastores := c.getFsEnabledDatastores(ctx, "vc3.example.com"); len(datastores) != 0 {
+		t.Fatalf("expected no datastores for an unknown vCenter, got %v", datastores)
+	}
+}
+
+func TestControllerGetVolume(t *testing.T) {
+	ct := getControllerTest(t)
+
+	params := make(map[string]string)
+	if v := os.Getenv("VSPHERE_DATASTORE_URL"); v != "" {
+		params[common.AttributeDatastoreURL] = v
+	}
+	capabilities := []*csi.VolumeCapability{
+		{
+			AccessMode: &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			},
+		},
+	}
+	reqCreate := &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters:         params,
+		VolumeCapabilities: capabilities,
+	}
+	respCreate, err := ct.controller.CreateVolume(ctx, reqCreate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	volID := respCreate.Volume.VolumeId
+	defer func() {
+		_, err := ct.controller.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	resp, err := ct.controller.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: volID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetVolume().GetVolumeId() != volID {
+		t.Fatalf("expected volume ID %q, got %q", volID, resp.GetVolume().GetVolumeId())
+	}
+	if resp.GetVolume().GetCapacityBytes() != 1*common.GbInBytes {
+		t.Fatalf("expected capacity %d, got %d", 1*common.GbInBytes, resp.GetVolume().GetCapacityBytes())
+	}
+	condition := resp.GetStatus().GetVolumeCondition()
+	if condition == nil || condition.GetAbnormal() {
+		t.Fatalf("expected volume condition to be normal, got %+v", condition)
+	}
+
+	// A volume unknown to CNS is reported as not found.
+	_, err = ct.controller.ControllerGetVolume(ctx,
+		&csi.ControllerGetVolumeRequest{VolumeId: uuid.New().String()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound error for unknown volume, got %v", err)
+	}
+
+	// A request without volume ID is rejected.
+	_, err = ct.controller.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument error for empty volume ID, got %v", err)
+	}
+}
+
+func TestControllerModifyVolume(t *testing.T) {
+	ct := getControllerTest(t)
+
+	// PBM simulator defaults.
+	storagePolicyName := "vSAN Default Storage Policy"
+	if v := os.Getenv("VSPHERE_STORAGE_POLICY_NAME"); v != "" {
+		storagePolicyName = v
+	}
+	params := make(map[string]string)
+	if v := os.Getenv("VSPHERE_DATASTORE_URL"); v != "" {
+		params[common.AttributeDatastoreURL] = v
+	}
+	params[common.AttributeStoragePolicyName] = storagePolicyName
+	reqCreate := &csi.CreateVolumeRequest{
+		Name: testVolumeName + "-" + uuid.New().String(),
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: 1 * common.GbInBytes,
+		},
+		Parameters: params,
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+				},
+			},
+		},
+	}
+	respCreate, err := ct.controller.CreateVolume(ctx, reqCreate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	volID := respCreate.Volume.VolumeId
+	defer func() {
+		_, err := ct.controller.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: volID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Modifying the volume to the storage policy it already has is a no-op.
+	_, err = ct.controller.ControllerModifyVolume(ctx, &csi.ControllerModifyVolumeRequest{
+		VolumeId:          volID,
+		MutableParameters: map[string]string{common.AttributeStoragePolicyName: storagePolicyName},
+	})
+	if err != nil {
+		t.Fatalf("expected no error when modifying volume to its current storage policy, got %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		volumeID          string
+		mutableParameters map[string]string
+	}{
+		{
+			name:              "EmptyVolumeID",
+			mutableParameters: map[string]string{common.AttributeStoragePolicyName: storagePolicyName},
+		},
+		{
+			name:     "NoMutableParameters",
+			volumeID: volID,
+		},
+		{
+			name:              "UnsupportedMutableParameter",
+			volumeID:          volID,
+			mutableParameters: map[string]string{common.AttributeDatastoreURL: "ds:///vmfs/volumes/vsan:1/"},
+		},
+		{
+			name:              "UnknownStoragePolicy",
+			volumeID:          volID,
+			mutableParameters: map[string]string{common.AttributeStoragePolicyName: "nonexistent-policy"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ct.controller.ControllerModifyVolume(ctx, &csi.ControllerModifyVolumeRequest{
+				VolumeId:          test.volumeID,
+				MutableParameters: test.mutableParameters,
+			})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument error, got %v", err)
+			}
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -815,3 +816,93 @@ func setupRealTagsInVCSim(ctx context.Context, vcenter *cnsvsphere.VirtualCenter
 	log.Infof("Successfully set up real tags in vcsim")
 	return nil
 }
+
+// TestCreateVolumeWithPVCRequestedTopology verifies that block volumes are
+// provisioned in the zone of the requested topology annotation of their PVC.
+func TestCreateVolumeWithPVCRequestedTopology(t *testing.T) {
+	ct := getControllerTestWithTopology(t)
+	getPVCOriginal := getPVC
+	defer func() {
+		getPVC = getPVCOriginal
+	}()
+	zoneKey := "topology.csi.vmware.com/k8s-zone"
+	var requestedZone string
+	getPVC = func(ctx context.Context, namespace, name string) (*v1.PersistentVolumeClaim, error) {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					common.AnnGuestClusterRequestedTopology: `[{"` + zoneKey + `":"` + requestedZone + `"}]`,
+				},
+			},
+		}, nil
+	}
+	newRequest := func(requisiteZones ...string) *csi.CreateVolumeRequest {
+		req := &csi.CreateVolumeRequest{
+			Name: testVolumeName + "-" + uuid.New().String(),
+			CapacityRange: &csi.CapacityRange{
+				RequiredBytes: 1 * common.GbInBytes,
+			},
+			Parameters: map[string]string{
+				common.AttributePvcName:      "pvc",
+				common.AttributePvcNamespace: "zonal",
+			},
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+					},
+				},
+			},
+		}
+		if len(requisiteZones) > 0 {
+			req.AccessibilityRequirements = &csi.TopologyRequirement{}
+			for _, zone := range requisiteZones {
+				req.AccessibilityRequirements.Requisite = append(req.AccessibilityRequirements.Requisite,
+					&csi.Topology{Segments: map[string]string{zoneKey: zone}})
+			}
+		}
+		return req
+	}
+
+	tests := []struct {
+		requestedZone  string
+		requisiteZones []string
+	}{
+		{requestedZone: "zone-1"},
+		{requestedZone: "zone-2"},
+		{requestedZone: "zone-2", requisiteZones: []string{"zone-1", "zone-2"}},
+	}
+	for _, test := range tests {
+		requestedZone = test.requestedZone
+		respCreate, err := ct.controller.CreateVolume(ctxtopology, newRequest(test.requisiteZones...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The datastore of the volume may be shared by several zones, but it
+		// must be accessible from the requested one.
+		accessibleTopology := respCreate.Volume.AccessibleTopology
+		accessible := false
+		for _, topology := range accessibleTopology {
+			if topology.Segments[zoneKey] == requestedZone {
+				accessible = true
+			}
+		}
+		if !accessible {
+			t.Fatalf("volume requested in %s is accessible from %+v", requestedZone, accessibleTopology)
+		}
+		_, err = ct.controller.DeleteVolume(ctxtopology,
+			&csi.DeleteVolumeRequest{VolumeId: respCreate.Volume.VolumeId})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The requested zone is not allowed by the accessibility requirements.
+	requestedZone = "zone-2"
+	_, err := ct.controller.CreateVolume(ctxtopology, newRequest("zone-1"))
+	if err == nil {
+		t.Fatal("CreateVolume should fail as the requested zone is not in the accessibility requirements")
+	}
+}
@@ -91,6 +91,9 @@ var (
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
 		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	}
 	// volumeInfoService holds the pointer to VolumeInfo service instance
 	// This will hold mapping for VolumeID to Storage policy info for PodVMOnStretchedSupervisor deployments
@@ -171,6 +174,16 @@ func New() csitypes.CnsController {
 	return &controller{}
 }
 
+// opTarget returns the optional metric labels of the operations run by the
+// controller.
+func (c *controller) opTarget(volumeID string) prometheus.OpTarget {
+	target := cnsvolume.GetVolumeOpTarget(volumeID)
+	if c.manager != nil && c.manager.VcenterConfig != nil {
+		target.VC = c.manager.VcenterConfig.Host
+	}
+	return target
+}
+
 // Init is initializing controller struct.
 func (c *controller) Init(config *cnsconfig.Config, version string) error {
 	ctx, log := logger.GetNewContextWithLogger()
@@ -405,6 +418,9 @@ func (c *controller) Init(config *cnsconfig.Config, version string) error {
 
 	go cnsvolume.ClearTaskInfoObjects()
 	go cnsvolume.ClearInvalidTasksFromListView(false)
+	if err := common.InitVCEventRecorder(ctx, "vsphere-csi-controller"); err != nil {
+		log.Warnf("failed to initialize event recorder for vCenter events. err=%v", err)
+	}
 	cfgPath := cnsconfig.GetConfigPath(ctx)
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -554,9 +570,7 @@ func (c *controller) ReloadConfiguration(reconnectToVCFromNewConfig bool) error
 	if newVCConfig != nil {
 		newVCConfig.ReloadVCConfigForNewClient = true
 		var vcenter *cnsvsphere.VirtualCenter
-		if c.manager.VcenterConfig.Host != newVCConfig.Host ||
-			c.manager.VcenterConfig.Username != newVCConfig.Username ||
-			c.manager.VcenterConfig.Password != newVCConfig.Password || reconnectToVCFromNewConfig {
+		if c.manager.VcenterConfig.Host != newVCConfig.Host || reconnectToVCFromNewConfig {
 
 			// Verify if new configuration has valid credentials by connecting to
 			// vCenter. Proceed only if the connection succeeds, else return error.
@@ -574,13 +588,31 @@ func (c *controller) ReloadConfiguration(reconnectToVCFromNewConfig bool) error
 				return logger.LogNewErrorf(log, "failed to get VirtualCenter. err=%v", err)
 			}
 		} else {
-			// If it's not a VC host or VC credentials update, same singleton
-			// instance can be used and it's Config field can be updated.
+			// If it's not a VC host update, same singleton instance can be used
+			// and it's Config field can be updated. Rotated credentials are
+			// verified by logging in a new session.
 			vcenter, err = cnsvsphere.GetVirtualCenterInstance(ctx, &cnsconfig.ConfigurationInfo{Cfg: cfg}, false)
 			if err != nil {
 				return logger.LogNewErrorf(log, "failed to get VirtualCenter. err=%v", err)
 			}
-			vcenter.Config = newVCConfig
+			credentialProvider, err := cnsvsphere.NewCredentialProvider(newVCConfig)
+			if err == nil {
+				err = vcenter.RotateCredentials(ctx, credentialProvider)
+			}
+			if err != nil {
+				return logger.LogNewErrorf(log, "failed to rotate credentials for VirtualCenter host: %q, Err: %+v",
+					newVCConfig.Host, err)
+			}
+			vcConfigChanged := !cnsvsphere.IsCredentialOnlyChange(vcenter.Config, newVCConfig)
+			vcenter.SetConfig(newVCConfig)
+			if !vcConfigChanged {
+				// The volume and auth managers already use this vCenter instance,
+				// whose session was swapped in place by the credential rotation.
+				c.manager.VcenterConfig = newVCConfig
+				c.manager.CnsConfig = cfg
+				log.Info("Successfully reloaded configuration with rotated vCenter credentials")
+				return nil
+			}
 		}
 		idempotencyHandlingEnabled := commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx,
 			common.CSIVolumeManagerIdempotency)
@@ -2021,6 +2053,12 @@ func (c *controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequ
 	resp, faultType, err := createVolumeInternal()
 	log.Debugf("createVolumeInternal: returns fault %q", faultType)
 
+	var volumeID string
+	if resp != nil && resp.Volume != nil {
+		volumeID = resp.Volume.VolumeId
+	}
+	target := c.opTarget(volumeID)
+	target.StoragePolicy = common.GetStoragePolicyFromParams(req.Parameters)
 	if err != nil {
 		if csifault.IsNonStorageFault(faultType) {
 			faultType = csifault.AddCsiNonStoragePrefix(ctx, faultType)
@@ -2028,11 +2066,13 @@ func (c *controller) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequ
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusCreateVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume created successfully. Volume Handle: %q, PV Name: %q", resp.Volume.VolumeId, req.Name)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2043,6 +2083,7 @@ func (c *controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequ
 	*csi.DeleteVolumeResponse, error) {
 
 	start := time.Now()
+	target := c.opTarget(req.VolumeId)
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
@@ -2109,11 +2150,13 @@ func (c *controller) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequ
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusDeleteVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q deleted successfully.", req.VolumeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2134,6 +2177,8 @@ func convertCnsVolumeType(ctx context.Context, cnsVolumeType string) string {
 func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (
 	*csi.ControllerPublishVolumeResponse, error) {
 	start := time.Now()
+	target := c.opTarget(req.VolumeId)
+	defer prometheus.TrackAttachQueueDepth(req.GetNodeId(), prometheus.PrometheusAttachVolumeOpType)()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
@@ -2303,11 +2348,13 @@ func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.Contr
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusAttachVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusAttachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusAttachVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q attached successfully.", req.VolumeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusAttachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusAttachVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2318,6 +2365,8 @@ func (c *controller) ControllerPublishVolume(ctx context.Context, req *csi.Contr
 func (c *controller) ControllerUnpublishVolume(ctx context.Context, req *csi.ControllerUnpublishVolumeRequest) (
 	*csi.ControllerUnpublishVolumeResponse, error) {
 	start := time.Now()
+	target := c.opTarget(req.VolumeId)
+	defer prometheus.TrackAttachQueueDepth(req.GetNodeId(), prometheus.PrometheusDetachVolumeOpType)()
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
@@ -2568,11 +2617,13 @@ func (c *controller) ControllerUnpublishVolume(ctx context.Context, req *csi.Con
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusDetachVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDetachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDetachVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q detached successfully.", req.VolumeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDetachVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusDetachVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2717,10 +2768,12 @@ func (c *controller) ListVolumes(ctx context.Context, req *csi.ListVolumesReques
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusListVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(
+			volumeType, prometheus.PrometheusListVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(
+			volumeType, prometheus.PrometheusListVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -2935,11 +2988,13 @@ func (c *controller) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshot
 	start := time.Now()
 	resp, err := createSnapshotInternal()
 	if err != nil {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget(req.GetSourceVolumeId())).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateSnapshotOpType,
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Snapshot for volume %q created successfully.", req.GetSourceVolumeId())
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusCreateSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget(req.GetSourceVolumeId())).WithLabelValues(
+			volumeType, prometheus.PrometheusCreateSnapshotOpType,
 			prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -3012,11 +3067,13 @@ func (c *controller) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshot
 	}
 	resp, err := deleteSnapshotInternal()
 	if err != nil {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteSnapshotOpType,
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Snapshot %q deleted successfully.", req.SnapshotId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusDeleteSnapshotOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(
+			volumeType, prometheus.PrometheusDeleteSnapshotOpType,
 			prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	}
 
@@ -3064,10 +3121,12 @@ func (c *controller) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRe
 	start := time.Now()
 	resp, err := listSnapshotsInternal()
 	if err != nil {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListSnapshotsOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(
+			volumeType, prometheus.PrometheusListSnapshotsOpType,
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 	} else {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusListSnapshotsOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(
+			volumeType, prometheus.PrometheusListSnapshotsOpType,
 			prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	}
 	return resp, err
@@ -3077,6 +3136,7 @@ func (c *controller) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRe
 func (c *controller) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (
 	*csi.ControllerExpandVolumeResponse, error) {
 	start := time.Now()
+	target := c.opTarget(req.VolumeId)
 	ctx = logger.NewContextWithLogger(ctx)
 	log := logger.GetLogger(ctx)
 	volumeType := prometheus.PrometheusUnknownVolumeType
@@ -3209,19 +3269,87 @@ func (c *controller) ControllerExpandVolume(ctx context.Context, req *csi.Contro
 		log.Errorf("Operation failed, reporting failure status to Prometheus."+
 			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
 			prometheus.PrometheusExpandVolumeOpType, volumeType, faultType)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusExpandVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusExpandVolumeOpType,
 			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
 	} else {
 		log.Infof("Volume %q expanded successfully.", req.VolumeId)
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, prometheus.PrometheusExpandVolumeOpType,
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusExpandVolumeOpType,
 			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
 	}
 	return resp, err
 }
 
+// ControllerGetVolume returns the capacity, published nodes and condition of
+// the given volume. The condition is derived from the health status CNS
+// reports for the volume.
 func (c *controller) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (
 	*csi.ControllerGetVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+	start := time.Now()
+	target := c.opTarget(req.VolumeId)
+	ctx = logger.NewContextWithLogger(ctx)
+	log := logger.GetLogger(ctx)
+	volumeType := prometheus.PrometheusUnknownVolumeType
+
+	controllerGetVolumeInternal := func() (*csi.ControllerGetVolumeResponse, string, error) {
+		log.Infof("ControllerGetVolume: called with args %+v", req)
+		if req.GetVolumeId() == "" {
+			return nil, csifault.CSIInvalidArgumentFault, logger.LogNewErrorCode(log, codes.InvalidArgument,
+				"volume ID must be provided")
+		}
+		if common.IsFVSVolumeHandle(req.GetVolumeId()) {
+			// Health of vSAN file volume service volumes is tracked through
+			// FileVolume CRs and not CNS.
+			volumeType = prometheus.PrometheusFileVolumeType
+			return nil, csifault.CSIUnimplementedFault, logger.LogNewErrorCodef(log, codes.Unimplemented,
+				"ControllerGetVolume is not supported for vSAN file volume service volume %q", req.GetVolumeId())
+		}
+		cnsVolume, faultType, err := common.QueryVolumeWithHealthStatusUtil(ctx, c.manager.VolumeManager,
+			req.GetVolumeId())
+		if err != nil {
+			return nil, faultType, err
+		}
+		volumeType = convertCnsVolumeType(ctx, cnsVolume.VolumeType)
+
+		volume := &csi.Volume{
+			VolumeId: req.GetVolumeId(),
+		}
+		if cnsVolume.BackingObjectDetails != nil {
+			volume.CapacityBytes = cnsVolume.BackingObjectDetails.GetCnsBackingObjectDetails().CapacityInMb *
+				common.MbInBytes
+		}
+		publishedNodeIDs, err := getPublishedNodeIDs(ctx, commonco.ContainerOrchestratorUtility.GetNodesForVolumes(
+			ctx, []string{req.GetVolumeId()})[req.GetVolumeId()])
+		if err != nil {
+			return nil, csifault.CSIInternalFault, err
+		}
+		return &csi.ControllerGetVolumeResponse{
+			Volume: volume,
+			Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+				PublishedNodeIds: publishedNodeIDs,
+				VolumeCondition:  common.GetVolumeCondition(ctx, req.GetVolumeId(), cnsVolume.HealthStatus),
+			},
+		}, "", nil
+	}
+
+	resp, faultType, err := controllerGetVolumeInternal()
+	if err != nil {
+		if csifault.IsNonStorageFault(faultType) {
+			faultType = csifault.AddCsiNonStoragePrefix(ctx, faultType)
+		}
+		log.Errorf("Operation failed, reporting failure status to Prometheus."+
+			" Operation Type: %q, Volume Type: %q, Fault Type: %q",
+			prometheus.PrometheusGetVolumeOpType, volumeType, faultType)
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusGetVolumeOpType,
+			prometheus.PrometheusFailStatus, faultType).Observe(time.Since(start).Seconds())
+	} else {
+		prometheus.CsiControlOpsHistVec.ForTarget(target).WithLabelValues(
+			volumeType, prometheus.PrometheusGetVolumeOpType,
+			prometheus.PrometheusPassStatus, faultType).Observe(time.Since(start).Seconds())
+	}
+	return resp, err
 }
 
 // checkMigrationTerminalConditions classifies MigrationConditions into a terminal outcome.
@@ -215,11 +215,11 @@ func (c *controller) GetMetadataAllocated(req *csi.GetMetadataAllocatedRequest,
 
 	err := getMetadataAllocatedInternal()
 	if err != nil {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, "GetMetadataAllocated",
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(volumeType, "GetMetadataAllocated",
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 		return err
 	}
-	prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, "GetMetadataAllocated",
+	prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(volumeType, "GetMetadataAllocated",
 		prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	return nil
 }
@@ -398,11 +398,11 @@ func (c *controller) GetMetadataDelta(req *csi.GetMetadataDeltaRequest,
 
 	err := getMetadataDeltaInternal()
 	if err != nil {
-		prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, "GetMetadataDelta",
+		prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(volumeType, "GetMetadataDelta",
 			prometheus.PrometheusFailStatus, "NotComputed").Observe(time.Since(start).Seconds())
 		return err
 	}
-	prometheus.CsiControlOpsHistVec.WithLabelValues(volumeType, "GetMetadataDelta",
+	prometheus.CsiControlOpsHistVec.ForTarget(c.opTarget("")).WithLabelValues(volumeType, "GetMetadataDelta",
 		prometheus.PrometheusPassStatus, "").Observe(time.Since(start).Seconds())
 	return nil
 }
@@ -48,6 +48,7 @@ import (
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
 	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/syncer/k8scloudoperator"
 )
@@ -823,11 +824,12 @@ func validateVolumeCapabilitiesInWcp(ctx context.Context, volCaps []*csi.VolumeC
 			}
 		}
 
-		if volCap.AccessMode.Mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER {
-			// For ReadWriteOnce access mode we only support following filesystems:
-			// ext3, ext4, xfs for Linux and ntfs for Windows.
+		if common.IsSingleNodeWriterAccessMode(volCap.AccessMode.Mode) {
+			// For ReadWriteOnce and ReadWriteOncePod access modes we only support following filesystems:
+			// ext3, ext4, xfs, btrfs for Linux and ntfs for Windows.
 			if volCap.GetMount() != nil && !(volCap.GetMount().FsType == common.Ext4FsType ||
 				volCap.GetMount().FsType == common.Ext3FsType || volCap.GetMount().FsType == common.XFSType ||
+				volCap.GetMount().FsType == common.BtrfsFsType ||
 				strings.ToLower(volCap.GetMount().FsType) == common.NTFSFsType || volCap.GetMount().FsType == "") {
 				return fmt.Errorf("fstype %s not supported for ReadWriteOnce volume creation",
 					volCap.GetMount().FsType)
@@ -977,6 +979,41 @@ func getPodVMUUID(ctx context.Context, volumeID, nodeName string) (string, error
 		"failed to find pod for pvc %q in the namespace %q", pvcName, pvcNamespace)
 }
 
+// getPublishedNodeIDs returns the IDs of the given nodes as reported by the
+// NodeGetInfo call of the vSphere CSI node plugin, i.e. the node UUIDs, as
+// registered in their CSINode objects. The name of a node is returned for a
+// node on which the vSphere CSI driver is not registered, as it is the ID of
+// the node in the ControllerPublishVolume requests for it.
+func getPublishedNodeIDs(ctx context.Context, nodeNames []string) ([]string, error) {
+	log := logger.GetLogger(ctx)
+	if len(nodeNames) == 0 {
+		return nil, nil
+	}
+	c, err := newK8sClient(ctx)
+	if err != nil {
+		return nil, logger.LogNewErrorCode(log, codes.Internal, "failed to create kubernetes client")
+	}
+	nodeIDs := make([]string, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		nodeID := nodeName
+		csiNode, err := c.StorageV1().CSINodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, logger.LogNewErrorCodef(log, codes.Internal,
+				"failed to get CSINode %q. Error: %v", nodeName, err)
+		}
+		if err == nil {
+			for _, driver := range csiNode.Spec.Drivers {
+				if driver.Name == csitypes.Name && driver.NodeID != "" {
+					nodeID = driver.NodeID
+					break
+				}
+			}
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs, nil
+}
+
 // GetAccessibleTopologies returns a list of CSI topology segments based on the clusters where the volume is accessible.
 func GetAccessibleTopologies(volumeClusters []vimtypes.ManagedObjectReference,
 	azClusterMap map[string][]string) []*csi.Topology {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	vimtypes "github.com/vmware/govmomi/vim25/types"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -16,6 +17,7 @@ import (
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/unittestcommon"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
 )
 
 func TestGetPodVMUUID(t *testing.T) {
@@ -453,3 +455,37 @@ func TestGetHostLocalAccessibleTopology(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetPublishedNodeIDs(t *testing.T) {
+	newK8sClientOriginal := newK8sClient
+	defer func() {
+		newK8sClient = newK8sClientOriginal
+	}()
+	newK8sClient = func(ctx context.Context) (kubernetes.Interface, error) {
+		return fake.NewClientset(
+			&storagev1.CSINode{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Spec: storagev1.CSINodeSpec{
+					Drivers: []storagev1.CSINodeDriver{
+						{Name: "other.csi.driver", NodeID: "other-id"},
+						{Name: csitypes.Name, NodeID: "4219b4f9-5c3c-4a1d-a9b5-2f8e7b9c1d2e"},
+					},
+				},
+			},
+			&storagev1.CSINode{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+				Spec: storagev1.CSINodeSpec{
+					Drivers: []storagev1.CSINodeDriver{{Name: "other.csi.driver", NodeID: "other-id"}},
+				},
+			},
+		), nil
+	}
+
+	nodeIDs, err := getPublishedNodeIDs(context.Background(), []string{"node-1", "node-2", "node-3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4219b4f9-5c3c-4a1d-a9b5-2f8e7b9c1d2e", "node-2", "node-3"}, nodeIDs)
+
+	nodeIDs, err = getPublishedNodeIDs(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, nodeIDs)
+}
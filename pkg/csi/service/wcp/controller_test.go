@@ -1808,11 +1808,18 @@ func TestControllerGetVolume(t *testing.T) {
 			VolumeId: respCreate.Volume.VolumeId,
 		}
 
-		_, err := ct.controller.ControllerGetVolume(ctx, req)
-		// ControllerGetVolume returns Unimplemented in WCP controller
-		// This is expected behavior, so we just verify the method can be called
+		resp, err := ct.controller.ControllerGetVolume(ctx, req)
 		if err != nil {
-			t.Logf("ControllerGetVolume failed as expected (Unimplemented): %v", err)
+			t.Fatalf("ControllerGetVolume failed: %v", err)
+		}
+		if resp.GetVolume().GetCapacityBytes() != 1*common.GbInBytes {
+			t.Errorf("expected capacity %d, got %d", 1*common.GbInBytes, resp.GetVolume().GetCapacityBytes())
+		}
+		if resp.GetStatus().GetVolumeCondition() == nil {
+			t.Fatal("expected volume condition to be set")
+		}
+		if resp.GetStatus().GetVolumeCondition().GetAbnormal() {
+			t.Errorf("expected volume condition to be normal, got %+v", resp.GetStatus().GetVolumeCondition())
 		}
 	})
 
@@ -1822,10 +1829,8 @@ func TestControllerGetVolume(t *testing.T) {
 		}
 
 		_, err := ct.controller.ControllerGetVolume(ctx, req)
-		// ControllerGetVolume returns Unimplemented in WCP controller
-		// This is expected behavior, so we just verify the method can be called
-		if err != nil {
-			t.Logf("ControllerGetVolume failed as expected (Unimplemented): %v", err)
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("expected NotFound error, got %v", err)
 		}
 	})
 
@@ -1835,10 +1840,8 @@ func TestControllerGetVolume(t *testing.T) {
 		}
 
 		_, err := ct.controller.ControllerGetVolume(ctx, req)
-		// ControllerGetVolume returns Unimplemented in WCP controller
-		// This is expected behavior, so we just verify the method can be called
-		if err != nil {
-			t.Logf("ControllerGetVolume failed as expected (Unimplemented): %v", err)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument error, got %v", err)
 		}
 	})
 }
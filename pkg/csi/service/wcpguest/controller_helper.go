@@ -209,7 +209,11 @@ func checkPVCCondition(ctx context.Context, pvc *v1.PersistentVolumeClaim,
 // getAccessMode returns the PersistentVolumeAccessMode for the PVC Spec given VolumeCapability_AccessMode
 func getAccessMode(accessMode csi.VolumeCapability_AccessMode_Mode) v1.PersistentVolumeAccessMode {
 	switch accessMode {
-	case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:
+	case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:
+		// ReadWriteOncePod is enforced by the guest cluster. The supervisor
+		// PVC is attached to the guest node VM, so ReadWriteOnce is used there.
 		return v1.ReadWriteOnce
 	case csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER:
 		return v1.ReadWriteMany
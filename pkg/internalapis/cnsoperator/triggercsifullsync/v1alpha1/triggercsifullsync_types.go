@@ -24,6 +24,22 @@ import (
 // created to trigger full sync on demand.
 const TriggerCsiFullSyncCRName = "csifullsync"
 
+const (
+	// VCenterAvailableCondition is the condition reporting whether the
+	// vCenters are available to the syncer. It is False if the connections of
+	// the syncer to any vCenter are failing, with the reason
+	// VCenterDegradedReason or VCenterDownReason. The controller reports its
+	// own connections as events on its pod.
+	VCenterAvailableCondition = "VCenterAvailable"
+	// VCenterHealthyReason means that all the vCenters are healthy.
+	VCenterHealthyReason = "Healthy"
+	// VCenterDegradedReason means that the last connections to a vCenter
+	// failed, but its circuit breaker is not open yet.
+	VCenterDegradedReason = "Degraded"
+	// VCenterDownReason means that the circuit breaker of a vCenter is open.
+	VCenterDownReason = "Down"
+)
+
 // TriggerCsiFullSyncSpec is the spec for TriggerCsiFullSync
 type TriggerCsiFullSyncSpec struct {
 	// TriggerSyncID gives an option to trigger full sync on demand.
@@ -58,6 +74,11 @@ type TriggerCsiFullSyncStatus struct {
 	// The last error encountered during CSI full sync operation, if any.
 	// Previous error will be cleared when a new full sync is in progress.
 	Error string `json:"error,omitempty"`
+
+	// Conditions holds the conditions of the vCenters the CSI driver is
+	// connected to, such as VCenterAvailableCondition.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
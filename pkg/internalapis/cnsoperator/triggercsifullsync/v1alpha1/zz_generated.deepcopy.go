@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -28,7 +29,7 @@ func (in *TriggerCsiFullSync) DeepCopyInto(out *TriggerCsiFullSync) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -102,6 +103,29 @@ func (in *TriggerCsiFullSyncSpec) DeepCopy() *TriggerCsiFullSyncSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TriggerCsiFullSyncStatus) DeepCopyInto(out *TriggerCsiFullSyncStatus) {
 	*out = *in
+	if in.LastSuccessfulStartTimeStamp != nil {
+		in, out := &in.LastSuccessfulStartTimeStamp, &out.LastSuccessfulStartTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulEndTimeStamp != nil {
+		in, out := &in.LastSuccessfulEndTimeStamp, &out.LastSuccessfulEndTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRunStartTimeStamp != nil {
+		in, out := &in.LastRunStartTimeStamp, &out.LastRunStartTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRunEndTimeStamp != nil {
+		in, out := &in.LastRunEndTimeStamp, &out.LastRunEndTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
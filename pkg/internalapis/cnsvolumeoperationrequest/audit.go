@@ -0,0 +1,340 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsvolumeoperationrequest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+)
+
+const (
+	// EnvOperationAuditLogPath represents the environment variable which stores
+	// the path of the file to which volume operation transitions are audited.
+	// Auditing is disabled if the variable is not set. The file is local to the
+	// container, so records are lost when the pod restarts unless the path is on
+	// a volume which outlives the pod, e.g. a hostPath or persistent volume.
+	EnvOperationAuditLogPath = "CSI_OPERATION_AUDIT_LOG_PATH"
+	// EnvOperationAuditLogMaxSizeInMB represents the environment variable which
+	// stores the size in megabytes after which the audit log file is rotated.
+	EnvOperationAuditLogMaxSizeInMB = "CSI_OPERATION_AUDIT_LOG_MAX_SIZE_MB"
+	// EnvOperationAuditLogMaxBackups represents the environment variable which
+	// stores the number of rotated audit log files to retain.
+	EnvOperationAuditLogMaxBackups = "CSI_OPERATION_AUDIT_LOG_MAX_BACKUPS"
+
+	// defaultAuditLogMaxSizeInMB is the default size of the audit log file
+	// after which it is rotated.
+	defaultAuditLogMaxSizeInMB = 100
+	// defaultAuditLogMaxBackups is the default number of rotated audit log
+	// files to retain.
+	defaultAuditLogMaxBackups = 5
+)
+
+// AuditRecord is a single volume operation transition written to the audit log.
+type AuditRecord struct {
+	// Timestamp is the time at which the transition was recorded.
+	Timestamp time.Time `json:"timestamp"`
+	// Name is the name of the CnsVolumeOperationRequest instance.
+	Name          string `json:"name"`
+	VolumeID      string `json:"volumeID,omitempty"`
+	SnapshotID    string `json:"snapshotID,omitempty"`
+	TaskID        string `json:"taskID,omitempty"`
+	OpID          string `json:"opID,omitempty"`
+	VCenterServer string `json:"vCenterServer,omitempty"`
+	TaskStatus    string `json:"taskStatus"`
+	Error         string `json:"error,omitempty"`
+	// DurationSeconds is the time elapsed since the task was invoked. It is
+	// set only for transitions out of the InProgress state.
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+}
+
+// AuditSink receives every volume operation transition persisted by the
+// VolumeOperationRequest interface. Transitions are recorded only after they
+// are successfully written to the CnsVolumeOperationRequest instance.
+type AuditSink interface {
+	// Record writes the given record to the sink.
+	Record(ctx context.Context, record *AuditRecord) error
+}
+
+// AuditFilter selects the audit records returned by QueryAuditRecords.
+// A record is selected if it matches any of the non-empty fields. An empty
+// filter selects all records.
+type AuditFilter struct {
+	// VolumeID matches records of the volume, including operations whose
+	// instance name is derived from the volume ID.
+	VolumeID string
+	// Name matches records with the given CnsVolumeOperationRequest instance
+	// name, e.g. the PV name for CreateVolume operations.
+	Name string
+}
+
+// fileAuditSink implements the AuditSink interface by appending records as
+// JSON lines to a local file which is rotated once it reaches maxSizeBytes.
+type fileAuditSink struct {
+	lock         sync.Mutex
+	path         string
+	maxSizeBytes int64
+	maxBackups   int
+	file         *os.File
+	size         int64
+}
+
+var (
+	auditSinkInstance AuditSink
+	auditSinkInitLock = &sync.Mutex{}
+)
+
+// NewFileAuditSink returns an AuditSink which writes records to the file at
+// path. The file is rotated to path.1, path.2, ... once it is larger than
+// maxSizeInMB, retaining at most maxBackups rotated files.
+func NewFileAuditSink(path string, maxSizeInMB, maxBackups int) (AuditSink, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("audit log path cannot be empty")
+	}
+	if maxSizeInMB <= 0 {
+		maxSizeInMB = defaultAuditLogMaxSizeInMB
+	}
+	if maxBackups < 0 {
+		maxBackups = defaultAuditLogMaxBackups
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for audit log %q: %v", path, err)
+	}
+	sink := &fileAuditSink{
+		path:         path,
+		maxSizeBytes: int64(maxSizeInMB) * 1024 * 1024,
+		maxBackups:   maxBackups,
+	}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// Record appends the record as a JSON line to the audit log file.
+func (s *fileAuditSink) Record(ctx context.Context, record *AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.size > 0 && s.size+int64(len(line)) > s.maxSizeBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// open opens the audit log file for appending.
+func (s *fileAuditSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %q: %v", s.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat audit log %q: %v", s.path, err)
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// rotate shifts the rotated audit log files by one, dropping the oldest, and
+// starts a new audit log file.
+func (s *fileAuditSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log %q: %v", s.path, err)
+	}
+	if s.maxBackups == 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(auditLogBackupPath(s.path, i), auditLogBackupPath(s.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, auditLogBackupPath(s.path, 1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.open()
+}
+
+func auditLogBackupPath(path string, index int) string {
+	return path + "." + strconv.Itoa(index)
+}
+
+// initAuditSink initializes the audit sink from the environment. Auditing is
+// opt-in and is enabled only when EnvOperationAuditLogPath is set.
+func initAuditSink(ctx context.Context) {
+	log := logger.GetLogger(ctx)
+	auditSinkInitLock.Lock()
+	defer auditSinkInitLock.Unlock()
+	if auditSinkInstance != nil {
+		return
+	}
+	path := strings.TrimSpace(os.Getenv(EnvOperationAuditLogPath))
+	if path == "" {
+		return
+	}
+	maxSizeInMB := getIntFromEnv(ctx, EnvOperationAuditLogMaxSizeInMB, defaultAuditLogMaxSizeInMB)
+	maxBackups := getIntFromEnv(ctx, EnvOperationAuditLogMaxBackups, defaultAuditLogMaxBackups)
+	sink, err := NewFileAuditSink(path, maxSizeInMB, maxBackups)
+	if err != nil {
+		log.Errorf("failed to initialize volume operation audit log. Auditing is disabled. Error: %v", err)
+		return
+	}
+	log.Infof("Auditing volume operations to %q with max size %d MB and %d backups",
+		path, maxSizeInMB, maxBackups)
+	auditSinkInstance = sink
+}
+
+// SetAuditSink sets the sink to which volume operation transitions are audited.
+// Passing nil disables auditing.
+func SetAuditSink(sink AuditSink) {
+	auditSinkInitLock.Lock()
+	defer auditSinkInitLock.Unlock()
+	auditSinkInstance = sink
+}
+
+// recordAudit writes the operation transition to the audit sink, if one is
+// configured. Failures are logged and never fail the operation.
+func recordAudit(ctx context.Context, details *VolumeOperationRequestDetails) {
+	auditSinkInitLock.Lock()
+	sink := auditSinkInstance
+	auditSinkInitLock.Unlock()
+	if sink == nil || details == nil || details.OperationDetails == nil {
+		return
+	}
+	log := logger.GetLogger(ctx)
+	record := &AuditRecord{
+		Timestamp:     time.Now().UTC(),
+		Name:          details.Name,
+		VolumeID:      details.VolumeID,
+		SnapshotID:    details.SnapshotID,
+		TaskID:        details.OperationDetails.TaskID,
+		OpID:          details.OperationDetails.OpID,
+		VCenterServer: details.OperationDetails.VCenterServer,
+		TaskStatus:    details.OperationDetails.TaskStatus,
+		Error:         details.OperationDetails.Error,
+	}
+	if details.OperationDetails.TaskStatus != TaskInvocationStatusInProgress &&
+		!details.OperationDetails.TaskInvocationTimestamp.IsZero() {
+		record.DurationSeconds = record.Timestamp.Sub(details.OperationDetails.TaskInvocationTimestamp.Time).Seconds()
+	}
+	if err := sink.Record(ctx, record); err != nil {
+		log.Warnf("failed to audit operation for CnsVolumeOperationRequest %q. Error: %v", details.Name, err)
+	}
+}
+
+// QueryAuditRecords reads the audit log file at path, including its rotated
+// files, and returns the records matching the filter from oldest to newest.
+func QueryAuditRecords(path string, filter AuditFilter) ([]AuditRecord, error) {
+	var files []string
+	for i := 1; ; i++ {
+		backup := auditLogBackupPath(path, i)
+		if _, err := os.Stat(backup); err != nil {
+			break
+		}
+		files = append([]string{backup}, files...)
+	}
+	files = append(files, path)
+
+	var records []AuditRecord
+	for _, file := range files {
+		fileRecords, err := readAuditRecords(file, filter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// readAuditRecords returns the records in the given audit log file that match
+// the filter. Lines which cannot be parsed are skipped.
+func readAuditRecords(path string, filter AuditFilter) ([]AuditRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if filter.matches(&record) {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func (f AuditFilter) matches(record *AuditRecord) bool {
+	if f.VolumeID == "" && f.Name == "" {
+		return true
+	}
+	if f.VolumeID != "" && (record.VolumeID == f.VolumeID || strings.Contains(record.Name, f.VolumeID)) {
+		return true
+	}
+	return f.Name != "" && record.Name == f.Name
+}
+
+func getIntFromEnv(ctx context.Context, envName string, defaultValue int) int {
+	log := logger.GetLogger(ctx)
+	v := os.Getenv(envName)
+	if v == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(v)
+	if err != nil || value < 0 {
+		log.Warnf("invalid value %q set in env variable %s. Using default value %d", v, envName, defaultValue)
+		return defaultValue
+	}
+	return value
+}
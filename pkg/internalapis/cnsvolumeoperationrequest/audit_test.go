@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsvolumeoperationrequest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestStoreRequestDetailsIsAudited(t *testing.T) {
+	store, ctx := setupTestEnvironment(t, false)
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(logPath, 1, 1)
+	require.NoError(t, err)
+	SetAuditSink(sink)
+	defer SetAuditSink(nil)
+
+	err = store.StoreRequestDetails(ctx, createTestVolumeOperationDetails("pvc-1", "", "", "task-1",
+		TaskInvocationStatusInProgress, "", nil))
+	require.NoError(t, err)
+	err = store.StoreRequestDetails(ctx, createTestVolumeOperationDetails("pvc-1", "vol-1", "", "task-1",
+		TaskInvocationStatusSuccess, "", nil))
+	require.NoError(t, err)
+	err = store.StoreRequestDetails(ctx, createTestVolumeOperationDetails("expand-vol-1-2048", "vol-1", "",
+		"task-2", TaskInvocationStatusError, "expand failed", nil))
+	require.NoError(t, err)
+	err = store.StoreRequestDetails(ctx, createTestVolumeOperationDetails("pvc-2", "vol-2", "", "task-3",
+		TaskInvocationStatusSuccess, "", nil))
+	require.NoError(t, err)
+
+	records, err := QueryAuditRecords(logPath, AuditFilter{VolumeID: "vol-1", Name: "pvc-1"})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, TaskInvocationStatusInProgress, records[0].TaskStatus)
+	assert.Zero(t, records[0].DurationSeconds)
+	assert.Equal(t, TaskInvocationStatusSuccess, records[1].TaskStatus)
+	assert.Equal(t, "vol-1", records[1].VolumeID)
+	assert.Equal(t, "task-2", records[2].TaskID)
+	assert.Equal(t, "expand failed", records[2].Error)
+
+	records, err = QueryAuditRecords(logPath, AuditFilter{})
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+}
+
+func TestStoreRequestDetailsFailureIsNotAudited(t *testing.T) {
+	store, ctx := setupTestEnvironment(t, false)
+	store.k8sclient = interceptor.NewClient(store.k8sclient.(client.WithWatch), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			return errors.New("etcd unavailable")
+		},
+	})
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(logPath, 1, 1)
+	require.NoError(t, err)
+	SetAuditSink(sink)
+	defer SetAuditSink(nil)
+
+	err = store.StoreRequestDetails(ctx, createTestVolumeOperationDetails("pvc-1", "", "", "task-1",
+		TaskInvocationStatusInProgress, "", nil))
+	require.Error(t, err)
+
+	records, err := QueryAuditRecords(logPath, AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestFileAuditSinkRotation(t *testing.T) {
+	ctx := context.Background()
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(logPath, 1, 2)
+	require.NoError(t, err)
+	// Use a small size so that every record rotates the log.
+	sink.(*fileAuditSink).maxSizeBytes = 1
+
+	for _, taskID := range []string{"task-1", "task-2", "task-3", "task-4"} {
+		require.NoError(t, sink.Record(ctx, &AuditRecord{Name: "pvc-1", TaskID: taskID,
+			TaskStatus: TaskInvocationStatusSuccess}))
+	}
+
+	_, err = os.Stat(logPath + ".2")
+	assert.NoError(t, err)
+	_, err = os.Stat(logPath + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// The oldest record is dropped and the others are returned in order.
+	records, err := QueryAuditRecords(logPath, AuditFilter{Name: "pvc-1"})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "task-2", records[0].TaskID)
+	assert.Equal(t, "task-3", records[1].TaskID)
+	assert.Equal(t, "task-4", records[2].TaskID)
+}
@@ -138,6 +138,8 @@ func InitVolumeOperationRequestInterface(ctx context.Context, cleanupInterval in
 		}
 		go operationRequestStoreInstance.cleanupStaleInstances(cleanupInterval)
 	}
+	// Initialize the opt-in audit sink for volume operation transitions.
+	initAuditSink(ctx)
 	// Store PodVMOnStretchedSupervisor FSS value for later use.
 	isPodVMOnStretchSupervisorFSSEnabled = isPodVMOnStretchSupervisorEnabled
 	// Store CSI Transaction Support FSS value for later use.
@@ -260,6 +262,7 @@ func (or *operationRequestStore) StoreRequestDetails(
 				instanceKey.Name,
 				operationDetailsToStore.TaskID,
 			)
+			recordAudit(ctx, operationToStore)
 			return nil
 		}
 		log.Errorf(
@@ -366,6 +369,7 @@ func (or *operationRequestStore) StoreRequestDetails(
 		instanceKey.Name,
 		operationDetailsToStore.TaskID,
 	)
+	recordAudit(ctx, operationToStore)
 	return nil
 }
 
@@ -39,6 +39,9 @@ const (
 	// This status appears when CSI Transaction Support is enabled and the task was never seen to
 	// completion or error, and a retry was initiated.
 	TaskInvocationStatusTrackingAborted = "TrackingAborted"
+	// TaskInvocationStatusTimedOut represents a task that did not complete within the
+	// operation timeout. The task may still be running on vCenter.
+	TaskInvocationStatusTimedOut = "TimedOut"
 )
 
 // VolumeOperationRequestDetails stores details about a single operation
@@ -159,10 +159,7 @@ func (im *InformerManager) AddPVCListener(ctx context.Context, add func(obj inte
 func (im *InformerManager) AddPVListener(ctx context.Context, add func(obj interface{}),
 	update func(oldObj, newObj interface{}), remove func(obj interface{})) error {
 	log := logger.GetLogger(ctx)
-	if im.pvInformer == nil {
-		im.pvInformer = im.informerFactory.Core().V1().PersistentVolumes().Informer()
-	}
-	im.pvSynced = im.pvInformer.HasSynced
+	im.InitPVInformer()
 
 	_, err := im.pvInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    add,
@@ -175,6 +172,16 @@ func (im *InformerManager) AddPVListener(ctx context.Context, add func(obj inter
 	return nil
 }
 
+// InitPVInformer registers the PV shared informer with the factory (no event handlers).
+// Idempotent. Call before Listen when only the PV lister/cache is needed.
+func (im *InformerManager) InitPVInformer() {
+	if im.pvInformer != nil {
+		return
+	}
+	im.pvInformer = im.informerFactory.Core().V1().PersistentVolumes().Informer()
+	im.pvSynced = im.pvInformer.HasSynced
+}
+
 // InitNamespaceInformer registers the cluster Namespace shared informer with the factory (no event handlers).
 // Idempotent. Call before Listen when only the Namespace lister/cache is needed (e.g. WCP FVS).
 func (im *InformerManager) InitNamespaceInformer() {
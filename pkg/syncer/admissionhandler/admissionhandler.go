@@ -235,6 +235,25 @@ func StartWebhookServer(ctx context.Context, enableWebhookClientCertVerification
 			}
 			log.Debugf("webhook config: %v", cfg)
 		}
+		if cfg.StorageClassValidation.VCenterLookup && scParamsLookup == nil {
+			scParamsLookup, err = newVCenterLookup(ctx, cfg.StorageClassValidation.VCenterLookupCacheTTLInSec)
+			if err != nil {
+				log.Errorf("failed to initialize the vCenter lookup of StorageClass parameters. err: %v", err)
+				return err
+			}
+		}
+		if cfg.PVCExpansionValidation.VCenterLookup && pvcExpansionLookup == nil {
+			pvcExpansionLookup, err = newVolumeDatastoreLookup(ctx,
+				cfg.PVCExpansionValidation.VCenterLookupCacheTTLInSec)
+			if err != nil {
+				log.Errorf("failed to initialize the vCenter lookup of PVC expansions. err: %v", err)
+				return err
+			}
+		}
+		if err := validateTopologyInjectionConfig(ctx, cfg.TopologyInjection); err != nil {
+			log.Errorf("invalid topology injection config. err: %v", err)
+			return err
+		}
 		featureGateBlockVolumeSnapshotEnabled = containerOrchestratorUtility.IsFSSEnabled(ctx, common.BlockVolumeSnapshot)
 		featureFileVolumesWithVmServiceEnabled = containerOrchestratorUtility.IsFSSEnabled(ctx,
 			common.FileVolumesWithVmService)
@@ -265,6 +284,7 @@ func StartWebhookServer(ctx context.Context, enableWebhookClientCertVerification
 			// Define http server and server handler.
 			mux := http.NewServeMux()
 			mux.HandleFunc("/validate", validationHandler)
+			mux.HandleFunc("/mutate", validationHandler)
 			server.Handler = mux
 
 			// Start webhook server.
@@ -307,11 +327,12 @@ func restartWebhookServer(ctx context.Context, enableWebhookClientCertVerificati
 }
 
 // validationHandler is the handler for webhook http multiplexer to help
-// validate resources. Depending on the URL validation of AdmissionReview
-// will be redirected to appropriate validation function.
+// validate and mutate resources. Depending on the URL validation or mutation
+// of AdmissionReview will be redirected to appropriate function.
 func validationHandler(w http.ResponseWriter, r *http.Request) {
 	var body []byte
-	ctx, log := logger.GetNewContextWithLogger()
+	ctx := logger.WithSubsystem(logger.NewContextWithLogger(context.Background()), logger.SubsystemAdmission)
+	log := logger.GetLogger(ctx)
 	if r.Body != nil {
 		if data, err := io.ReadAll(r.Body); err == nil {
 			body = data
@@ -360,6 +381,11 @@ func validationHandler(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 			log.Debugf("admissionResponse: %+v", admissionResponse)
+		} else if r.URL.Path == "/mutate" {
+			log.Debugf("request URL path is /mutate")
+			log.Debugf("admissionReview: %+v", ar)
+			admissionResponse = mutateTopology(ctx, ar.Request)
+			log.Debugf("admissionResponse: %+v", admissionResponse)
 		}
 	}
 	admissionReview := admissionv1.AdmissionReview{}
@@ -212,6 +212,7 @@ type CSISupervisorWebhook struct {
 }
 
 func (h *CSISupervisorWebhook) Handle(ctx context.Context, req admission.Request) (resp admission.Response) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemAdmission)
 	log := logger.GetLogger(ctx)
 	log.Debugf("CNS-CSI validation webhook handler called with request: %+v", req)
 	defer log.Debugf("CNS-CSI validation webhook handler completed for the request: %+v", req)
@@ -282,6 +283,7 @@ type CSISupervisorMutationWebhook struct {
 }
 
 func (h *CSISupervisorMutationWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemAdmission)
 	log := logger.GetLogger(ctx)
 	log.Debugf("CNS-CSI mutation webhook handler called with request: %+v", req)
 	defer log.Debugf("CNS-CSI mutation webhook handler completed for the request: %+v", req)
@@ -29,12 +29,24 @@ const (
 	envWebHookConfigPath     = "WEBHOOK_CONFIG_PATH"
 	defaultWebHookConfigPath = "/etc/webhook/webhook.config"
 	defaultWebhookServerPort = "8443"
+	// defaultVCenterLookupCacheTTLInSec is the default time the storage policies
+	// and datastores listed from vCenter are cached for.
+	defaultVCenterLookupCacheTTLInSec = 300
 )
 
 // config holds webhook configuration and FeatureStatesConfig
 type config struct {
 	// WebHookConfig contains the detail about webhook - certfile, keyfile, port etc.
 	WebHookConfig webHookConfig
+	// StorageClassValidation contains the configuration of the validation of
+	// the StorageClass parameters.
+	StorageClassValidation storageClassValidationConfig
+	// PVCExpansionValidation contains the configuration of the validation of
+	// the PVC expansions.
+	PVCExpansionValidation pvcExpansionValidationConfig
+	// TopologyInjection contains the configuration of the injection of the
+	// topology requirements into StorageClasses and PVCs.
+	TopologyInjection topologyInjectionConfig
 }
 
 // webHookConfig holds webhook configuration using which webhook http server will be created
@@ -47,6 +59,59 @@ type webHookConfig struct {
 	Port string `gcfg:"port"`
 }
 
+// storageClassValidationConfig holds the configuration of the validation of
+// the parameters of the StorageClasses of the vSphere CSI driver.
+type storageClassValidationConfig struct {
+	// DenyInvalidParameters denies the creation of StorageClasses with invalid
+	// parameters. StorageClasses with invalid parameters are admitted with
+	// warnings if not set.
+	DenyInvalidParameters bool `gcfg:"deny-invalid-parameters"`
+	// VCenterLookup verifies the storage policy names and datastore URLs of
+	// the StorageClasses exist in vCenter.
+	VCenterLookup bool `gcfg:"vcenter-lookup"`
+	// VCenterLookupCacheTTLInSec is the time the storage policies and
+	// datastores listed from vCenter are cached for.
+	VCenterLookupCacheTTLInSec int `gcfg:"vcenter-lookup-cache-ttl-insec"`
+}
+
+// pvcExpansionValidationConfig holds the configuration of the validation of
+// the expansions of the PVCs of the vSphere CSI driver.
+type pvcExpansionValidationConfig struct {
+	// DenyInvalidExpansion denies the PVC expansions which cannot succeed.
+	// Such expansions are admitted with warnings if not set.
+	DenyInvalidExpansion bool `gcfg:"deny-invalid-expansion"`
+	// VCenterLookup verifies the free space and the maximum virtual disk size
+	// of the datastore of the volume, as computed by the AuthManager every
+	// csi-auth-check-intervalinmin of the vSphere config. This lists all the
+	// datastores of the vCenter servers and checks the privileges of the
+	// vCenter user on them from each webhook replica.
+	VCenterLookup bool `gcfg:"vcenter-lookup"`
+	// VCenterLookupCacheTTLInSec is the time the datastore and provisioning
+	// type of each volume retrieved from vCenter are cached for.
+	VCenterLookupCacheTTLInSec int `gcfg:"vcenter-lookup-cache-ttl-insec"`
+}
+
+// topologyInjectionConfig holds the cluster policy for injecting topology
+// requirements into the StorageClasses and PVCs of the vSphere CSI driver.
+// Nothing is injected if neither Policy nor NamespaceZoneLabel is set.
+type topologyInjectionConfig struct {
+	// Policy is the topology requirement injected into the StorageClasses
+	// created without allowedTopologies. Supported values are
+	// "AllowedTopologies" and "WaitForFirstConsumer".
+	Policy string `gcfg:"policy"`
+	// TopologyCategory is the topology category, as set in topology-categories
+	// of the vSphere config, of the zones.
+	TopologyCategory string `gcfg:"topology-category"`
+	// AllowedZones is a comma separated list of the zones injected as the
+	// allowedTopologies of StorageClasses with the AllowedTopologies policy.
+	AllowedZones string `gcfg:"allowed-zones"`
+	// NamespaceZoneLabel is the namespace label whose value is the default zone
+	// of the PVCs created in the namespace. The zone is honored by the vanilla
+	// CSI controller when the external-provisioner runs with
+	// --extra-create-metadata.
+	NamespaceZoneLabel string `gcfg:"namespace-zone-label"`
+}
+
 // getWebHookConfig returns webhook config
 func getWebHookConfig(ctx context.Context) (*config, error) {
 	log := logger.GetLogger(ctx)
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhandler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+const (
+	// topologyInjectionPolicyAllowedTopologies injects the allowed zones of the
+	// webhook config as the allowedTopologies of StorageClasses.
+	topologyInjectionPolicyAllowedTopologies = "AllowedTopologies"
+	// topologyInjectionPolicyWaitForFirstConsumer injects the
+	// WaitForFirstConsumer volumeBindingMode into StorageClasses, so that the
+	// volumes are provisioned in the zone of the first pod using them.
+	topologyInjectionPolicyWaitForFirstConsumer = "WaitForFirstConsumer"
+)
+
+// validateTopologyInjectionConfig validates the topology injection policy of
+// the webhook config.
+func validateTopologyInjectionConfig(ctx context.Context, injectionCfg topologyInjectionConfig) error {
+	log := logger.GetLogger(ctx)
+	switch injectionCfg.Policy {
+	case "", topologyInjectionPolicyWaitForFirstConsumer:
+	case topologyInjectionPolicyAllowedTopologies:
+		if injectionCfg.TopologyCategory == "" || strings.TrimSpace(injectionCfg.AllowedZones) == "" {
+			return logger.LogNewErrorf(log, "topology-category and allowed-zones are required by the %q "+
+				"topology injection policy", topologyInjectionPolicyAllowedTopologies)
+		}
+	default:
+		return logger.LogNewErrorf(log, "unsupported topology injection policy %q. Supported policies are %q "+
+			"and %q", injectionCfg.Policy, topologyInjectionPolicyAllowedTopologies,
+			topologyInjectionPolicyWaitForFirstConsumer)
+	}
+	if injectionCfg.NamespaceZoneLabel != "" && injectionCfg.TopologyCategory == "" {
+		return logger.LogNewErrorf(log, "topology-category is required by namespace-zone-label")
+	}
+	return nil
+}
+
+// isTopologyInjectionEnabled returns whether topology requirements are
+// injected into StorageClasses or PVCs as per the webhook config.
+func isTopologyInjectionEnabled() bool {
+	return cfg != nil && (cfg.TopologyInjection.Policy != "" || cfg.TopologyInjection.NamespaceZoneLabel != "")
+}
+
+// getInjectedTopologyKey returns the topology label key of the zones.
+func getInjectedTopologyKey() string {
+	return common.TopologyLabelsDomain + "/" + cfg.TopologyInjection.TopologyCategory
+}
+
+// mutateTopology helps mutate AdmissionReview requests creating StorageClasses
+// and PersistentVolumeClaims to inject the topology requirements of the
+// cluster policy.
+func mutateTopology(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if !isTopologyInjectionEnabled() || req.Operation != admissionv1.Create {
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	log := logger.GetLogger(ctx)
+	var obj interface{}
+	var wasMutated bool
+	switch req.Kind.Kind {
+	case "StorageClass":
+		sc := &storagev1.StorageClass{}
+		if err := json.Unmarshal(req.Object.Raw, sc); err != nil {
+			log.Errorf("error deserializing storage class: %v. skipping mutation.", err)
+			return &admissionv1.AdmissionResponse{
+				Allowed: true,
+			}
+		}
+		obj = sc
+		wasMutated = injectStorageClassTopology(ctx, sc)
+	case "PersistentVolumeClaim":
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := json.Unmarshal(req.Object.Raw, pvc); err != nil {
+			log.Errorf("error deserializing pvc: %v. skipping mutation.", err)
+			return &admissionv1.AdmissionResponse{
+				Allowed: true,
+			}
+		}
+		obj = pvc
+		var err error
+		wasMutated, err = setDefaultPVCZone(ctx, req.Namespace, pvc)
+		if err != nil {
+			log.Warnf("error setting the default zone of pvc %s/%s: %v. skipping mutation.",
+				req.Namespace, pvc.Name, err)
+			return &admissionv1.AdmissionResponse{
+				Allowed: true,
+			}
+		}
+	default:
+		log.Infof("Skipping mutation for resource type: %q", req.Kind.Kind)
+	}
+	if !wasMutated {
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	newRaw, err := json.Marshal(obj)
+	if err != nil {
+		log.Errorf("error serializing mutated %s: %v. skipping mutation.", req.Kind.Kind, err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	resp := admission.PatchResponseFromRaw(req.Object.Raw, newRaw)
+	if err := resp.Complete(admission.Request{AdmissionRequest: *req}); err != nil || !resp.Allowed {
+		log.Errorf("error creating the patch of the mutated %s: %v. skipping mutation.", req.Kind.Kind, err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	return &resp.AdmissionResponse
+}
+
+// injectStorageClassTopology injects the topology requirement of the cluster
+// policy into a vSphere CSI StorageClass created without allowedTopologies.
+// The API server defaults volumeBindingMode to Immediate, so with the
+// WaitForFirstConsumer policy, StorageClasses requesting Immediate binding are
+// mutated too.
+func injectStorageClassTopology(ctx context.Context, sc *storagev1.StorageClass) bool {
+	log := logger.GetLogger(ctx)
+	if sc.Provisioner != csitypes.Name || len(sc.AllowedTopologies) > 0 {
+		return false
+	}
+	switch cfg.TopologyInjection.Policy {
+	case topologyInjectionPolicyAllowedTopologies:
+		var zones []string
+		for _, zone := range strings.Split(cfg.TopologyInjection.AllowedZones, ",") {
+			if zone = strings.TrimSpace(zone); zone != "" {
+				zones = append(zones, zone)
+			}
+		}
+		sc.AllowedTopologies = []corev1.TopologySelectorTerm{
+			{
+				MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+					{
+						Key:    getInjectedTopologyKey(),
+						Values: zones,
+					},
+				},
+			},
+		}
+		log.Infof("Injecting allowedTopologies %v into StorageClass %q", sc.AllowedTopologies, sc.Name)
+		return true
+	case topologyInjectionPolicyWaitForFirstConsumer:
+		if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+			return false
+		}
+		volumeBindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+		sc.VolumeBindingMode = &volumeBindingMode
+		log.Infof("Injecting volumeBindingMode %q into StorageClass %q", volumeBindingMode, sc.Name)
+		return true
+	}
+	return false
+}
+
+// setDefaultPVCZone sets the csi.vsphere.volume-requested-topology annotation
+// of a PVC of a vSphere CSI StorageClass to the zone in the zone label of its
+// namespace, if the PVC does not specify a requested topology. The vanilla CSI
+// controller restricts the accessibility requirements of the CreateVolume
+// request of the PVC to the zones of this annotation.
+func setDefaultPVCZone(ctx context.Context, namespace string, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	log := logger.GetLogger(ctx)
+	zoneLabel := cfg.TopologyInjection.NamespaceZoneLabel
+	if zoneLabel == "" || metav1.HasAnnotation(pvc.ObjectMeta, common.AnnGuestClusterRequestedTopology) {
+		return false, nil
+	}
+	// Do not set the zone of PVCs using the default StorageClass.
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+
+	kubeClient, err := newK8sClient(ctx)
+	if err != nil {
+		return false, err
+	}
+	sc, err := kubeClient.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if sc.Provisioner != csitypes.Name {
+		return false, nil
+	}
+	ns, err := kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	zone := ns.Labels[zoneLabel]
+	if zone == "" {
+		return false, nil
+	}
+
+	requestedTopology, err := json.Marshal([]map[string]string{{getInjectedTopologyKey(): zone}})
+	if err != nil {
+		return false, err
+	}
+	metav1.SetMetaDataAnnotation(&pvc.ObjectMeta, common.AnnGuestClusterRequestedTopology, string(requestedTopology))
+	log.Infof("Setting the requested topology of PVC %s/%s to %s", namespace, pvc.Name, requestedTopology)
+	return true, nil
+}
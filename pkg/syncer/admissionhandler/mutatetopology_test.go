@@ -0,0 +1,282 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhandler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+// applyAdmissionPatch decodes the object of the request with the JSON patch
+// of the admission response applied into obj.
+func applyAdmissionPatch(t *testing.T, raw []byte, response *admissionv1.AdmissionResponse, obj interface{}) {
+	if len(response.Patch) != 0 {
+		require.NotNil(t, response.PatchType)
+		assert.Equal(t, admissionv1.PatchTypeJSONPatch, *response.PatchType)
+		patch, err := jsonpatch.DecodePatch(response.Patch)
+		require.NoError(t, err)
+		raw, err = patch.Apply(raw)
+		require.NoError(t, err)
+	}
+	require.NoError(t, json.Unmarshal(raw, obj))
+}
+
+func TestMutateStorageClassTopology(t *testing.T) {
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	immediate := storagev1.VolumeBindingImmediate
+	zoneKey := common.TopologyLabelsDomain + "/k8s-zone"
+	existingTopologies := []corev1.TopologySelectorTerm{
+		{
+			MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+				{Key: zoneKey, Values: []string{"zone-c"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                      string
+		policy                    string
+		sc                        *storagev1.StorageClass
+		expectedAllowedTopologies []corev1.TopologySelectorTerm
+		expectedVolumeBindingMode *storagev1.VolumeBindingMode
+	}{
+		{
+			name:   "AllowedTopologies are injected",
+			policy: topologyInjectionPolicyAllowedTopologies,
+			sc: &storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "sc"},
+				Provisioner:       csitypes.Name,
+				VolumeBindingMode: &immediate,
+			},
+			expectedAllowedTopologies: []corev1.TopologySelectorTerm{
+				{
+					MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+						{Key: zoneKey, Values: []string{"zone-a", "zone-b"}},
+					},
+				},
+			},
+			expectedVolumeBindingMode: &immediate,
+		},
+		{
+			name:   "WaitForFirstConsumer is injected",
+			policy: topologyInjectionPolicyWaitForFirstConsumer,
+			sc: &storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "sc"},
+				Provisioner:       csitypes.Name,
+				VolumeBindingMode: &immediate,
+			},
+			expectedVolumeBindingMode: &waitForFirstConsumer,
+		},
+		{
+			name:   "StorageClass with allowedTopologies is not mutated",
+			policy: topologyInjectionPolicyWaitForFirstConsumer,
+			sc: &storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "sc"},
+				Provisioner:       csitypes.Name,
+				VolumeBindingMode: &immediate,
+				AllowedTopologies: existingTopologies,
+			},
+			expectedAllowedTopologies: existingTopologies,
+			expectedVolumeBindingMode: &immediate,
+		},
+		{
+			name:   "StorageClass of other provisioners is not mutated",
+			policy: topologyInjectionPolicyAllowedTopologies,
+			sc: &storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "sc"},
+				Provisioner:       "other.csi.driver",
+				VolumeBindingMode: &immediate,
+			},
+			expectedVolumeBindingMode: &immediate,
+		},
+		{
+			name:   "StorageClass is not mutated without policy",
+			policy: "",
+			sc: &storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "sc"},
+				Provisioner:       csitypes.Name,
+				VolumeBindingMode: &immediate,
+			},
+			expectedVolumeBindingMode: &immediate,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			origCfg := cfg
+			defer func() {
+				cfg = origCfg
+			}()
+			cfg = &config{TopologyInjection: topologyInjectionConfig{
+				Policy:           test.policy,
+				TopologyCategory: "k8s-zone",
+				AllowedZones:     "zone-a, zone-b",
+			}}
+			raw, err := json.Marshal(test.sc)
+			require.NoError(t, err)
+
+			response := mutateTopology(context.Background(), &admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Kind: "StorageClass"},
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.True(t, response.Allowed)
+			mutatedSC := &storagev1.StorageClass{}
+			applyAdmissionPatch(t, raw, response, mutatedSC)
+			assert.Equal(t, test.expectedAllowedTopologies, mutatedSC.AllowedTopologies)
+			assert.Equal(t, test.expectedVolumeBindingMode, mutatedSC.VolumeBindingMode)
+		})
+	}
+}
+
+func TestMutatePVCTopology(t *testing.T) {
+	csiSCName := "csi-sc"
+	otherSCName := "other-sc"
+	zoneLabel := "example.com/zone"
+	kubeObjs := []runtime.Object{
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: csiSCName},
+			Provisioner: csitypes.Name,
+		},
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: otherSCName},
+			Provisioner: "other.csi.driver",
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "zonal",
+				Labels: map[string]string{zoneLabel: "zone-a"},
+			},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "non-zonal"},
+		},
+	}
+
+	tests := []struct {
+		name                      string
+		namespace                 string
+		storageClassName          *string
+		annotations               map[string]string
+		expectedRequestedTopology string
+	}{
+		{
+			name:                      "Default zone of namespace is set",
+			namespace:                 "zonal",
+			storageClassName:          &csiSCName,
+			expectedRequestedTopology: `[{"topology.csi.vmware.com/k8s-zone":"zone-a"}]`,
+		},
+		{
+			name:                      "Requested topology of PVC is kept",
+			namespace:                 "zonal",
+			storageClassName:          &csiSCName,
+			annotations:               map[string]string{common.AnnGuestClusterRequestedTopology: "[]"},
+			expectedRequestedTopology: "[]",
+		},
+		{
+			name:             "PVC in namespace without zone label is not mutated",
+			namespace:        "non-zonal",
+			storageClassName: &csiSCName,
+		},
+		{
+			name:             "PVC of other provisioners is not mutated",
+			namespace:        "zonal",
+			storageClassName: &otherSCName,
+		},
+		{
+			name:      "PVC of default StorageClass is not mutated",
+			namespace: "zonal",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset(kubeObjs...)
+			origK8sClient := newK8sClient
+			origCfg := cfg
+			defer func() {
+				newK8sClient = origK8sClient
+				cfg = origCfg
+			}()
+			newK8sClient = func(ctx context.Context) (clientset.Interface, error) {
+				return kubeClient, nil
+			}
+			cfg = &config{TopologyInjection: topologyInjectionConfig{
+				TopologyCategory:   "k8s-zone",
+				NamespaceZoneLabel: zoneLabel,
+			}}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pvc",
+					Annotations: test.annotations,
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: test.storageClassName,
+				},
+			}
+			raw, err := json.Marshal(pvc)
+			require.NoError(t, err)
+
+			response := mutateTopology(context.Background(), &admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Kind: "PersistentVolumeClaim"},
+				Namespace: test.namespace,
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.True(t, response.Allowed)
+			mutatedPVC := &corev1.PersistentVolumeClaim{}
+			applyAdmissionPatch(t, raw, response, mutatedPVC)
+			assert.Equal(t, test.expectedRequestedTopology,
+				mutatedPVC.Annotations[common.AnnGuestClusterRequestedTopology])
+		})
+	}
+}
+
+func TestValidateTopologyInjectionConfig(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, validateTopologyInjectionConfig(ctx, topologyInjectionConfig{}))
+	assert.NoError(t, validateTopologyInjectionConfig(ctx, topologyInjectionConfig{
+		Policy: topologyInjectionPolicyWaitForFirstConsumer,
+	}))
+	assert.NoError(t, validateTopologyInjectionConfig(ctx, topologyInjectionConfig{
+		Policy:           topologyInjectionPolicyAllowedTopologies,
+		TopologyCategory: "k8s-zone",
+		AllowedZones:     "zone-a",
+	}))
+	assert.Error(t, validateTopologyInjectionConfig(ctx, topologyInjectionConfig{
+		Policy: topologyInjectionPolicyAllowedTopologies,
+	}))
+	assert.Error(t, validateTopologyInjectionConfig(ctx, topologyInjectionConfig{
+		Policy: "Immediate",
+	}))
+	assert.Error(t, validateTopologyInjectionConfig(ctx, topologyInjectionConfig{
+		NamespaceZoneLabel: "example.com/zone",
+	}))
+}
@@ -119,6 +119,7 @@ type CSIGuestMutationWebhook struct {
 }
 
 func (h *CSIGuestWebhook) Handle(ctx context.Context, req admission.Request) (resp admission.Response) {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemAdmission)
 	log := logger.GetLogger(ctx)
 	log.Debugf("PV-CSI validation webhook handler called with request: %s/%s", req.Name, req.Namespace)
 	defer log.Debugf("PV-CSI validation webhook handler completed for the request: %s/%s",
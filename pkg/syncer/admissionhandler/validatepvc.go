@@ -74,13 +74,6 @@ func validatePVC(ctx context.Context, req *admissionv1.AdmissionRequest) *admiss
 		}
 		oldReq := oldPVC.Spec.Resources.Requests[corev1.ResourceStorage]
 
-		if isFileVolume(oldPVC.Spec.AccessModes, *oldPVC.Spec.VolumeMode) {
-			log.Info("PVC is a file volume. skipping validation.")
-			return &admissionv1.AdmissionResponse{
-				// skip validation if the pvc is not RWO
-				Allowed: true,
-			}
-		}
 		var newPVC corev1.PersistentVolumeClaim
 		var newReq resource.Quantity
 		if req.Operation != admissionv1.Delete {
@@ -95,7 +88,19 @@ func validatePVC(ctx context.Context, req *admissionv1.AdmissionRequest) *admiss
 				}
 			}
 			newReq = newPVC.Spec.Resources.Requests[corev1.ResourceStorage]
-		} else {
+		}
+
+		if isFileVolume(oldPVC.Spec.AccessModes, *oldPVC.Spec.VolumeMode) {
+			if isPVCExpansionValidationEnabled() && req.Operation == admissionv1.Update && newReq.Cmp(oldReq) > 0 {
+				return validatePVCExpansion(ctx, oldPVC, newReq)
+			}
+			log.Info("PVC is a file volume. skipping validation.")
+			return &admissionv1.AdmissionResponse{
+				// skip validation if the pvc is not RWO
+				Allowed: true,
+			}
+		}
+		if req.Operation == admissionv1.Delete {
 			reclaimPolicy, err := getPVReclaimPolicyForPVC(ctx, oldPVC)
 			if err != nil {
 				log.Warnf("error getting reclaim policy for pvc: %v. skipping validation.", err)
@@ -201,6 +206,11 @@ func validatePVC(ctx context.Context, req *admissionv1.AdmissionRequest) *admiss
 				}
 			}
 		}
+		// Validate the expansion against the limits of the datastore of the volume.
+		if isPVCExpansionValidationEnabled() && allowed && req.Operation == admissionv1.Update &&
+			newReq.Cmp(oldReq) > 0 {
+			return validatePVCExpansion(ctx, oldPVC, newReq)
+		}
 	default:
 		allowed = false
 		log.Errorf("Can't validate resource kind: %q using validatePVC function", req.Kind.Kind)
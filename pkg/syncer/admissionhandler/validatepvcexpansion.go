@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+const (
+	// maxVirtualDiskSizeInBytes is the maximum size of a virtual disk on vSAN
+	// and VMFS datastores, used when the datastore does not report it.
+	maxVirtualDiskSizeInBytes    = 62 * 1024 * common.GbInBytes
+	invalidExpansionErrorMessage = "Invalid PVC expansion"
+)
+
+var (
+	// pvcExpansionLookup looks up the datastores of the volumes being expanded.
+	// Lookups are disabled if nil.
+	pvcExpansionLookup *volumeDatastoreLookup
+)
+
+// isPVCExpansionValidationEnabled returns whether PVC expansions are validated
+// against the vSphere limits. This is only done by the webhook of vanilla
+// clusters, which reads the webhook config.
+func isPVCExpansionValidationEnabled() bool {
+	return cfg != nil
+}
+
+// validatePVCExpansion validates the expansion of the vSphere CSI volume bound
+// to the PVC to newSize can succeed. Expansions which cannot succeed are
+// denied, or admitted with warnings, depending on the webhook config.
+func validatePVCExpansion(ctx context.Context, pvc corev1.PersistentVolumeClaim,
+	newSize resource.Quantity) *admissionv1.AdmissionResponse {
+	log := logger.GetLogger(ctx)
+	if pvc.Spec.VolumeName == "" {
+		log.Debugf("No PV is bound to the PVC %s/%s. skipping expansion validation.", pvc.Namespace, pvc.Name)
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	kubeClient, err := newK8sClient(ctx)
+	if err != nil {
+		log.Warnf("failed to get kube client: %v. skipping expansion validation.", err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	pv, err := kubeClient.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("failed to get PV %q: %v. skipping expansion validation.", pvc.Spec.VolumeName, err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != csitypes.Name {
+		log.Debugf("PV %q is not a vSphere CSI volume. skipping expansion validation.", pv.Name)
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	problems, warnings := getPVCExpansionProblems(ctx, pvc, pv, newSize)
+	if len(problems) > 0 {
+		message := invalidExpansionErrorMessage + ": " + strings.Join(problems, "; ")
+		if cfg != nil && cfg.PVCExpansionValidation.DenyInvalidExpansion {
+			log.Errorf("Denying expansion of PVC %s/%s. %s", pvc.Namespace, pvc.Name, message)
+			return &admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Reason: metav1.StatusReason(message),
+				},
+			}
+		}
+		warnings = append(warnings, message)
+	}
+	return &admissionv1.AdmissionResponse{
+		Allowed:  true,
+		Warnings: warnings,
+	}
+}
+
+// getPVCExpansionProblems returns the reasons the expansion of the volume of
+// the PV bound to the PVC to newSize cannot succeed, and warnings for the limits which could
+// not be verified. The free space and maximum virtual disk size of the
+// datastore of the volume are looked up if enabled. Only thick provisioned
+// volumes are guaranteed to need the whole size increase from the datastore,
+// so the free space of the datastore only produces a warning for the others.
+func getPVCExpansionProblems(ctx context.Context, pvc corev1.PersistentVolumeClaim, pv *corev1.PersistentVolume,
+	newSize resource.Quantity) ([]string, []string) {
+	log := logger.GetLogger(ctx)
+	volumeID := pv.Spec.CSI.VolumeHandle
+	if pvc.Spec.VolumeMode != nil && isFileVolume(pvc.Spec.AccessModes, *pvc.Spec.VolumeMode) {
+		// vSAN file service shares backing file volumes cannot be expanded.
+		return []string{"expanding file volumes is not supported"}, nil
+	}
+	var problems, warnings []string
+	maxSize := maxVirtualDiskSizeInBytes
+	if pvcExpansionLookup != nil {
+		datastore, backing, err := pvcExpansionLookup.GetVolumeDatastore(ctx, volumeID)
+		if err != nil {
+			log.Warnf("failed to look up the datastore of volume %q in vCenter. Error: %v", volumeID, err)
+			warnings = append(warnings, "unable to verify the datastore of the volume has enough free space")
+		} else {
+			if datastore.Info.MaxVirtualDiskCapacity > 0 {
+				maxSize = datastore.Info.MaxVirtualDiskCapacity
+			}
+			// The capacity of the PV is the size of the volume. The capacity
+			// of the PVC lags behind it until the file system of a volume
+			// expanded in vCenter is expanded by the node.
+			currentSize, ok := pv.Spec.Capacity[corev1.ResourceStorage]
+			if !ok {
+				currentSize = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			}
+			increase := newSize.Value() - currentSize.Value()
+			if increase > datastore.Info.FreeSpace {
+				message := fmt.Sprintf("size increase %s exceeds the free space %s of datastore %q",
+					resource.NewQuantity(increase, resource.BinarySI),
+					resource.NewQuantity(datastore.Info.FreeSpace, resource.BinarySI), datastore.Info.Name)
+				if backing.isThick() {
+					problems = append(problems, message)
+				} else {
+					warnings = append(warnings, message)
+				}
+			}
+		}
+	}
+	if newSize.Value() > maxSize {
+		problems = append(problems, fmt.Sprintf("requested size %s exceeds the maximum virtual disk size %s",
+			newSize.String(), resource.NewQuantity(maxSize, resource.BinarySI)))
+	}
+	return problems, warnings
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhandler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/object"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+const (
+	testExpansionVolumeID     = "test-volume-id"
+	testExpansionDatastoreURL = "ds:///vmfs/volumes/ds1/"
+	testExpansionVCHost       = "vc1"
+)
+
+var testExpansionDatastoreRef = vimtypes.ManagedObjectReference{Type: "Datastore", Value: "datastore-1"}
+
+// newTestVolumeDatastoreLookup returns a volumeDatastoreLookup whose
+// AuthManager has the datastore of testExpansionDatastoreURL with the given
+// free space and maximum virtual disk size.
+func newTestVolumeDatastoreLookup(t *testing.T, freeSpace, maxVirtualDiskSize int64) *volumeDatastoreLookup {
+	authMgr, err := common.GetAuthorizationServiceForTesting(context.Background(), nil,
+		map[string]*cnsvsphere.DatastoreInfo{
+			testExpansionDatastoreURL: {
+				Datastore: &cnsvsphere.Datastore{Datastore: object.NewDatastore(nil, testExpansionDatastoreRef)},
+				Info: &vimtypes.DatastoreInfo{
+					Name:                   "ds1",
+					Url:                    testExpansionDatastoreURL,
+					FreeSpace:              freeSpace,
+					MaxVirtualDiskCapacity: maxVirtualDiskSize,
+				},
+			},
+		}, nil)
+	require.NoError(t, err)
+	return &volumeDatastoreLookup{
+		authMgrs: map[string]*common.AuthManager{testExpansionVCHost: authMgr},
+		ttl:      time.Hour,
+		backings: make(map[string]*volumeBacking),
+	}
+}
+
+// TestValidatePVCExpansion is the unit test for validating the expansions of
+// the PVCs of the vSphere CSI driver against the limits of their datastores.
+func TestValidatePVCExpansion(t *testing.T) {
+	csiPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testPVName,
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("5Gi"),
+			},
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       csitypes.Name,
+					VolumeHandle: testExpansionVolumeID,
+				},
+			},
+		},
+	}
+	// The volume of expandedPV was expanded in vCenter but the capacity of
+	// its PVC wasn't updated yet.
+	expandedPV := csiPV.DeepCopy()
+	expandedPV.Spec.Capacity[corev1.ResourceStorage] = resource.MustParse("8Gi")
+	rwxPVC := oldPVC.DeepCopy()
+	rwxPVC.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+
+	tests := []struct {
+		name                  string
+		pv                    *corev1.PersistentVolume
+		pvc                   *corev1.PersistentVolumeClaim
+		newSize               string
+		deny                  bool
+		lookup                bool
+		lookupErr             error
+		provisioningType      string
+		freeSpace             int64
+		maxVirtualDiskSize    int64
+		expectedAllowed       bool
+		expectedMessage       string
+		expectedWarningsCount int
+	}{
+		{
+			name:            "Expansion of non vSphere CSI volume is not validated",
+			pv:              testPV,
+			pvc:             oldPVC,
+			newSize:         "100Ti",
+			deny:            true,
+			expectedAllowed: true,
+		},
+		{
+			name:            "Expansion within the maximum virtual disk size",
+			pv:              csiPV,
+			pvc:             oldPVC,
+			newSize:         "10Gi",
+			deny:            true,
+			expectedAllowed: true,
+		},
+		{
+			name:                  "Expansion beyond the maximum virtual disk size is warned",
+			pv:                    csiPV,
+			pvc:                   oldPVC,
+			newSize:               "63Ti",
+			expectedAllowed:       true,
+			expectedMessage:       "exceeds the maximum virtual disk size 62Ti",
+			expectedWarningsCount: 1,
+		},
+		{
+			name:            "Expansion beyond the maximum virtual disk size is denied",
+			pv:              csiPV,
+			pvc:             oldPVC,
+			newSize:         "63Ti",
+			deny:            true,
+			expectedAllowed: false,
+			expectedMessage: "exceeds the maximum virtual disk size 62Ti",
+		},
+		{
+			name:            "Expansion of file volume is denied",
+			pv:              csiPV,
+			pvc:             rwxPVC,
+			newSize:         "10Gi",
+			deny:            true,
+			expectedAllowed: false,
+			expectedMessage: "expanding file volumes is not supported",
+		},
+		{
+			name:            "Expansion within the free space of the datastore",
+			pv:              csiPV,
+			pvc:             oldPVC,
+			newSize:         "10Gi",
+			deny:            true,
+			lookup:          true,
+			freeSpace:       5 * 1024 * 1024 * 1024,
+			expectedAllowed: true,
+		},
+		{
+			name:             "Expansion of thick volume beyond the free space of the datastore is denied",
+			pv:               csiPV,
+			pvc:              oldPVC,
+			newSize:          "10Gi",
+			deny:             true,
+			lookup:           true,
+			provisioningType: string(vimtypes.BaseConfigInfoDiskFileBackingInfoProvisioningTypeEagerZeroedThick),
+			freeSpace:        1024 * 1024 * 1024,
+			expectedAllowed:  false,
+			expectedMessage:  "size increase 5Gi exceeds the free space 1Gi of datastore",
+		},
+		{
+			name:                  "Expansion of thin volume beyond the free space of the datastore is warned",
+			pv:                    csiPV,
+			pvc:                   oldPVC,
+			newSize:               "10Gi",
+			deny:                  true,
+			lookup:                true,
+			provisioningType:      string(vimtypes.BaseConfigInfoDiskFileBackingInfoProvisioningTypeThin),
+			freeSpace:             1024 * 1024 * 1024,
+			expectedAllowed:       true,
+			expectedMessage:       "size increase 5Gi exceeds the free space 1Gi of datastore",
+			expectedWarningsCount: 1,
+		},
+		{
+			name:             "Expansion of volume already partly expanded is within the free space of the datastore",
+			pv:               expandedPV,
+			pvc:              oldPVC,
+			newSize:          "10Gi",
+			deny:             true,
+			lookup:           true,
+			provisioningType: string(vimtypes.BaseConfigInfoDiskFileBackingInfoProvisioningTypeEagerZeroedThick),
+			freeSpace:        3 * 1024 * 1024 * 1024,
+			expectedAllowed:  true,
+		},
+		{
+			name:               "Expansion beyond the maximum virtual disk size of the datastore is denied",
+			pv:                 csiPV,
+			pvc:                oldPVC,
+			newSize:            "10Gi",
+			deny:               true,
+			lookup:             true,
+			freeSpace:          100 * 1024 * 1024 * 1024,
+			maxVirtualDiskSize: 8 * 1024 * 1024 * 1024,
+			expectedAllowed:    false,
+			expectedMessage:    "exceeds the maximum virtual disk size 8Gi",
+		},
+		{
+			name:                  "Expansion is warned if the datastore cannot be looked up",
+			pv:                    csiPV,
+			pvc:                   oldPVC,
+			newSize:               "10Gi",
+			deny:                  true,
+			lookup:                true,
+			lookupErr:             errors.New("connection refused"),
+			expectedAllowed:       true,
+			expectedMessage:       "unable to verify the datastore of the volume has enough free space",
+			expectedWarningsCount: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset(test.pv)
+			origK8sClient := newK8sClient
+			origCfg := cfg
+			defer func() {
+				newK8sClient = origK8sClient
+				cfg = origCfg
+				pvcExpansionLookup = nil
+				retrieveVolumeBacking = _retrieveVolumeBacking
+			}()
+			newK8sClient = func(ctx context.Context) (clientset.Interface, error) {
+				return kubeClient, nil
+			}
+			cfg = &config{PVCExpansionValidation: pvcExpansionValidationConfig{DenyInvalidExpansion: test.deny}}
+			if test.lookup {
+				pvcExpansionLookup = newTestVolumeDatastoreLookup(t, test.freeSpace, test.maxVirtualDiskSize)
+				retrieveVolumeBacking = func(ctx context.Context, vcs []*cnsvsphere.VirtualCenter,
+					volumeID string) (*volumeBacking, error) {
+					if test.lookupErr != nil {
+						return nil, test.lookupErr
+					}
+					assert.Equal(t, testExpansionVolumeID, volumeID)
+					return &volumeBacking{
+						vcHost:           testExpansionVCHost,
+						datastore:        testExpansionDatastoreRef,
+						provisioningType: test.provisioningType,
+					}, nil
+				}
+			}
+
+			response := validatePVCExpansion(context.Background(), *test.pvc, resource.MustParse(test.newSize))
+			assert.Equal(t, test.expectedAllowed, response.Allowed)
+			assert.Len(t, response.Warnings, test.expectedWarningsCount)
+			if test.expectedAllowed {
+				assert.Nil(t, response.Result)
+				for _, warning := range response.Warnings {
+					assert.Contains(t, warning, test.expectedMessage)
+				}
+			} else {
+				assert.Contains(t, string(response.Result.Reason), invalidExpansionErrorMessage)
+				assert.Contains(t, string(response.Result.Reason), test.expectedMessage)
+			}
+		})
+	}
+}
+
+// TestVolumeDatastoreLookup is the unit test for looking up the datastores of
+// volumes in the datastores of the AuthManager.
+func TestVolumeDatastoreLookup(t *testing.T) {
+	ctx := context.Background()
+	defer func() {
+		retrieveVolumeBacking = _retrieveVolumeBacking
+	}()
+	retrieves := 0
+	datastoreRef := testExpansionDatastoreRef
+	retrieveVolumeBacking = func(ctx context.Context, vcs []*cnsvsphere.VirtualCenter,
+		volumeID string) (*volumeBacking, error) {
+		retrieves++
+		return &volumeBacking{vcHost: testExpansionVCHost, datastore: datastoreRef}, nil
+	}
+	lookup := newTestVolumeDatastoreLookup(t, 1024, 0)
+
+	for i := 0; i < 2; i++ {
+		datastore, _, err := lookup.GetVolumeDatastore(ctx, testExpansionVolumeID)
+		require.NoError(t, err)
+		assert.Equal(t, "ds1", datastore.Info.Name)
+	}
+	assert.Equal(t, 1, retrieves, "the backing of the volume should be cached")
+
+	// The backing is retrieved again once expired.
+	lookup.backings[testExpansionVolumeID].retrieveTime = time.Now().Add(-2 * time.Hour)
+	datastoreRef = vimtypes.ManagedObjectReference{Type: "Datastore", Value: "datastore-2"}
+	_, _, err := lookup.GetVolumeDatastore(ctx, testExpansionVolumeID)
+	assert.ErrorContains(t, err, "not found in the datastores of vCenter")
+	assert.Equal(t, 2, retrieves)
+}
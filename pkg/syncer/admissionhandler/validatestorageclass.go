@@ -19,6 +19,8 @@ package admissionhandler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	stroagev1 "k8s.io/api/storage/v1"
@@ -42,9 +44,28 @@ var (
 	}
 )
 
+var (
+	// supportedFsTypes are the filesystem types supported by the vSphere CSI
+	// driver for the csi.storage.k8s.io/fstype parameter.
+	supportedFsTypes = parameterSet{
+		common.Ext4FsType:  struct{}{},
+		common.Ext3FsType:  struct{}{},
+		common.XFSType:     struct{}{},
+		common.BtrfsFsType: struct{}{},
+		common.NTFSFsType:  struct{}{},
+		common.NfsFsType:   struct{}{},
+		common.NfsV4FsType: struct{}{},
+	}
+)
+
 const (
 	migrationParamErrorMessage = "Invalid StorageClass Parameters. " +
 		"Migration specific parameters should not be used in the StorageClass"
+	invalidParamsErrorMessage = "Invalid StorageClass Parameters"
+	// csiParamPrefix is the prefix of the StorageClass parameters interpreted
+	// by the external-provisioner, which are not passed to the CSI driver.
+	csiParamPrefix = "csi.storage.k8s.io/"
+	csiFsTypeParam = csiParamPrefix + "fstype"
 )
 
 // validateStorageClass helps validate AdmissionReview requests for StroageClass.
@@ -52,6 +73,7 @@ func validateStorageClass(ctx context.Context, ar *admissionv1.AdmissionReview)
 	log := logger.GetLogger(ctx)
 	req := ar.Request
 	var result *metav1.Status
+	var warnings []string
 	allowed := true
 
 	switch req.Kind.Kind {
@@ -78,6 +100,25 @@ func validateStorageClass(ctx context.Context, ar *admissionv1.AdmissionReview)
 					break
 				}
 			}
+			if allowed {
+				var problems []string
+				problems, warnings = validateStorageClassParams(ctx, sc.Parameters)
+				if len(problems) > 0 {
+					message := invalidParamsErrorMessage + ": " + strings.Join(problems, "; ")
+					// StorageClass parameters are immutable, so only the creation of
+					// StorageClasses is denied to not block updating the other fields
+					// of existing StorageClasses.
+					if cfg != nil && cfg.StorageClassValidation.DenyInvalidParameters &&
+						req.Operation == admissionv1.Create {
+						allowed = false
+						result = &metav1.Status{
+							Reason: metav1.StatusReason(message),
+						}
+					} else {
+						warnings = append(warnings, message)
+					}
+				}
+			}
 		}
 		if allowed {
 			log.Infof("Validation of StorageClass: %q Passed", sc.Name)
@@ -90,7 +131,69 @@ func validateStorageClass(ctx context.Context, ar *admissionv1.AdmissionReview)
 	}
 	// return AdmissionResponse result
 	return &admissionv1.AdmissionResponse{
-		Allowed: allowed,
-		Result:  result,
+		Allowed:  allowed,
+		Result:   result,
+		Warnings: warnings,
+	}
+}
+
+// validateStorageClassParams validates the parameters of a StorageClass of the
+// vSphere CSI driver with the parsing of the CSI controller. The storage
+// policy and datastore the parameters refer to are looked up in vCenter if
+// enabled. It returns the problems found with the parameters, and warnings
+// for the parameters which are deprecated or could not be verified.
+func validateStorageClassParams(ctx context.Context, params map[string]string) ([]string, []string) {
+	log := logger.GetLogger(ctx)
+	var problems, warnings []string
+	// The external-provisioner strips the csi.storage.k8s.io/ parameters
+	// before passing the parameters to CreateVolume.
+	volumeParams := make(map[string]string)
+	for param, value := range params {
+		if !strings.HasPrefix(param, csiParamPrefix) {
+			volumeParams[param] = value
+		}
+		if strings.ToLower(param) == common.AttributeFsType {
+			warnings = append(warnings, fmt.Sprintf("parameter %q is deprecated, use %q instead",
+				param, csiFsTypeParam))
+		}
+	}
+	fsType := params[csiFsTypeParam]
+	if fsType != "" && !supportedFsTypes.Has(strings.ToLower(fsType)) {
+		problems = append(problems, fmt.Sprintf("fstype %q is not supported", fsType))
+	}
+	scParams, err := common.ParseStorageClassParams(ctx, volumeParams)
+	if err != nil {
+		problems = append(problems, err.Error())
+		return problems, warnings
+	}
+	if scParams.MkfsOptions != "" {
+		if _, err := common.ParseMkfsOptions(fsType, scParams.MkfsOptions); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %q parameter: %v", common.AttributeMkfsOptions, err))
+		}
+	}
+	if scParamsLookup == nil {
+		return problems, warnings
+	}
+	if scParams.StoragePolicyName != "" {
+		found, err := scParamsLookup.StoragePolicyExists(ctx, scParams.StoragePolicyName)
+		if err != nil {
+			log.Warnf("failed to look up storage policy %q in vCenter. Error: %v", scParams.StoragePolicyName, err)
+			warnings = append(warnings, fmt.Sprintf("unable to verify storage policy %q exists in vCenter",
+				scParams.StoragePolicyName))
+		} else if !found {
+			problems = append(problems, fmt.Sprintf("storage policy %q not found in vCenter",
+				scParams.StoragePolicyName))
+		}
+	}
+	if scParams.DatastoreURL != "" {
+		found, err := scParamsLookup.DatastoreExists(ctx, scParams.DatastoreURL)
+		if err != nil {
+			log.Warnf("failed to look up datastore %q in vCenter. Error: %v", scParams.DatastoreURL, err)
+			warnings = append(warnings, fmt.Sprintf("unable to verify datastore %q exists in vCenter",
+				scParams.DatastoreURL))
+		} else if !found {
+			problems = append(problems, fmt.Sprintf("datastore %q not found in vCenter", scParams.DatastoreURL))
+		}
 	}
+	return problems, warnings
 }
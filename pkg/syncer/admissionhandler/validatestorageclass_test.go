@@ -18,12 +18,18 @@ package admissionhandler
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 )
 
 var admissionReview = v1.AdmissionReview{
@@ -116,3 +122,139 @@ func TestValidateStorageClassForValidStorageClass(t *testing.T) {
 	}
 	t.Log("TestValidateStorageClassForValidStorageClass Passed")
 }
+
+// fakeVCenterInventory returns a vCenter inventory with the given storage
+// policies and datastores.
+func fakeVCenterInventory(storagePolicyNames []string, datastoreURLs []string) *vCenterInventory {
+	inventory := &vCenterInventory{
+		storagePolicyNames: make(parameterSet),
+		datastores:         make(map[string]*cnsvsphere.DatastoreInfo),
+	}
+	for _, name := range storagePolicyNames {
+		inventory.storagePolicyNames[name] = struct{}{}
+	}
+	for _, url := range datastoreURLs {
+		inventory.datastores[url] = &cnsvsphere.DatastoreInfo{Info: &vimtypes.DatastoreInfo{Url: url}}
+	}
+	return inventory
+}
+
+// TestValidateStorageClassParams is the unit test for validating the
+// parameters of StorageClasses of the vSphere CSI driver.
+func TestValidateStorageClassParams(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tests := []struct {
+		name         string
+		params       map[string]string
+		lookupErr    error
+		wantProblems int
+		wantWarnings int
+	}{
+		{
+			name: "valid parameters",
+			params: map[string]string{
+				"storagepolicyname":         "vSAN Default Storage Policy",
+				"datastoreurl":              "ds:///vmfs/volumes/vsan:1/",
+				"csi.storage.k8s.io/fstype": "xfs",
+				"mkfsoptions":               "-b 4096",
+			},
+		},
+		{
+			name:         "unknown parameter",
+			params:       map[string]string{"storagepolicy": "vSAN Default Storage Policy"},
+			wantProblems: 1,
+		},
+		{
+			name:         "unsupported fstype",
+			params:       map[string]string{"csi.storage.k8s.io/fstype": "zfs"},
+			wantProblems: 1,
+		},
+		{
+			name:         "mkfs options not allowed for fstype",
+			params:       map[string]string{"csi.storage.k8s.io/fstype": "xfs", "mkfsoptions": "-E lazy_itable_init=1"},
+			wantProblems: 1,
+		},
+		{
+			name:         "deprecated fstype",
+			params:       map[string]string{"fstype": "ext4"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "storage policy and datastore not found",
+			params:       map[string]string{"storagePolicyName": "gold", "datastoreURL": "ds:///vmfs/volumes/missing/"},
+			wantProblems: 2,
+		},
+		{
+			name:         "vCenter unreachable",
+			params:       map[string]string{"storagepolicyname": "gold"},
+			lookupErr:    errors.New("vCenter unreachable"),
+			wantWarnings: 1,
+		},
+	}
+	defer func() {
+		scParamsLookup = nil
+		listVCenterInventory = _listVCenterInventory
+	}()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			listVCenterInventory = func(ctx context.Context,
+				vcConfigs []*cnsvsphere.VirtualCenterConfig) (*vCenterInventory, error) {
+				if test.lookupErr != nil {
+					return nil, test.lookupErr
+				}
+				return fakeVCenterInventory([]string{"vSAN Default Storage Policy"},
+					[]string{"ds:///vmfs/volumes/vsan:1/"}), nil
+			}
+			scParamsLookup = &vCenterLookup{ttl: time.Minute}
+			problems, warnings := validateStorageClassParams(ctx, test.params)
+			if len(problems) != test.wantProblems || len(warnings) != test.wantWarnings {
+				t.Fatalf("unexpected result for parameters %v. problems: %v, warnings: %v",
+					test.params, problems, warnings)
+			}
+		})
+	}
+}
+
+// TestValidateStorageClassForInvalidParameters is the unit test for validating
+// admissionReview requests containing StorageClasses with invalid parameters
+// in the warn and deny modes.
+func TestValidateStorageClassForInvalidParameters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer func() {
+		cfg = nil
+	}()
+	admissionReview.Request.Object = runtime.RawExtension{
+		Raw: []byte("{\n  \"kind\": \"StorageClass\",\n  \"apiVersion\": \"storage.k8s.io/v1\",\n  \"metadata\": " +
+			"{\n    \"name\": \"sc\"\n  },\n  \"provisioner\": \"csi.vsphere.vmware.com\",\n  " +
+			"\"parameters\": {\n    \"storagepolicyname\": \"gold\",\n    " +
+			"\"csi.storage.k8s.io/fstype\": \"zfs\"\n  }\n}"),
+	}
+
+	// Invalid parameters are admitted with warnings by default.
+	admissionReview.Request.Operation = v1.Create
+	admissionResponse := validateStorageClass(ctx, &admissionReview)
+	if !admissionResponse.Allowed || len(admissionResponse.Warnings) != 1 ||
+		!strings.Contains(admissionResponse.Warnings[0], invalidParamsErrorMessage) {
+		t.Fatalf("expected StorageClass to be admitted with a warning. admissionResponse: %v", admissionResponse)
+	}
+
+	// Invalid parameters are denied on creation in the deny mode.
+	cfg = &config{StorageClassValidation: storageClassValidationConfig{DenyInvalidParameters: true}}
+	admissionResponse = validateStorageClass(ctx, &admissionReview)
+	if admissionResponse.Allowed || admissionResponse.Result == nil ||
+		!strings.Contains(string(admissionResponse.Result.Reason), "fstype \"zfs\" is not supported") {
+		t.Fatalf("expected StorageClass to be denied. admissionResponse: %v", admissionResponse)
+	}
+
+	// Updates of existing StorageClasses are admitted with warnings in the
+	// deny mode.
+	admissionReview.Request.Operation = v1.Update
+	admissionResponse = validateStorageClass(ctx, &admissionReview)
+	if !admissionResponse.Allowed || len(admissionResponse.Warnings) != 1 {
+		t.Fatalf("expected StorageClass update to be admitted with a warning. admissionResponse: %v",
+			admissionResponse)
+	}
+	admissionReview.Request.Operation = ""
+}
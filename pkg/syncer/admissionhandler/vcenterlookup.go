@@ -0,0 +1,372 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pbmtypes "github.com/vmware/govmomi/pbm/types"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vslm"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	cnsconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+)
+
+const (
+	// vCenterLookupTimeout is the maximum time spent listing the storage
+	// policies and datastores of vCenter during an admission request.
+	vCenterLookupTimeout = 5 * time.Second
+	// minVCenterLookupRefreshInterval is the minimum time between two listings
+	// of the storage policies and datastores of vCenter when a StorageClass
+	// refers to one which is not cached.
+	minVCenterLookupRefreshInterval = 30 * time.Second
+)
+
+var (
+	// scParamsLookup looks up the storage policies and datastores referred to
+	// by the StorageClass parameters in vCenter. Lookups are disabled if nil.
+	scParamsLookup *vCenterLookup
+	// listVCenterInventory lists the storage policies and datastores of the
+	// vCenter servers.
+	listVCenterInventory = _listVCenterInventory
+	// retrieveVolumeBacking retrieves the backing of a volume from the vCenter
+	// servers.
+	retrieveVolumeBacking = _retrieveVolumeBacking
+)
+
+// vCenterInventory is the names of the storage policies and the datastores,
+// by URL, of the vCenter servers.
+type vCenterInventory struct {
+	storagePolicyNames parameterSet
+	datastores         map[string]*cnsvsphere.DatastoreInfo
+}
+
+// vCenterLookup looks up storage policies and datastores in the inventory of
+// the vCenter servers, which is listed at most once per cache TTL.
+type vCenterLookup struct {
+	vcConfigs []*cnsvsphere.VirtualCenterConfig
+	ttl       time.Duration
+	mutex     sync.Mutex
+	inventory *vCenterInventory
+	listTime  time.Time
+}
+
+// newVCenterLookup returns a vCenterLookup for the vCenter servers of the
+// vSphere config.
+func newVCenterLookup(ctx context.Context, cacheTTLInSec int) (*vCenterLookup, error) {
+	log := logger.GetLogger(ctx)
+	cfg, err := cnsconfig.GetConfig(ctx)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to read vSphere config. Error: %v", err)
+	}
+	vcConfigs, err := cnsvsphere.GetVirtualCenterConfigs(ctx, cfg)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to get vCenter configs. Error: %v", err)
+	}
+	if cacheTTLInSec <= 0 {
+		cacheTTLInSec = defaultVCenterLookupCacheTTLInSec
+	}
+	return &vCenterLookup{
+		vcConfigs: vcConfigs,
+		ttl:       time.Duration(cacheTTLInSec) * time.Second,
+	}, nil
+}
+
+// StoragePolicyExists returns whether a storage policy with the given name
+// exists in any of the vCenter servers.
+func (l *vCenterLookup) StoragePolicyExists(ctx context.Context, storagePolicyName string) (bool, error) {
+	return l.find(ctx, func(inventory *vCenterInventory) bool {
+		return inventory.storagePolicyNames.Has(storagePolicyName)
+	})
+}
+
+// DatastoreExists returns whether a datastore with the given URL exists in any
+// of the vCenter servers.
+func (l *vCenterLookup) DatastoreExists(ctx context.Context, datastoreURL string) (bool, error) {
+	datastore, err := l.GetDatastore(ctx, datastoreURL)
+	return datastore != nil, err
+}
+
+// GetDatastore returns the info of the datastore with the given URL, or nil
+// if it does not exist in any of the vCenter servers. The capacity of the
+// datastore is as of the last listing of the inventory.
+func (l *vCenterLookup) GetDatastore(ctx context.Context, datastoreURL string) (*cnsvsphere.DatastoreInfo, error) {
+	var datastore *cnsvsphere.DatastoreInfo
+	_, err := l.find(ctx, func(inventory *vCenterInventory) bool {
+		datastore = inventory.datastores[datastoreURL]
+		return datastore != nil
+	})
+	return datastore, err
+}
+
+// find looks up an object in the cached inventory. The inventory is listed
+// again once expired, or when the object is not found in it and it was listed
+// more than minVCenterLookupRefreshInterval ago, so that the objects created
+// in vCenter recently are found.
+func (l *vCenterLookup) find(ctx context.Context, contains func(*vCenterInventory) bool) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.inventory != nil && time.Since(l.listTime) < l.ttl &&
+		(contains(l.inventory) || time.Since(l.listTime) < minVCenterLookupRefreshInterval) {
+		return contains(l.inventory), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, vCenterLookupTimeout)
+	defer cancel()
+	inventory, err := listVCenterInventory(ctx, l.vcConfigs)
+	if err != nil {
+		return false, err
+	}
+	l.inventory = inventory
+	l.listTime = time.Now()
+	return contains(inventory), nil
+}
+
+// getConnectedVirtualCenter returns the connected vCenter for the vCenter
+// config, registering it first if needed.
+func getConnectedVirtualCenter(ctx context.Context,
+	vcConfig *cnsvsphere.VirtualCenterConfig) (*cnsvsphere.VirtualCenter, error) {
+	log := logger.GetLogger(ctx)
+	vcManager := cnsvsphere.GetVirtualCenterManager(ctx)
+	vc, err := vcManager.GetVirtualCenter(ctx, vcConfig.Host)
+	if err != nil {
+		vc, err = vcManager.RegisterVirtualCenter(ctx, vcConfig)
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to register vCenter %q. Error: %v", vcConfig.Host, err)
+		}
+	}
+	if err := vc.Connect(ctx); err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to connect to vCenter %q. Error: %v", vcConfig.Host, err)
+	}
+	return vc, nil
+}
+
+// _listVCenterInventory lists the storage policies and the datastores of the
+// vCenter servers.
+func _listVCenterInventory(ctx context.Context,
+	vcConfigs []*cnsvsphere.VirtualCenterConfig) (*vCenterInventory, error) {
+	log := logger.GetLogger(ctx)
+	inventory := &vCenterInventory{
+		storagePolicyNames: make(parameterSet),
+		datastores:         make(map[string]*cnsvsphere.DatastoreInfo),
+	}
+	for _, vcConfig := range vcConfigs {
+		vc, err := getConnectedVirtualCenter(ctx, vcConfig)
+		if err != nil {
+			return nil, err
+		}
+		if err := vc.ConnectPbm(ctx); err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to connect to PBM of vCenter %q. Error: %v",
+				vcConfig.Host, err)
+		}
+		profileIDs, err := vc.PbmClient.QueryProfile(ctx, pbmtypes.PbmProfileResourceType{
+			ResourceType: string(pbmtypes.PbmProfileResourceTypeEnumSTORAGE),
+		}, string(pbmtypes.PbmProfileCategoryEnumREQUIREMENT))
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to query the storage policies of vCenter %q. Error: %v",
+				vcConfig.Host, err)
+		}
+		profiles, err := vc.PbmClient.RetrieveContent(ctx, profileIDs)
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to retrieve the storage policies of vCenter %q. Error: %v",
+				vcConfig.Host, err)
+		}
+		for _, profile := range profiles {
+			inventory.storagePolicyNames[profile.GetPbmProfile().Name] = struct{}{}
+		}
+		datacenters, err := vc.GetDatacenters(ctx)
+		if err != nil {
+			return nil, logger.LogNewErrorf(log, "failed to get the datacenters of vCenter %q. Error: %v",
+				vcConfig.Host, err)
+		}
+		for _, datacenter := range datacenters {
+			datastores, err := datacenter.GetAllDatastores(ctx)
+			if err != nil {
+				return nil, logger.LogNewErrorf(log, "failed to get the datastores of datacenter %q. Error: %v",
+					datacenter.InventoryPath, err)
+			}
+			for datastoreURL, datastore := range datastores {
+				inventory.datastores[datastoreURL] = datastore
+			}
+		}
+	}
+	log.Debugf("Listed %d storage policies and %d datastores from vCenter", len(inventory.storagePolicyNames),
+		len(inventory.datastores))
+	return inventory, nil
+}
+
+// volumeBacking is the backing of a volume retrieved from vCenter.
+type volumeBacking struct {
+	vcHost    string
+	datastore vimtypes.ManagedObjectReference
+	// provisioningType is the provisioning type of the virtual disk of the
+	// volume, e.g. "thin" or "eagerZeroedThick".
+	provisioningType string
+	retrieveTime     time.Time
+}
+
+// isThick returns whether the space of the whole virtual disk of the volume
+// is allocated in the datastore.
+func (b *volumeBacking) isThick() bool {
+	switch vimtypes.BaseConfigInfoDiskFileBackingInfoProvisioningType(b.provisioningType) {
+	case vimtypes.BaseConfigInfoDiskFileBackingInfoProvisioningTypeEagerZeroedThick,
+		vimtypes.BaseConfigInfoDiskFileBackingInfoProvisioningTypeLazyZeroedThick:
+		return true
+	}
+	return false
+}
+
+// volumeDatastoreLookup looks up the datastores of volumes in the datastores
+// the AuthManager of their vCenter computes for the placement of block
+// volumes. The backing of each volume is retrieved from vCenter at most once
+// per cache TTL.
+type volumeDatastoreLookup struct {
+	vcs      []*cnsvsphere.VirtualCenter
+	authMgrs map[string]*common.AuthManager
+	ttl      time.Duration
+	mutex    sync.Mutex
+	backings map[string]*volumeBacking
+}
+
+// newVolumeDatastoreLookup returns a volumeDatastoreLookup for the vCenter
+// servers of the vSphere config. The datastores of the AuthManagers are
+// refreshed every csi-auth-check-intervalinmin of the vSphere config.
+func newVolumeDatastoreLookup(ctx context.Context, cacheTTLInSec int) (*volumeDatastoreLookup, error) {
+	log := logger.GetLogger(ctx)
+	vsphereCfg, err := cnsconfig.GetConfig(ctx)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to read vSphere config. Error: %v", err)
+	}
+	vcConfigs, err := cnsvsphere.GetVirtualCenterConfigs(ctx, vsphereCfg)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to get vCenter configs. Error: %v", err)
+	}
+	vcManager := cnsvsphere.GetVirtualCenterManager(ctx)
+	var vcs []*cnsvsphere.VirtualCenter
+	for _, vcConfig := range vcConfigs {
+		vc, err := vcManager.GetVirtualCenter(ctx, vcConfig.Host)
+		if err != nil {
+			vc, err = vcManager.RegisterVirtualCenter(ctx, vcConfig)
+			if err != nil {
+				return nil, logger.LogNewErrorf(log, "failed to register vCenter %q. Error: %v", vcConfig.Host, err)
+			}
+		}
+		vcs = append(vcs, vc)
+	}
+	authMgrs, err := common.GetAuthorizationServices(ctx, vcs)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to initialize authMgr. Error: %v", err)
+	}
+	for _, authMgr := range authMgrs {
+		go common.ComputeDatastoreMapForBlockVolumes(authMgr, vsphereCfg.Global.CSIAuthCheckIntervalInMin)
+	}
+	if cacheTTLInSec <= 0 {
+		cacheTTLInSec = defaultVCenterLookupCacheTTLInSec
+	}
+	return &volumeDatastoreLookup{
+		vcs:      vcs,
+		authMgrs: authMgrs,
+		ttl:      time.Duration(cacheTTLInSec) * time.Second,
+		backings: make(map[string]*volumeBacking),
+	}, nil
+}
+
+// GetVolumeDatastore returns the datastore of the volume along with the
+// backing of the volume. The capacity of the datastore is as of the last
+// refresh of the AuthManager.
+func (l *volumeDatastoreLookup) GetVolumeDatastore(ctx context.Context,
+	volumeID string) (*cnsvsphere.DatastoreInfo, *volumeBacking, error) {
+	backing, err := l.getVolumeBacking(ctx, volumeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	authMgr, ok := l.authMgrs[backing.vcHost]
+	if !ok {
+		return nil, nil, fmt.Errorf("no AuthManager for vCenter %q of volume %q", backing.vcHost, volumeID)
+	}
+	for _, datastore := range authMgr.GetDatastoreMapForBlockVolumes(ctx) {
+		if datastore.Reference() == backing.datastore {
+			return datastore, backing, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("datastore %v of volume %q not found in the datastores of vCenter %q",
+		backing.datastore, volumeID, backing.vcHost)
+}
+
+// getVolumeBacking returns the cached backing of the volume, retrieving it
+// again from vCenter once expired.
+func (l *volumeDatastoreLookup) getVolumeBacking(ctx context.Context, volumeID string) (*volumeBacking, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if backing, ok := l.backings[volumeID]; ok && time.Since(backing.retrieveTime) < l.ttl {
+		return backing, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, vCenterLookupTimeout)
+	defer cancel()
+	backing, err := retrieveVolumeBacking(ctx, l.vcs, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	backing.retrieveTime = time.Now()
+	for id, cached := range l.backings {
+		if time.Since(cached.retrieveTime) >= l.ttl {
+			delete(l.backings, id)
+		}
+	}
+	l.backings[volumeID] = backing
+	return backing, nil
+}
+
+// _retrieveVolumeBacking retrieves the FCD of the volume from the vCenter
+// servers and returns its backing.
+func _retrieveVolumeBacking(ctx context.Context, vcs []*cnsvsphere.VirtualCenter,
+	volumeID string) (*volumeBacking, error) {
+	log := logger.GetLogger(ctx)
+	var errs []error
+	for _, vc := range vcs {
+		if err := vc.ConnectVslm(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to connect to vslm of vCenter %q. Error: %v", vc.Config.Host, err))
+			continue
+		}
+		globalObjectManager := vslm.NewGlobalObjectManager(vc.VslmClient)
+		vStorageObject, err := globalObjectManager.Retrieve(ctx, vimtypes.ID{Id: volumeID})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to retrieve volume %q in vCenter %q. Error: %v",
+				volumeID, vc.Config.Host, err))
+			continue
+		}
+		backingInfo, ok := vStorageObject.Config.Backing.(vimtypes.BaseBaseConfigInfoBackingInfo)
+		if !ok {
+			return nil, logger.LogNewErrorf(log, "volume %q has no backing in vCenter %q", volumeID, vc.Config.Host)
+		}
+		backing := &volumeBacking{
+			vcHost:    vc.Config.Host,
+			datastore: backingInfo.GetBaseConfigInfoBackingInfo().Datastore,
+		}
+		if diskBacking, ok := vStorageObject.Config.Backing.(*vimtypes.BaseConfigInfoDiskFileBackingInfo); ok {
+			backing.provisioningType = diskBacking.ProvisioningType
+		}
+		return backing, nil
+	}
+	return nil, logger.LogNewErrorf(log, "volume %q not found in vCenter. Errors: %v", volumeID, errors.Join(errs...))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhandler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+)
+
+func TestVCenterLookupCache(t *testing.T) {
+	ctx := context.Background()
+	listCount := 0
+	var listErr error
+	storagePolicyNames := []string{"gold"}
+	listVCenterInventory = func(ctx context.Context,
+		vcConfigs []*cnsvsphere.VirtualCenterConfig) (*vCenterInventory, error) {
+		listCount++
+		if listErr != nil {
+			return nil, listErr
+		}
+		return fakeVCenterInventory(storagePolicyNames, []string{"ds:///vmfs/volumes/ds1/"}), nil
+	}
+	defer func() {
+		listVCenterInventory = _listVCenterInventory
+	}()
+	lookup := &vCenterLookup{ttl: time.Hour}
+
+	found, err := lookup.StoragePolicyExists(ctx, "gold")
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = lookup.DatastoreExists(ctx, "ds:///vmfs/volumes/ds1/")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1, listCount, "inventory should be listed once")
+
+	// Objects missing from an inventory listed recently are not listed again.
+	storagePolicyNames = []string{"gold", "silver"}
+	found, err = lookup.StoragePolicyExists(ctx, "silver")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, 1, listCount)
+
+	// Objects missing from an older inventory are listed again.
+	lookup.listTime = time.Now().Add(-minVCenterLookupRefreshInterval)
+	found, err = lookup.StoragePolicyExists(ctx, "silver")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 2, listCount)
+
+	// An expired inventory is listed again.
+	lookup.listTime = time.Now().Add(-time.Hour)
+	listErr = errors.New("vCenter unreachable")
+	_, err = lookup.StoragePolicyExists(ctx, "gold")
+	assert.Error(t, err)
+	assert.Equal(t, 3, listCount)
+}
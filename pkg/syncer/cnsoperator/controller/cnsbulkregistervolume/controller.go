@@ -0,0 +1,709 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsbulkregistervolume
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"text/template"
+	"time"
+
+	cnstypes "github.com/vmware/govmomi/cns/types"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	apis "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator"
+	v1a1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsbulkregistervolume/v1alpha1"
+	cnsregistervolumev1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsregistervolume/v1alpha1"
+	volumes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/volume"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	commonconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
+	cnsoptypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/syncer/cnsoperator/types"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/syncer/cnsoperator/util"
+)
+
+const (
+	workerThreadEnvVar      = "WORKER_THREADS_BULK_REGISTER_VOLUME"
+	defaultMaxWorkerThreads = 2
+	// pollInterval is the interval at which the registration of the items is
+	// checked while the bulk import is in progress.
+	pollInterval = 10 * time.Second
+	// registrationTimeout is the time after which an item still being
+	// registered is marked as failed.
+	registrationTimeout = 30 * time.Minute
+	// bulkRegisterVolumeAnnotation is set on the CnsRegisterVolume instances
+	// created by a CnsBulkRegisterVolume to the <namespace>/<name> of the
+	// CnsBulkRegisterVolume.
+	bulkRegisterVolumeAnnotation = "cns.vmware.com/bulk-register-volume"
+)
+
+var (
+	// backOffDuration is a map of cnsbulkregistervolume name's to the time
+	// after which a request for this instance will be requeued.
+	// Initialized to 1 second for new instances and for instances whose latest
+	// reconcile operation succeeded.
+	// If the reconcile fails, backoff is incremented exponentially.
+	backOffDuration         map[types.NamespacedName]time.Duration
+	backOffDurationMapMutex = sync.Mutex{}
+
+	getVirtualCenter = cnsvsphere.GetVirtualCenterInstance
+)
+
+// Add creates a new CnsBulkRegisterVolume Controller and adds it to the
+// Manager, ConfigurationInfo and VirtualCenterTypes. The Manager will set
+// fields on the Controller and Start it when the Manager is Started.
+// The controller is only enabled in vanilla clusters as the users of the
+// supervisor namespaces must not be able to browse the datastores.
+func Add(mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
+	configInfo *commonconfig.ConfigurationInfo, volumeManager volumes.Manager) error {
+	ctx, log := logger.GetNewContextWithLogger()
+	if clusterFlavor != cnstypes.CnsClusterFlavorVanilla {
+		log.Debug("Not initializing the CnsBulkRegisterVolume Controller as its a non-Vanilla CSI deployment")
+		return nil
+	}
+	if len(configInfo.Cfg.VirtualCenter) > 1 {
+		log.Info("Not initializing the CnsBulkRegisterVolume Controller as multiple vCenters are configured")
+		return nil
+	}
+
+	// Initializes kubernetes client.
+	k8sclient, err := k8s.NewClient(ctx)
+	if err != nil {
+		log.Errorf("Creating Kubernetes client failed. Err: %v", err)
+		return err
+	}
+
+	// eventBroadcaster broadcasts events on CnsBulkRegisterVolume instances to
+	// the event sink.
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(
+		&typedcorev1.EventSinkImpl{
+			Interface: k8sclient.CoreV1().Events(""),
+		},
+	)
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: apis.GroupName})
+	return add(mgr, newReconciler(mgr, configInfo, volumeManager, recorder))
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager, configInfo *commonconfig.ConfigurationInfo,
+	volumeManager volumes.Manager, recorder record.EventRecorder) reconcile.Reconciler {
+	return &Reconciler{
+		client:        mgr.GetClient(),
+		scheme:        mgr.GetScheme(),
+		configInfo:    configInfo,
+		volumeManager: volumeManager,
+		recorder:      recorder,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler.
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	ctx, log := logger.GetNewContextWithLogger()
+
+	maxWorkerThreads := util.GetMaxWorkerThreads(ctx,
+		workerThreadEnvVar, defaultMaxWorkerThreads)
+	// Create a new controller.
+	err := ctrl.NewControllerManagedBy(mgr).Named("cnsbulkregistervolume-controller").
+		For(&v1a1.CnsBulkRegisterVolume{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxWorkerThreads}).
+		Complete(r)
+	if err != nil {
+		log.Errorf("Failed to build application controller. Err: %v", err)
+		return err
+	}
+
+	backOffDuration = make(map[types.NamespacedName]time.Duration)
+	return nil
+}
+
+// blank assignment to verify that Reconciler implements
+// reconcile.Reconciler.
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciler reconciles a CnsBulkRegisterVolume object.
+type Reconciler struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver.
+	client        client.Client
+	scheme        *runtime.Scheme
+	configInfo    *commonconfig.ConfigurationInfo
+	volumeManager volumes.Manager
+	recorder      record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a CnsBulkRegisterVolume
+// object and makes changes based on the state read and what is in the
+// CnsBulkRegisterVolume.Spec.
+// The virtual disks of the datastore are discovered in the first reconcile
+// and listed in the status items, skipping the ones attached to VMs. Then the
+// pending items are registered as FCDs, unless they already are, and a
+// CnsRegisterVolume is created for each of them in the target namespace, at
+// most MaxConcurrentRegistrations at a time, until all the items are either
+// Registered, Skipped or Failed. In dry run mode, the pending items are only
+// checked the same way before their registration.
+// Note:
+// The Controller will requeue the Request to be processed again if the
+// returned error is non-nil or Result.Requeue is true. Otherwise, upon
+// completion it will remove the work from the queue.
+func (r *Reconciler) Reconcile(ctx context.Context,
+	request reconcile.Request) (reconcile.Result, error) {
+	ctx = logger.NewContextWithLogger(ctx)
+	log := logger.GetLogger(ctx).With("name", request.NamespacedName)
+
+	// Fetch the CnsBulkRegisterVolume instance.
+	instance := &v1a1.CnsBulkRegisterVolume{}
+	err := r.client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("instance not found. Ignoring since it must be deleted.")
+			deleteBackoffEntry(ctx, request.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+
+		log.Error("Error reading the instance. ", err)
+		return reconcile.Result{}, err
+	}
+
+	if instance.Status.Completed {
+		log.Debug("instance is already completed")
+		deleteBackoffEntry(ctx, request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	log.Info("reconciling instance")
+	defer func() {
+		log.Info("finished reconciling instance")
+	}()
+
+	backoff := getBackoffDuration(ctx, request.NamespacedName)
+	log.Info("backoff duration is ", backoff)
+
+	tmpl, err := validateSpec(instance)
+	if err != nil {
+		// The spec is immutable, so the instance can't be fixed.
+		log.Error("invalid spec. ", err)
+		instance.Status.Completed = true
+		setInstanceError(ctx, r, instance, err.Error())
+		deleteBackoffEntry(ctx, request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	var location *datastoreLocation
+	if !instance.Status.Discovered || !instance.Spec.DryRun {
+		vc, err := getVirtualCenter(ctx, r.configInfo, false)
+		if err != nil {
+			log.Error("failed to get virtual center instance with error ", err)
+			setInstanceError(ctx, r, instance, "Unable to connect to VC for volume registration")
+			return reconcile.Result{RequeueAfter: backoff}, nil
+		}
+		location, err = findDatastore(ctx, vc, instance.Spec.DatastoreURL)
+		if err != nil {
+			setInstanceError(ctx, r, instance, err.Error())
+			return reconcile.Result{RequeueAfter: backoff}, nil
+		}
+	}
+
+	if !instance.Status.Discovered {
+		err = discoverVirtualDisks(ctx, instance, location, tmpl)
+		if err != nil {
+			setInstanceError(ctx, r, instance, err.Error())
+			return reconcile.Result{RequeueAfter: backoff}, nil
+		}
+	}
+
+	if instance.Spec.DryRun {
+		err = r.validateItems(ctx, instance)
+	} else {
+		err = r.registerItems(ctx, instance, location)
+	}
+	if err != nil {
+		log.Error("failed to reconcile with error ", err)
+		setInstanceError(ctx, r, instance, err.Error())
+		return reconcile.Result{RequeueAfter: backoff}, nil
+	}
+
+	inProgress := updateStatusCounts(&instance.Status)
+	instance.Status.Completed = instance.Spec.DryRun || !inProgress
+	instance.Status.Error = ""
+	err = k8s.UpdateStatus(ctx, r.client, instance)
+	if err != nil {
+		log.Warn("failed to update status with error ", err)
+		doubleBackoffDuration(ctx, request.NamespacedName)
+		return reconcile.Result{RequeueAfter: backoff}, nil
+	}
+
+	if !instance.Status.Completed {
+		updateBackoffEntry(ctx, request.NamespacedName, time.Second)
+		return reconcile.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	msg := fmt.Sprintf("registered %d, skipped %d and failed to register %d of the %d virtual disks",
+		instance.Status.Registered, instance.Status.Skipped, instance.Status.Failed, instance.Status.Total)
+	if instance.Spec.DryRun {
+		msg = fmt.Sprintf("dry run found %d of the %d virtual disks to register",
+			instance.Status.Total-instance.Status.Failed, instance.Status.Total)
+	}
+	recordEvent(ctx, r, instance, v1.EventTypeNormal, msg)
+	deleteBackoffEntry(ctx, request.NamespacedName)
+	log.Info(msg)
+	return reconcile.Result{}, nil
+}
+
+// discoverVirtualDisks lists the virtual disks of the datastore in the status
+// items of the instance, with the names of their PVCs. The virtual disks
+// attached to VMs are skipped.
+func discoverVirtualDisks(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume,
+	location *datastoreLocation, tmpl *template.Template) error {
+	log := logger.GetLogger(ctx)
+	disks, err := listVirtualDisks(ctx, location, instance.Spec.FolderPrefix)
+	if err != nil {
+		return err
+	}
+	items := make([]v1a1.BulkRegisterVolumeItem, 0, len(disks))
+	diskPathsByPVCName := make(map[string]string)
+	for index, disk := range disks {
+		item := v1a1.BulkRegisterVolumeItem{
+			DiskPath:     disk.path,
+			VolumeID:     disk.volumeID,
+			CapacityInMb: disk.capacityInMb,
+			Phase:        v1a1.ItemPending,
+		}
+		if disk.attachedVM != "" {
+			item.Phase = v1a1.ItemSkipped
+			item.Message = fmt.Sprintf("virtual disk is attached to VM %q", disk.attachedVM)
+			items = append(items, item)
+			continue
+		}
+		pvcName, err := getPVCName(tmpl, getDiskName(disk.path), index)
+		if err != nil {
+			item.Phase = v1a1.ItemFailed
+			item.Error = err.Error()
+		} else if otherDiskPath, exists := diskPathsByPVCName[pvcName]; exists {
+			item.PVCName = pvcName
+			item.Phase = v1a1.ItemFailed
+			item.Error = fmt.Sprintf("PVC name %q is already used by virtual disk %q", pvcName, otherDiskPath)
+		} else {
+			item.PVCName = pvcName
+			diskPathsByPVCName[pvcName] = disk.path
+		}
+		items = append(items, item)
+	}
+	instance.Status.Items = items
+	instance.Status.Discovered = true
+	log.Infof("Discovered %d virtual disks in folder %q of datastore %q", len(items),
+		instance.Spec.FolderPrefix, instance.Spec.DatastoreURL)
+	return nil
+}
+
+// validateItems checks the pending items as they would be before their
+// registration. It is used in dry run mode instead of registering the items.
+func (r *Reconciler) validateItems(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume) error {
+	for i := range instance.Status.Items {
+		item := &instance.Status.Items[i]
+		if item.Phase != v1a1.ItemPending {
+			continue
+		}
+		if _, err := r.checkItem(ctx, instance, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkItem skips the pending item if its FCD is already used by a PV and
+// fails it if its PVC already exists. It returns true if the item can be
+// registered.
+func (r *Reconciler) checkItem(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume,
+	item *v1a1.BulkRegisterVolumeItem) (bool, error) {
+	log := logger.GetLogger(ctx).With("diskPath", item.DiskPath)
+	if item.VolumeID != "" {
+		pvName, found, err := getPVNameForVolume(ctx, r.client, item.VolumeID)
+		if err != nil {
+			return false, fmt.Errorf("failed to look up the PV of volume %q. Error: %v", item.VolumeID, err)
+		}
+		if found {
+			log.Infof("Skipping the virtual disk as volume %q is already used by PV %q", item.VolumeID, pvName)
+			item.Phase = v1a1.ItemSkipped
+			item.Message = fmt.Sprintf("volume %q is already used by PV %q", item.VolumeID, pvName)
+			item.Error = ""
+			return false, nil
+		}
+	}
+	exists, err := r.pvcExists(ctx, instance.Spec.TargetNamespace, item.PVCName)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		setItemFailed(item, fmt.Sprintf("PVC %s/%s already exists", instance.Spec.TargetNamespace, item.PVCName))
+		return false, nil
+	}
+	return true, nil
+}
+
+// registerItems checks the items being registered and starts the
+// registration of the pending items, up to MaxConcurrentRegistrations items
+// being registered at a time.
+func (r *Reconciler) registerItems(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume,
+	location *datastoreLocation) error {
+	registering := 0
+	for i := range instance.Status.Items {
+		item := &instance.Status.Items[i]
+		if item.Phase != v1a1.ItemRegistering {
+			continue
+		}
+		if err := r.checkItemRegistration(ctx, instance, item); err != nil {
+			return err
+		}
+		if item.Phase == v1a1.ItemRegistering {
+			registering++
+		}
+	}
+
+	var batch []int
+	for i := range instance.Status.Items {
+		if registering+len(batch) >= getMaxConcurrentRegistrations(instance) {
+			break
+		}
+		if instance.Status.Items[i].Phase == v1a1.ItemPending {
+			batch = append(batch, i)
+		}
+	}
+	// The virtual disks registered as FCDs in a previous reconcile whose
+	// status update failed are found by path so that they aren't registered
+	// again.
+	var fcds map[string]virtualDisk
+	for _, index := range batch {
+		if instance.Status.Items[index].VolumeID == "" {
+			var err error
+			fcds, err = listFirstClassDisks(ctx, location, instance.Spec.FolderPrefix)
+			if err != nil {
+				return fmt.Errorf("failed to list the FCDs of datastore %q. Error: %v",
+					instance.Spec.DatastoreURL, err)
+			}
+			break
+		}
+	}
+	// Each goroutine only updates its own item.
+	var wg sync.WaitGroup
+	for _, index := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.startItemRegistration(ctx, instance, location, fcds, index)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// startItemRegistration checks the item at index, registers its virtual disk
+// as an FCD if it isn't one yet and creates a CnsRegisterVolume for it in the
+// target namespace.
+// The CnsRegisterVolume and the FCD created for the item in a previous
+// reconcile whose status update failed are reused.
+// Kubernetes API errors leave the item pending so it is retried in the next
+// reconcile.
+func (r *Reconciler) startItemRegistration(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume,
+	location *datastoreLocation, fcds map[string]virtualDisk, index int) {
+	item := &instance.Status.Items[index]
+	log := logger.GetLogger(ctx).With("diskPath", item.DiskPath)
+	adopted, err := r.adoptCnsRegisterVolume(ctx, instance, index)
+	if err != nil {
+		item.Error = err.Error()
+		return
+	}
+	if adopted {
+		return
+	}
+	if fcd, found := fcds[item.DiskPath]; found && item.VolumeID == "" {
+		log.Infof("Reusing FCD %q of the virtual disk", fcd.volumeID)
+		item.VolumeID = fcd.volumeID
+		item.CapacityInMb = fcd.capacityInMb
+	}
+	ok, err := r.checkItem(ctx, instance, item)
+	if err != nil {
+		item.Error = err.Error()
+		return
+	}
+	if !ok {
+		return
+	}
+
+	if item.VolumeID == "" {
+		diskURL, err := getDiskURL(location, item.DiskPath)
+		if err != nil {
+			setItemFailed(item, err.Error())
+			return
+		}
+		volumeID, err := r.volumeManager.RegisterDisk(ctx, diskURL, item.PVCName)
+		if err != nil {
+			setItemFailed(item, fmt.Sprintf("failed to register the virtual disk as FCD. Error: %v", err))
+			return
+		}
+		item.VolumeID = volumeID
+		vStorageObject, err := r.volumeManager.RetrieveVStorageObject(ctx, volumeID)
+		if err != nil {
+			setItemFailed(item, fmt.Sprintf("failed to retrieve FCD %q. Error: %v", volumeID, err))
+			return
+		}
+		if vStorageObject != nil {
+			item.CapacityInMb = vStorageObject.Config.CapacityInMB
+		}
+	}
+
+	cnsRegisterVolume := &cnsregistervolumev1alpha1.CnsRegisterVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getCnsRegisterVolumeName(instance, index),
+			Namespace: instance.Spec.TargetNamespace,
+			Annotations: map[string]string{
+				bulkRegisterVolumeAnnotation: instance.Namespace + "/" + instance.Name,
+			},
+		},
+		Spec: cnsregistervolumev1alpha1.CnsRegisterVolumeSpec{
+			PvcName:    item.PVCName,
+			VolumeID:   item.VolumeID,
+			AccessMode: v1.ReadWriteOnce,
+			VolumeMode: instance.Spec.VolumeMode,
+		},
+	}
+	err = r.client.Create(ctx, cnsRegisterVolume)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		item.Error = fmt.Sprintf("failed to create CnsRegisterVolume %s/%s. Error: %v",
+			cnsRegisterVolume.Namespace, cnsRegisterVolume.Name, err)
+		log.Error(item.Error)
+		return
+	}
+	log.Infof("Created CnsRegisterVolume %s/%s for volume %q", cnsRegisterVolume.Namespace,
+		cnsRegisterVolume.Name, item.VolumeID)
+	now := metav1.Now()
+	item.CnsRegisterVolumeName = cnsRegisterVolume.Name
+	item.StartTime = &now
+	item.Phase = v1a1.ItemRegistering
+	item.Error = ""
+}
+
+// adoptCnsRegisterVolume marks the item at index as being registered if its
+// CnsRegisterVolume already exists and returns true if it does. The item
+// fails if the CnsRegisterVolume wasn't created for this instance.
+func (r *Reconciler) adoptCnsRegisterVolume(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume,
+	index int) (bool, error) {
+	item := &instance.Status.Items[index]
+	log := logger.GetLogger(ctx).With("diskPath", item.DiskPath)
+	cnsRegisterVolume := &cnsregistervolumev1alpha1.CnsRegisterVolume{}
+	name := getCnsRegisterVolumeName(instance, index)
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: instance.Spec.TargetNamespace,
+		Name: name}, cnsRegisterVolume)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get CnsRegisterVolume %s/%s. Error: %v",
+			instance.Spec.TargetNamespace, name, err)
+	}
+	if cnsRegisterVolume.Annotations[bulkRegisterVolumeAnnotation] != instance.Namespace+"/"+instance.Name {
+		setItemFailed(item, fmt.Sprintf("CnsRegisterVolume %s/%s already exists and wasn't created for %s/%s",
+			instance.Spec.TargetNamespace, name, instance.Namespace, instance.Name))
+		return true, nil
+	}
+	log.Infof("Found CnsRegisterVolume %s/%s for volume %q", instance.Spec.TargetNamespace, name,
+		cnsRegisterVolume.Spec.VolumeID)
+	if item.VolumeID == "" {
+		item.VolumeID = cnsRegisterVolume.Spec.VolumeID
+	}
+	startTime := cnsRegisterVolume.CreationTimestamp
+	if startTime.IsZero() {
+		startTime = metav1.Now()
+	}
+	item.CnsRegisterVolumeName = name
+	item.StartTime = &startTime
+	item.Phase = v1a1.ItemRegistering
+	item.Error = ""
+	return true, nil
+}
+
+// checkItemRegistration updates the phase of an item being registered from
+// the status of its CnsRegisterVolume.
+func (r *Reconciler) checkItemRegistration(ctx context.Context, instance *v1a1.CnsBulkRegisterVolume,
+	item *v1a1.BulkRegisterVolumeItem) error {
+	log := logger.GetLogger(ctx).With("diskPath", item.DiskPath)
+	cnsRegisterVolume := &cnsregistervolumev1alpha1.CnsRegisterVolume{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: instance.Spec.TargetNamespace,
+		Name: item.CnsRegisterVolumeName}, cnsRegisterVolume)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// The registered CnsRegisterVolume instances are periodically
+		// cleaned up, so check the PVC instead.
+		pvc := &v1.PersistentVolumeClaim{}
+		err = r.client.Get(ctx, types.NamespacedName{Namespace: instance.Spec.TargetNamespace,
+			Name: item.PVCName}, pvc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && pvc.Status.Phase == v1.ClaimBound {
+			item.Phase = v1a1.ItemRegistered
+			item.Error = ""
+			return nil
+		}
+		setItemFailed(item, fmt.Sprintf("CnsRegisterVolume %q was deleted before PVC %q was bound",
+			item.CnsRegisterVolumeName, item.PVCName))
+		return nil
+	}
+	if cnsRegisterVolume.Status.Registered {
+		log.Infof("Registered volume %q as PVC %s/%s", item.VolumeID, instance.Spec.TargetNamespace, item.PVCName)
+		item.Phase = v1a1.ItemRegistered
+		item.Error = ""
+		return nil
+	}
+	item.Error = cnsRegisterVolume.Status.Error
+	if item.StartTime == nil || time.Since(item.StartTime.Time) < registrationTimeout {
+		return nil
+	}
+	// Delete the CnsRegisterVolume so that it isn't retried anymore.
+	err = r.client.Delete(ctx, cnsRegisterVolume)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	setItemFailed(item, fmt.Sprintf("timed out waiting for CnsRegisterVolume %q. Last error: %q",
+		item.CnsRegisterVolumeName, cnsRegisterVolume.Status.Error))
+	return nil
+}
+
+// pvcExists returns true if the PVC exists.
+func (r *Reconciler) pvcExists(ctx context.Context, namespace, name string) (bool, error) {
+	pvc := &v1.PersistentVolumeClaim{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pvc)
+	if err == nil {
+		return true, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to get PVC %s/%s. Error: %v", namespace, name, err)
+}
+
+// setItemFailed marks the item as failed with the error.
+func setItemFailed(item *v1a1.BulkRegisterVolumeItem, errMsg string) {
+	item.Phase = v1a1.ItemFailed
+	item.Error = errMsg
+}
+
+// updateStatusCounts updates the item counts of the status and returns true
+// if any item is still pending or being registered.
+func updateStatusCounts(status *v1a1.CnsBulkRegisterVolumeStatus) bool {
+	status.Total = len(status.Items)
+	status.Registered, status.Skipped, status.Failed = 0, 0, 0
+	inProgress := false
+	for _, item := range status.Items {
+		switch item.Phase {
+		case v1a1.ItemRegistered:
+			status.Registered++
+		case v1a1.ItemSkipped:
+			status.Skipped++
+		case v1a1.ItemFailed:
+			status.Failed++
+		default:
+			inProgress = true
+		}
+	}
+	return inProgress
+}
+
+// setInstanceError sets error and records an event on the
+// CnsBulkRegisterVolume instance.
+func setInstanceError(ctx context.Context, r *Reconciler,
+	instance *v1a1.CnsBulkRegisterVolume, errMsg string) {
+	instance.Status.Error = errMsg
+	_ = k8s.UpdateStatus(ctx, r.client, instance)
+	recordEvent(ctx, r, instance, v1.EventTypeWarning, errMsg)
+}
+
+// recordEvent records the event, sets the backOffDuration for the instance
+// appropriately and logs the message.
+// backOffDuration is reset to 1 second on success and doubled on failure
+// until it reaches a maximum of 5 minutes.
+func recordEvent(ctx context.Context, r *Reconciler,
+	instance *v1a1.CnsBulkRegisterVolume, eventtype string, msg string) {
+	log := logger.GetLogger(ctx)
+	log.Debugf("Event type is %s", eventtype)
+	namespacedName := types.NamespacedName{
+		Name:      instance.Name,
+		Namespace: instance.Namespace,
+	}
+	switch eventtype {
+	case v1.EventTypeWarning:
+		// Double backOff duration.
+		doubleBackoffDuration(ctx, namespacedName)
+		r.recorder.Event(instance, v1.EventTypeWarning, "CnsBulkRegisterVolumeFailed", msg)
+	case v1.EventTypeNormal:
+		// Reset backOff duration to one second.
+		updateBackoffEntry(ctx, namespacedName, time.Second)
+		r.recorder.Event(instance, v1.EventTypeNormal, "CnsBulkRegisterVolumeSucceeded", msg)
+	}
+}
+
+// getBackoffDuration returns the backoff duration for the instance.
+// If the instance is not present in the map, it is added with
+// a backoff duration of 1 second.
+func getBackoffDuration(ctx context.Context, name types.NamespacedName) time.Duration {
+	backOffDurationMapMutex.Lock()
+	defer backOffDurationMapMutex.Unlock()
+	if _, exists := backOffDuration[name]; !exists {
+		backOffDuration[name] = time.Second
+	}
+
+	return backOffDuration[name]
+}
+
+// doubleBackoffDuration doubles the backoff duration for the instance
+// until it reaches a maximum of 5 minutes.
+func doubleBackoffDuration(ctx context.Context, name types.NamespacedName) {
+	d := getBackoffDuration(ctx, name)
+	d = min(d*2, cnsoptypes.MaxBackOffDurationForReconciler)
+	updateBackoffEntry(ctx, name, d)
+}
+
+// updateBackoffEntry updates the backoff duration for the instance.
+func updateBackoffEntry(ctx context.Context, name types.NamespacedName, duration time.Duration) {
+	backOffDurationMapMutex.Lock()
+	defer backOffDurationMapMutex.Unlock()
+	backOffDuration[name] = duration
+}
+
+// deleteBackoffEntry deletes the backoff entry for the instance.
+func deleteBackoffEntry(ctx context.Context, name types.NamespacedName) {
+	backOffDurationMapMutex.Lock()
+	defer backOffDurationMapMutex.Unlock()
+	delete(backOffDuration, name)
+}
@@ -0,0 +1,430 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsbulkregistervolume
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	vim25types "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	apis "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator"
+	v1a1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsbulkregistervolume/v1alpha1"
+	cnsregistervolumev1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsregistervolume/v1alpha1"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/volume"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	commonconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+const (
+	testNamespace       = "test-ns"
+	testTargetNamespace = "target-ns"
+)
+
+var testLocation = &datastoreLocation{vcHost: "vc.example.com", datacenterPath: "/dc1"}
+
+// mockVolumeManager registers the virtual disks with the volume IDs of
+// volumeIDs, keyed by datastore path. The registration fails for the virtual
+// disks without a volume ID.
+type mockVolumeManager struct {
+	volume.Manager
+	mu         sync.Mutex
+	volumeIDs  map[string]string
+	registered []string
+}
+
+func (m *mockVolumeManager) RegisterDisk(ctx context.Context, path string, name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for diskPath, volumeID := range m.volumeIDs {
+		diskURL, _ := getDiskURL(testLocation, diskPath)
+		if diskURL == path && volumeID != "" {
+			m.registered = append(m.registered, diskPath)
+			return volumeID, nil
+		}
+	}
+	return "", errors.New("disk is locked")
+}
+
+func (m *mockVolumeManager) RetrieveVStorageObject(ctx context.Context,
+	volumeID string) (*vim25types.VStorageObject, error) {
+	return &vim25types.VStorageObject{Config: vim25types.VStorageObjectConfigInfo{CapacityInMB: 1024}}, nil
+}
+
+func TestGetDiskName(t *testing.T) {
+	tests := map[string]string{
+		"[vsanDatastore] legacy/app/App_Data 1.vmdk":                   "app-data-1",
+		"[vsanDatastore] db.vmdk":                                      "db",
+		"[vsanDatastore] legacy/___.vmdk":                              defaultDiskName,
+		"[vsanDatastore] legacy/a" + strings.Repeat("b", 80) + ".vmdk": "a" + strings.Repeat("b", 62),
+	}
+	for diskPath, expected := range tests {
+		assert.Equal(t, expected, getDiskName(diskPath), diskPath)
+	}
+}
+
+func TestIsInFolder(t *testing.T) {
+	assert.True(t, isInFolder("[ds1] legacy/app/disk.vmdk", "legacy"))
+	assert.True(t, isInFolder("[ds1] legacy/app/disk.vmdk", "legacy/app/"))
+	assert.True(t, isInFolder("[ds1] fcd/disk.vmdk", ""))
+	assert.False(t, isInFolder("[ds1] legacy-vms/disk.vmdk", "legacy"))
+	assert.False(t, isInFolder("legacy/disk.vmdk", "legacy"))
+}
+
+func TestValidateSpec(t *testing.T) {
+	newInstance := func(mutate func(spec *v1a1.CnsBulkRegisterVolumeSpec)) *v1a1.CnsBulkRegisterVolume {
+		instance := &v1a1.CnsBulkRegisterVolume{Spec: v1a1.CnsBulkRegisterVolumeSpec{
+			DatastoreURL:    "ds:///vmfs/volumes/ds1/",
+			TargetNamespace: testTargetNamespace,
+		}}
+		mutate(&instance.Spec)
+		return instance
+	}
+
+	tmpl, err := validateSpec(newInstance(func(spec *v1a1.CnsBulkRegisterVolumeSpec) {}))
+	require.NoError(t, err)
+	pvcName, err := getPVCName(tmpl, "app-1", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "app-1", pvcName)
+
+	tmpl, err = validateSpec(newInstance(func(spec *v1a1.CnsBulkRegisterVolumeSpec) {
+		spec.PVCNameTemplate = "legacy-{{.Index}}-{{.DiskName}}"
+	}))
+	require.NoError(t, err)
+	pvcName, err = getPVCName(tmpl, "app-1", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-3-app-1", pvcName)
+
+	for name, mutate := range map[string]func(spec *v1a1.CnsBulkRegisterVolumeSpec){
+		"missing datastore URL":    func(spec *v1a1.CnsBulkRegisterVolumeSpec) { spec.DatastoreURL = "" },
+		"invalid target namespace": func(spec *v1a1.CnsBulkRegisterVolumeSpec) { spec.TargetNamespace = "Target" },
+		"invalid volume mode":      func(spec *v1a1.CnsBulkRegisterVolumeSpec) { spec.VolumeMode = "Raw" },
+		"unparsable template":      func(spec *v1a1.CnsBulkRegisterVolumeSpec) { spec.PVCNameTemplate = "{{.DiskName" },
+		"unknown template field":   func(spec *v1a1.CnsBulkRegisterVolumeSpec) { spec.PVCNameTemplate = "{{.Name}}" },
+		"invalid PVC name":         func(spec *v1a1.CnsBulkRegisterVolumeSpec) { spec.PVCNameTemplate = "A_{{.Index}}" },
+	} {
+		_, err := validateSpec(newInstance(mutate))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGetDiskURL(t *testing.T) {
+	diskURL, err := getDiskURL(testLocation, "[vsan Datastore] legacy vm/disk 1.vmdk")
+	require.NoError(t, err)
+	assert.Equal(t, "https://vc.example.com/folder/legacy%20vm/disk%201.vmdk?dcPath=%2Fdc1&dsName=vsan%20Datastore",
+		diskURL)
+
+	_, err = getDiskURL(testLocation, "legacy/disk.vmdk")
+	assert.Error(t, err)
+}
+
+func TestReconcile(t *testing.T) {
+	getVirtualCenterOriginal := getVirtualCenter
+	findDatastoreOriginal := findDatastore
+	listVirtualDisksOriginal := listVirtualDisks
+	listFirstClassDisksOriginal := listFirstClassDisks
+	defer func() {
+		getVirtualCenter = getVirtualCenterOriginal
+		findDatastore = findDatastoreOriginal
+		listVirtualDisks = listVirtualDisksOriginal
+		listFirstClassDisks = listFirstClassDisksOriginal
+	}()
+	getVirtualCenter = func(ctx context.Context, configInfo *commonconfig.ConfigurationInfo,
+		reinitialize bool) (*cnsvsphere.VirtualCenter, error) {
+		return &cnsvsphere.VirtualCenter{}, nil
+	}
+	findDatastore = func(ctx context.Context, vc *cnsvsphere.VirtualCenter,
+		datastoreURL string) (*datastoreLocation, error) {
+		return testLocation, nil
+	}
+	disks := []virtualDisk{
+		{path: "[ds1] legacy/app_1.vmdk"},
+		{path: "[ds1] legacy/imported.vmdk", volumeID: "vol-imported", capacityInMb: 2048},
+		{path: "[ds1] legacy/taken.vmdk"},
+		{path: "[ds1] legacy/App-1.vmdk"},
+		{path: "[ds1] legacy/locked.vmdk"},
+		{path: "[ds1] legacy/vm/vm.vmdk", attachedVM: "vm"},
+		{path: "[ds1] legacy/fcd.vmdk", volumeID: "vol-fcd", capacityInMb: 2048},
+	}
+	listVirtualDisks = func(ctx context.Context, location *datastoreLocation, folder string) ([]virtualDisk, error) {
+		return disks, nil
+	}
+	// fcds are the FCDs of the datastore in addition to the discovered ones.
+	var fcds []virtualDisk
+	listFirstClassDisks = func(ctx context.Context, location *datastoreLocation,
+		folder string) (map[string]virtualDisk, error) {
+		result := make(map[string]virtualDisk)
+		for _, disk := range append(disks, fcds...) {
+			if disk.volumeID != "" {
+				result[disk.path] = disk
+			}
+		}
+		return result, nil
+	}
+	newVolumeManager := func() *mockVolumeManager {
+		return &mockVolumeManager{volumeIDs: map[string]string{
+			"[ds1] legacy/app_1.vmdk": "vol-app",
+			"[ds1] legacy/taken.vmdk": "vol-taken",
+			"[ds1] legacy/App-1.vmdk": "vol-app-dup",
+		}}
+	}
+	existingPV := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "imported-pv"},
+		Spec: v1.PersistentVolumeSpec{PersistentVolumeSource: v1.PersistentVolumeSource{
+			CSI: &v1.CSIPersistentVolumeSource{Driver: csitypes.Name, VolumeHandle: "vol-imported"},
+		}},
+	}
+	existingPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "taken", Namespace: testTargetNamespace},
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "import", Namespace: testNamespace}}
+
+	t.Run("registers the discovered virtual disks", func(t *testing.T) {
+		volumeManager := newVolumeManager()
+		r := newTestReconciler(t, volumeManager, newBulkInstance(false, 0), existingPV, existingPVC)
+
+		res, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, pollInterval, res.RequeueAfter)
+		instance := getBulkInstance(t, r, request)
+		assert.True(t, instance.Status.Discovered)
+		assert.False(t, instance.Status.Completed)
+		assertItemPhases(t, instance, v1a1.ItemRegistering, v1a1.ItemSkipped, v1a1.ItemFailed,
+			v1a1.ItemFailed, v1a1.ItemFailed, v1a1.ItemSkipped, v1a1.ItemRegistering)
+		assert.Equal(t, "app-1", instance.Status.Items[0].PVCName)
+		assert.Equal(t, "vol-app", instance.Status.Items[0].VolumeID)
+		assert.Equal(t, int64(1024), instance.Status.Items[0].CapacityInMb)
+		assert.Contains(t, instance.Status.Items[1].Message, "already used by PV")
+		assert.Contains(t, instance.Status.Items[2].Error, "already exists")
+		assert.Contains(t, instance.Status.Items[3].Error, "already used by virtual disk")
+		assert.Contains(t, instance.Status.Items[4].Error, "disk is locked")
+		assert.Contains(t, instance.Status.Items[5].Message, "attached to VM")
+		assert.Equal(t, "vol-fcd", instance.Status.Items[6].VolumeID)
+		assert.Equal(t, int64(2048), instance.Status.Items[6].CapacityInMb)
+		// The FCDs and the virtual disks whose PVC exists are not registered.
+		assert.Equal(t, []string{"[ds1] legacy/app_1.vmdk"}, volumeManager.registered)
+
+		cnsRegisterVolume := &cnsregistervolumev1alpha1.CnsRegisterVolume{}
+		require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{
+			Namespace: testTargetNamespace, Name: instance.Status.Items[0].CnsRegisterVolumeName}, cnsRegisterVolume))
+		assert.Equal(t, "vol-app", cnsRegisterVolume.Spec.VolumeID)
+		assert.Equal(t, "app-1", cnsRegisterVolume.Spec.PvcName)
+		assert.Equal(t, v1.ReadWriteOnce, cnsRegisterVolume.Spec.AccessMode)
+		assert.Equal(t, testNamespace+"/import", cnsRegisterVolume.Annotations[bulkRegisterVolumeAnnotation])
+
+		fcdCnsRegisterVolume := &cnsregistervolumev1alpha1.CnsRegisterVolume{}
+		require.NoError(t, r.client.Get(context.Background(), types.NamespacedName{Namespace: testTargetNamespace,
+			Name: instance.Status.Items[6].CnsRegisterVolumeName}, fcdCnsRegisterVolume))
+		assert.Equal(t, "vol-fcd", fcdCnsRegisterVolume.Spec.VolumeID)
+
+		// The items are registered once their CnsRegisterVolumes are.
+		for _, registerVolume := range []*cnsregistervolumev1alpha1.CnsRegisterVolume{cnsRegisterVolume,
+			fcdCnsRegisterVolume} {
+			registerVolume.Status.Registered = true
+			require.NoError(t, r.client.Status().Update(context.Background(), registerVolume))
+		}
+		res, err = r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+		instance = getBulkInstance(t, r, request)
+		assert.True(t, instance.Status.Completed)
+		assertItemPhases(t, instance, v1a1.ItemRegistered, v1a1.ItemSkipped, v1a1.ItemFailed,
+			v1a1.ItemFailed, v1a1.ItemFailed, v1a1.ItemSkipped, v1a1.ItemRegistered)
+		assert.Equal(t, 7, instance.Status.Total)
+		assert.Equal(t, 2, instance.Status.Registered)
+		assert.Equal(t, 2, instance.Status.Skipped)
+		assert.Equal(t, 3, instance.Status.Failed)
+	})
+
+	t.Run("dry run checks the virtual disks without registering them", func(t *testing.T) {
+		volumeManager := newVolumeManager()
+		r := newTestReconciler(t, volumeManager, newBulkInstance(true, 0), existingPV, existingPVC)
+
+		res, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+		instance := getBulkInstance(t, r, request)
+		assert.True(t, instance.Status.Completed)
+		assertItemPhases(t, instance, v1a1.ItemPending, v1a1.ItemSkipped, v1a1.ItemFailed,
+			v1a1.ItemFailed, v1a1.ItemPending, v1a1.ItemSkipped, v1a1.ItemPending)
+		assert.Empty(t, volumeManager.registered)
+		cnsRegisterVolumes := &cnsregistervolumev1alpha1.CnsRegisterVolumeList{}
+		require.NoError(t, r.client.List(context.Background(), cnsRegisterVolumes))
+		assert.Empty(t, cnsRegisterVolumes.Items)
+	})
+
+	t.Run("limits the concurrent registrations", func(t *testing.T) {
+		volumeManager := newVolumeManager()
+		volumeManager.volumeIDs["[ds1] legacy/locked.vmdk"] = "vol-locked"
+		r := newTestReconciler(t, volumeManager, newBulkInstance(false, 1))
+
+		_, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		instance := getBulkInstance(t, r, request)
+		assertItemPhases(t, instance, v1a1.ItemRegistering, v1a1.ItemPending, v1a1.ItemPending,
+			v1a1.ItemFailed, v1a1.ItemPending, v1a1.ItemSkipped, v1a1.ItemPending)
+
+		_, err = r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		instance = getBulkInstance(t, r, request)
+		assertItemPhases(t, instance, v1a1.ItemRegistering, v1a1.ItemPending, v1a1.ItemPending,
+			v1a1.ItemFailed, v1a1.ItemPending, v1a1.ItemSkipped, v1a1.ItemPending)
+	})
+
+	t.Run("reuses the FCDs and CnsRegisterVolumes of a failed status update", func(t *testing.T) {
+		fcds = []virtualDisk{{path: "[ds1] legacy/locked.vmdk", volumeID: "vol-locked", capacityInMb: 4096}}
+		defer func() { fcds = nil }()
+		instance := newBulkInstance(false, 0)
+		instance.Status.Discovered = true
+		instance.Status.Items = []v1a1.BulkRegisterVolumeItem{
+			{DiskPath: disks[0].path, PVCName: "app-1", Phase: v1a1.ItemPending},
+			{DiskPath: disks[4].path, PVCName: "locked", Phase: v1a1.ItemPending},
+			{DiskPath: disks[2].path, PVCName: "foreign", Phase: v1a1.ItemPending},
+		}
+		created := &cnsregistervolumev1alpha1.CnsRegisterVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "import-0", Namespace: testTargetNamespace,
+				Annotations: map[string]string{bulkRegisterVolumeAnnotation: testNamespace + "/import"}},
+			Spec: cnsregistervolumev1alpha1.CnsRegisterVolumeSpec{PvcName: "app-1", VolumeID: "vol-app"},
+		}
+		// The PVC of an adopted CnsRegisterVolume may already exist.
+		createdPVC := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: testTargetNamespace},
+		}
+		foreign := &cnsregistervolumev1alpha1.CnsRegisterVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "import-2", Namespace: testTargetNamespace},
+		}
+		volumeManager := newVolumeManager()
+		r := newTestReconciler(t, volumeManager, instance, created, createdPVC, foreign)
+
+		_, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		instance = getBulkInstance(t, r, request)
+		assertItemPhases(t, instance, v1a1.ItemRegistering, v1a1.ItemRegistering, v1a1.ItemFailed)
+		assert.Empty(t, volumeManager.registered)
+		assert.Equal(t, "vol-app", instance.Status.Items[0].VolumeID)
+		assert.Equal(t, "import-0", instance.Status.Items[0].CnsRegisterVolumeName)
+		assert.Equal(t, "vol-locked", instance.Status.Items[1].VolumeID)
+		assert.Equal(t, int64(4096), instance.Status.Items[1].CapacityInMb)
+		assert.Contains(t, instance.Status.Items[2].Error, "wasn't created for")
+	})
+
+	t.Run("checks the items being registered", func(t *testing.T) {
+		instance := newBulkInstance(false, 0)
+		instance.Status.Discovered = true
+		startTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		instance.Status.Items = []v1a1.BulkRegisterVolumeItem{
+			{DiskPath: disks[0].path, PVCName: "timed-out", CnsRegisterVolumeName: "import-0",
+				Phase: v1a1.ItemRegistering, StartTime: &startTime},
+			{DiskPath: disks[1].path, PVCName: "bound", CnsRegisterVolumeName: "import-1",
+				Phase: v1a1.ItemRegistering, StartTime: &startTime},
+			{DiskPath: disks[2].path, PVCName: "unbound", CnsRegisterVolumeName: "import-2",
+				Phase: v1a1.ItemRegistering, StartTime: &startTime},
+		}
+		timedOut := &cnsregistervolumev1alpha1.CnsRegisterVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "import-0", Namespace: testTargetNamespace},
+			Status:     cnsregistervolumev1alpha1.CnsRegisterVolumeStatus{Error: "datastore is not accessible"},
+		}
+		bound := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "bound", Namespace: testTargetNamespace},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+		}
+		r := newTestReconciler(t, newVolumeManager(), instance, timedOut, bound)
+
+		res, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+		instance = getBulkInstance(t, r, request)
+		assertItemPhases(t, instance, v1a1.ItemFailed, v1a1.ItemRegistered, v1a1.ItemFailed)
+		assert.Contains(t, instance.Status.Items[0].Error, "datastore is not accessible")
+		err = r.client.Get(context.Background(), client.ObjectKeyFromObject(timedOut), timedOut)
+		assert.True(t, apierrors.IsNotFound(err), "the timed out CnsRegisterVolume should be deleted")
+	})
+
+	t.Run("invalid spec completes the instance", func(t *testing.T) {
+		instance := newBulkInstance(false, 0)
+		instance.Spec.PVCNameTemplate = "{{.Name}}"
+		r := newTestReconciler(t, newVolumeManager(), instance)
+
+		res, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+		instance = getBulkInstance(t, r, request)
+		assert.True(t, instance.Status.Completed)
+		assert.Contains(t, instance.Status.Error, "invalid pvcNameTemplate")
+	})
+}
+
+func newTestReconciler(t *testing.T, volumeManager volume.Manager, objs ...client.Object) *Reconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	schemeBuilder := runtime.NewSchemeBuilder(apis.AddToScheme, v1.AddToScheme)
+	require.NoError(t, schemeBuilder.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&v1a1.CnsBulkRegisterVolume{}, &cnsregistervolumev1alpha1.CnsRegisterVolume{}).
+		Build()
+	backOffDuration = make(map[types.NamespacedName]time.Duration)
+	return &Reconciler{
+		client:        c,
+		recorder:      record.NewFakeRecorder(100),
+		volumeManager: volumeManager,
+	}
+}
+
+func newBulkInstance(dryRun bool, maxConcurrentRegistrations int) *v1a1.CnsBulkRegisterVolume {
+	return &v1a1.CnsBulkRegisterVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "import", Namespace: testNamespace},
+		Spec: v1a1.CnsBulkRegisterVolumeSpec{
+			DatastoreURL:               "ds:///vmfs/volumes/ds1/",
+			FolderPrefix:               "legacy",
+			TargetNamespace:            testTargetNamespace,
+			MaxConcurrentRegistrations: maxConcurrentRegistrations,
+			DryRun:                     dryRun,
+		},
+	}
+}
+
+func getBulkInstance(t *testing.T, r *Reconciler, request reconcile.Request) *v1a1.CnsBulkRegisterVolume {
+	t.Helper()
+	instance := &v1a1.CnsBulkRegisterVolume{}
+	require.NoError(t, r.client.Get(context.Background(), request.NamespacedName, instance))
+	return instance
+}
+
+func assertItemPhases(t *testing.T, instance *v1a1.CnsBulkRegisterVolume,
+	phases ...v1a1.BulkRegisterVolumeItemPhase) {
+	t.Helper()
+	require.Len(t, instance.Status.Items, len(phases))
+	for i, phase := range phases {
+		assert.Equal(t, phase, instance.Status.Items[i].Phase, "phase of item %d (%s). Error: %s", i,
+			instance.Status.Items[i].DiskPath, instance.Status.Items[i].Error)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsbulkregistervolume
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/vmware/govmomi/object"
+	vimtypes "github.com/vmware/govmomi/vim25/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	v1a1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsbulkregistervolume/v1alpha1"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+const (
+	defaultPVCNameTemplate            = "{{.DiskName}}"
+	defaultMaxConcurrentRegistrations = 10
+	// defaultDiskName is used for the virtual disks without any character
+	// allowed in a DNS-1123 label.
+	defaultDiskName = "disk"
+)
+
+// pvcNameTemplateData is the data the PVCNameTemplate is executed with.
+type pvcNameTemplateData struct {
+	DiskName string
+	Index    int
+}
+
+// datastoreLocation is the location of the datastore the virtual disks are
+// imported from.
+type datastoreLocation struct {
+	vcHost         string
+	datacenterPath string
+	datastore      *cnsvsphere.Datastore
+}
+
+// virtualDisk is a virtual disk discovered in the datastore.
+type virtualDisk struct {
+	path string
+	// volumeID is the ID of the FCD of the virtual disk, if it is already
+	// registered as an FCD.
+	volumeID     string
+	capacityInMb int64
+	// attachedVM is the name of the VM the virtual disk is attached to, if
+	// any.
+	attachedVM string
+}
+
+var (
+	findDatastore       = _findDatastore
+	listVirtualDisks    = _listVirtualDisks
+	listFirstClassDisks = _listFirstClassDisks
+)
+
+// validateSpec validates the spec of the CnsBulkRegisterVolume instance and
+// returns the parsed PVC name template.
+func validateSpec(instance *v1a1.CnsBulkRegisterVolume) (*template.Template, error) {
+	spec := instance.Spec
+	if spec.DatastoreURL == "" {
+		return nil, errors.New("datastoreURL must be specified")
+	}
+	if errs := validation.IsDNS1123Label(spec.TargetNamespace); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid targetNamespace %q: %s", spec.TargetNamespace, strings.Join(errs, ", "))
+	}
+	if spec.VolumeMode != "" && spec.VolumeMode != v1.PersistentVolumeFilesystem &&
+		spec.VolumeMode != v1.PersistentVolumeBlock {
+		return nil, fmt.Errorf("invalid volumeMode %q", spec.VolumeMode)
+	}
+	if spec.MaxConcurrentRegistrations < 0 {
+		return nil, fmt.Errorf("invalid maxConcurrentRegistrations %d", spec.MaxConcurrentRegistrations)
+	}
+	nameTemplate := spec.PVCNameTemplate
+	if nameTemplate == "" {
+		nameTemplate = defaultPVCNameTemplate
+	}
+	tmpl, err := template.New("pvcName").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pvcNameTemplate %q: %v", nameTemplate, err)
+	}
+	if _, err := getPVCName(tmpl, defaultDiskName, 0); err != nil {
+		return nil, fmt.Errorf("invalid pvcNameTemplate %q: %v", nameTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// getMaxConcurrentRegistrations returns the maximum number of virtual disks
+// registered at the same time.
+func getMaxConcurrentRegistrations(instance *v1a1.CnsBulkRegisterVolume) int {
+	if instance.Spec.MaxConcurrentRegistrations > 0 {
+		return instance.Spec.MaxConcurrentRegistrations
+	}
+	return defaultMaxConcurrentRegistrations
+}
+
+// getPVCName executes the PVC name template for the virtual disk and
+// validates the resulting name.
+func getPVCName(tmpl *template.Template, diskName string, index int) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pvcNameTemplateData{DiskName: diskName, Index: index}); err != nil {
+		return "", err
+	}
+	pvcName := buf.String()
+	if errs := validation.IsDNS1123Subdomain(pvcName); len(errs) > 0 {
+		return "", fmt.Errorf("invalid PVC name %q: %s", pvcName, strings.Join(errs, ", "))
+	}
+	return pvcName, nil
+}
+
+// getDiskName returns the name of the virtual disk file at the datastore path
+// without the ".vmdk" extension, converted to a DNS-1123 label.
+func getDiskName(diskPath string) string {
+	var dsPath object.DatastorePath
+	if dsPath.FromString(diskPath) {
+		diskPath = dsPath.Path
+	}
+	name := strings.ToLower(strings.TrimSuffix(path.Base(diskPath), ".vmdk"))
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, name)
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = name[:validation.DNS1123LabelMaxLength]
+	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return defaultDiskName
+	}
+	return name
+}
+
+// getCnsRegisterVolumeName returns the name of the CnsRegisterVolume created
+// for the item at index.
+func getCnsRegisterVolumeName(instance *v1a1.CnsBulkRegisterVolume, index int) string {
+	return fmt.Sprintf("%s-%d", instance.Name, index)
+}
+
+// getDiskURL returns the URL of the virtual disk at the datastore path in the
+// format expected by RegisterDisk:
+// https://<vc_ip>/folder/<vm_vmdk_path>?dcPath=<datacenter-path>&dsName=<datastoreName>
+func getDiskURL(location *datastoreLocation, diskPath string) (string, error) {
+	var dsPath object.DatastorePath
+	if !dsPath.FromString(diskPath) || dsPath.Path == "" {
+		return "", fmt.Errorf("invalid virtual disk path %q", diskPath)
+	}
+	segments := strings.Split(dsPath.Path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "https://" + location.vcHost + "/folder/" + strings.Join(segments, "/") +
+		"?dcPath=" + url.PathEscape(location.datacenterPath) + "&dsName=" + url.PathEscape(dsPath.Datastore), nil
+}
+
+// _findDatastore returns the location of the datastore with the given URL in
+// the vCenter.
+func _findDatastore(ctx context.Context, vc *cnsvsphere.VirtualCenter,
+	datastoreURL string) (*datastoreLocation, error) {
+	log := logger.GetLogger(ctx)
+	datacenters, err := vc.GetDatacenters(ctx)
+	if err != nil {
+		return nil, logger.LogNewErrorf(log, "failed to get the datacenters of vCenter %q. Error: %v",
+			vc.Config.Host, err)
+	}
+	for _, datacenter := range datacenters {
+		dsInfo, err := datacenter.GetDatastoreInfoByURL(ctx, datastoreURL)
+		if err != nil {
+			log.Debugf("datastore %q not found in datacenter %q. Error: %v", datastoreURL,
+				datacenter.InventoryPath, err)
+			continue
+		}
+		return &datastoreLocation{
+			vcHost:         vc.Config.Host,
+			datacenterPath: datacenter.InventoryPath,
+			datastore:      dsInfo.Datastore,
+		}, nil
+	}
+	return nil, logger.LogNewErrorf(log, "datastore %q not found in vCenter %q", datastoreURL, vc.Config.Host)
+}
+
+// _listVirtualDisks returns the virtual disks in the folder of the datastore,
+// sorted by path. The FCDs of the datastore are retrieved from vCenter so
+// that they are imported with their existing ID, and the virtual disks
+// attached to VMs are reported with their VM so that they are not imported.
+// The datastore is still browsed for the virtual disks which are not FCDs.
+func _listVirtualDisks(ctx context.Context, location *datastoreLocation, folder string) ([]virtualDisk, error) {
+	diskPaths, err := location.datastore.ListVirtualDiskPaths(ctx, folder)
+	if err != nil {
+		return nil, err
+	}
+	disks := make(map[string]*virtualDisk, len(diskPaths))
+	for _, diskPath := range diskPaths {
+		disks[diskPath] = &virtualDisk{path: diskPath}
+	}
+	fcds, err := listFirstClassDisks(ctx, location, folder)
+	if err != nil {
+		return nil, err
+	}
+	for diskPath, fcd := range fcds {
+		disks[diskPath] = &fcd
+	}
+	attachedDiskPaths, err := location.datastore.GetAttachedVirtualDiskPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]virtualDisk, 0, len(disks))
+	for diskPath, disk := range disks {
+		disk.attachedVM = attachedDiskPaths[diskPath]
+		result = append(result, *disk)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].path < result[j].path
+	})
+	return result, nil
+}
+
+// _listFirstClassDisks returns the FCDs in the folder of the datastore, by the
+// path of their virtual disk.
+func _listFirstClassDisks(ctx context.Context, location *datastoreLocation,
+	folder string) (map[string]virtualDisk, error) {
+	log := logger.GetLogger(ctx)
+	fcds, err := location.datastore.ListFirstClassDisks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	disks := make(map[string]virtualDisk)
+	for _, fcd := range fcds {
+		backing, ok := fcd.Config.Backing.(vimtypes.BaseBaseConfigInfoFileBackingInfo)
+		if !ok {
+			log.Debugf("ignoring FCD %q without file backing", fcd.Config.Id.Id)
+			continue
+		}
+		diskPath := backing.GetBaseConfigInfoFileBackingInfo().FilePath
+		if !isInFolder(diskPath, folder) {
+			continue
+		}
+		disks[diskPath] = virtualDisk{path: diskPath, volumeID: fcd.Config.Id.Id,
+			capacityInMb: fcd.Config.CapacityInMB}
+	}
+	return disks, nil
+}
+
+// isInFolder returns true if the datastore path is in the folder or one of its
+// sub folders.
+func isInFolder(diskPath string, folder string) bool {
+	var dsPath object.DatastorePath
+	if !dsPath.FromString(diskPath) {
+		return false
+	}
+	folder = strings.Trim(folder, "/")
+	return folder == "" || strings.HasPrefix(dsPath.Path, folder+"/")
+}
+
+// getPVNameForVolume returns the name of the vSphere CSI PV of the volume, if
+// any.
+func getPVNameForVolume(ctx context.Context, c client.Client, volumeID string) (string, bool, error) {
+	pvs := &v1.PersistentVolumeList{}
+	if err := c.List(ctx, pvs); err != nil {
+		return "", false, err
+	}
+	for _, pv := range pvs.Items {
+		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == csitypes.Name && pv.Spec.CSI.VolumeHandle == volumeID {
+			return pv.Name, true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -28,6 +28,7 @@ import (
 
 	cnstypes "github.com/vmware/govmomi/cns/types"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -104,8 +105,18 @@ var (
 func Add(mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
 	configInfo *commonconfig.ConfigurationInfo, volumeManager volumes.Manager) error {
 	ctx, log := logger.GetNewContextWithLogger()
+	if clusterFlavor == cnstypes.CnsClusterFlavorVanilla {
+		if len(configInfo.Cfg.VirtualCenter) > 1 {
+			log.Info("Not initializing the CnsRegisterVolume Controller as multiple vCenters are configured")
+			return nil
+		}
+		if err := initVanilla(ctx, configInfo); err != nil {
+			return err
+		}
+		return addWithRecorder(ctx, mgr, clusterFlavor, configInfo, volumeManager, nil)
+	}
 	if clusterFlavor != cnstypes.CnsClusterFlavorWorkload {
-		log.Debug("Not initializing the CnsRegisterVolume Controller as its a non-WCP CSI deployment")
+		log.Debug("Not initializing the CnsRegisterVolume Controller as its a non-WCP non-Vanilla CSI deployment")
 		return nil
 	}
 	workloadDomainIsolationEnabled = commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx,
@@ -151,6 +162,16 @@ func Add(mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
 			return logger.LogNewErrorf(log, "failed to start zone informer. Error: %v", err)
 		}
 	}
+	return addWithRecorder(ctx, mgr, clusterFlavor, configInfo, volumeManager, volumeInfoService)
+}
+
+// addWithRecorder creates the event recorder and the reconciler of the
+// CnsRegisterVolume Controller for the cluster flavor and adds the Controller
+// to mgr.
+func addWithRecorder(ctx context.Context, mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
+	configInfo *commonconfig.ConfigurationInfo, volumeManager volumes.Manager,
+	volumeInfoService cnsvolumeinfo.VolumeInfoService) error {
+	log := logger.GetLogger(ctx)
 	// Initializes kubernetes client.
 	k8sclient, err := k8s.NewClient(ctx)
 	if err != nil {
@@ -167,7 +188,7 @@ func Add(mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
 		},
 	)
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: apis.GroupName})
-	reconciler, err := newReconciler(mgr, configInfo, volumeManager, recorder, volumeInfoService)
+	reconciler, err := newReconciler(mgr, clusterFlavor, configInfo, volumeManager, recorder, volumeInfoService)
 	if err != nil {
 		log.Errorf("Failed to create reconciler. Err: %v", err)
 		return err
@@ -176,8 +197,8 @@ func Add(mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
 }
 
 // newReconciler returns a new reconcile.Reconciler.
-func newReconciler(mgr manager.Manager, configInfo *commonconfig.ConfigurationInfo,
-	volumeManager volumes.Manager, recorder record.EventRecorder,
+func newReconciler(mgr manager.Manager, clusterFlavor cnstypes.CnsClusterFlavor,
+	configInfo *commonconfig.ConfigurationInfo, volumeManager volumes.Manager, recorder record.EventRecorder,
 	volumeInfoService cnsvolumeinfo.VolumeInfoService) (reconcile.Reconciler, error) {
 	ctx, log := logger.GetNewContextWithLogger()
 	k8sclient, err := k8s.NewClient(ctx)
@@ -187,7 +208,7 @@ func newReconciler(mgr manager.Manager, configInfo *commonconfig.ConfigurationIn
 	}
 	return &ReconcileCnsRegisterVolume{client: mgr.GetClient(), scheme: mgr.GetScheme(),
 		configInfo: configInfo, volumeManager: volumeManager, recorder: recorder,
-		volumeInfoService: volumeInfoService, k8sclient: k8sclient}, nil
+		volumeInfoService: volumeInfoService, k8sclient: k8sclient, clusterFlavor: clusterFlavor}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
@@ -226,6 +247,7 @@ type ReconcileCnsRegisterVolume struct {
 	recorder          record.EventRecorder
 	volumeInfoService cnsvolumeinfo.VolumeInfoService
 	k8sclient         clientset.Interface
+	clusterFlavor     cnstypes.CnsClusterFlavor
 }
 
 // Reconcile reads that state of the cluster for a CnsRegisterVolume object
@@ -338,7 +360,11 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 		// if CNS Register Volume Instance is created with diskURLPath,
 		// confirm using CNS Volume ID, if another PV is already present with same volume ID
 		// If yes then fail.
-		pvName, found := commonco.ContainerOrchestratorUtility.GetPVNameFromCSIVolumeID(volInfo.VolumeID.Id)
+		pvName, found, err := getPVNameFromCSIVolumeID(ctx, volInfo.VolumeID.Id)
+		if err != nil {
+			setInstanceError(ctx, r, instance, err.Error())
+			return reconcile.Result{RequeueAfter: timeout}, nil
+		}
 		if found {
 			if pvName != staticPvNamePrefix+volInfo.VolumeID.Id {
 				msg := fmt.Sprintf("PV: %q with the volume ID: %q for volume path: %q"+
@@ -387,8 +413,9 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 	// hosts" gate and the intersection-based topology computation are replaced with a node-driven
 	// resolution below. When the capability is off or the policy is not host-local, isHostLocal
 	// stays false and the existing behavior is preserved.
+	isVanilla := r.clusterFlavor == cnstypes.CnsClusterFlavorVanilla
 	isHostLocal := false
-	if commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.HostLocalStorageSupport) {
+	if !isVanilla && commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.HostLocalStorageSupport) {
 		isHostLocal, err = vc.IsHostLocalStoragePolicy(ctx, volume.StoragePolicyId)
 		if err != nil {
 			msg := fmt.Sprintf("failed to determine host-local status for storage policy %s: %+v",
@@ -403,8 +430,29 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 	// populated by the node-driven resolver below; otherwise it is computed later from shared
 	// datastores in the IsPodVMOnStretchSupervisorFSSEnabled block.
 	var datastoreAccessibleTopology []map[string]string
-
-	if isHostLocal {
+	// accessibleNodeNames holds the names of the nodes with access to the volume in vanilla clusters.
+	var accessibleNodeNames []string
+
+	if isVanilla {
+		// Vanilla clusters have no AZ clusters. Verify the volume is accessible to the nodes the
+		// cluster topology requires and get the topology segments of the PV node affinity.
+		accessibleNodeNames, datastoreAccessibleTopology, err = getVanillaVolumeAccessibility(ctx, vc,
+			volume.DatastoreUrl)
+		if err != nil {
+			msg := fmt.Sprintf("failed to verify the accessibility of volume %s. Error: %v", volumeID, err)
+			log.Error(msg)
+			setInstanceError(ctx, r, instance, msg)
+			if !errors.Is(err, errVolumeNotAccessible) {
+				return reconcile.Result{RequeueAfter: timeout}, nil
+			}
+			if err = r.cleanupCNSVolume(ctx, instance, volumeID); err != nil {
+				log.Errorf("Failed to cleanup CNS volume: %s with error: %+v", volumeID, err)
+				return reconcile.Result{RequeueAfter: timeout}, nil
+			}
+			// permanent failure and not requeue.
+			return reconcile.Result{}, nil
+		}
+	} else if isHostLocal {
 		// Bypass the shared-datastore "accessible to all hosts" gate. Build the clusterMoID -> zone
 		// map (inverted AvailabilityZone map) used both to discover the candidate clusters that may
 		// hold the datastore and to derive the zone from the host's cluster.
@@ -536,8 +584,8 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 		// permanent failure and not requeue.
 		return reconcile.Result{}, nil
 	}
-	// Verify if storage policy is empty.
-	if volume.StoragePolicyId == "" {
+	// Verify if storage policy is empty. Volumes registered in vanilla clusters may not have a policy.
+	if !isVanilla && volume.StoragePolicyId == "" {
 		log.Errorf("Volume: %s doesn't have storage policy associated with it", volumeID)
 		setInstanceError(ctx, r, instance, "Volume in the spec doesn't have storage policy associated with it")
 		if err = r.cleanupCNSVolume(ctx, instance, volumeID); err != nil {
@@ -549,32 +597,41 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 	// Use cached K8s client for registration operations.
 	k8sclient := r.k8sclient
 
-	// Get K8S storageclass name mapping the storagepolicy id with Immediate volume binding mode
-	storageClassName, err := getK8sStorageClassNameWithImmediateBindingModeForPolicy(ctx, k8sclient, r.client,
-		volume.StoragePolicyId, request.Namespace, syncer.IsPodVMOnStretchSupervisorFSSEnabled)
-	if err != nil {
-		msg := fmt.Sprintf("Failed to find K8S Storageclass mapping storagepolicyId: %s and assigned to namespace: %s",
-			volume.StoragePolicyId, request.Namespace)
-		log.Error(msg)
-		setInstanceError(ctx, r, instance, msg)
-		return reconcile.Result{RequeueAfter: timeout}, nil
-	}
-	log.Infof("Volume with storagepolicyId: %s is mapping to K8S storage class: %s and assigned to namespace: %s",
-		volume.StoragePolicyId, storageClassName, request.Namespace)
+	// Vanilla clusters don't map the storage policies to StorageClasses. The PV gets the StorageClass of
+	// the existing PVC, if any, so that they can bind.
+	var (
+		storageClassName string
+		sc               *storagev1.StorageClass
+	)
+	if !isVanilla {
+		// Get K8S storageclass name mapping the storagepolicy id with Immediate volume binding mode
+		storageClassName, err = getK8sStorageClassNameWithImmediateBindingModeForPolicy(ctx, k8sclient,
+			r.client, volume.StoragePolicyId, request.Namespace, syncer.IsPodVMOnStretchSupervisorFSSEnabled)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to find K8S Storageclass mapping storagepolicyId: %s and assigned to "+
+				"namespace: %s", volume.StoragePolicyId, request.Namespace)
+			log.Error(msg)
+			setInstanceError(ctx, r, instance, msg)
+			return reconcile.Result{RequeueAfter: timeout}, nil
+		}
+		log.Infof("Volume with storagepolicyId: %s is mapping to K8S storage class: %s and assigned to "+
+			"namespace: %s", volume.StoragePolicyId, storageClassName, request.Namespace)
 
-	sc, err := k8sclient.StorageV1().StorageClasses().Get(ctx, storageClassName, metav1.GetOptions{})
-	if err != nil {
-		msg := fmt.Sprintf("Failed to fetch StorageClass: %q with error: %+v", storageClassName, err)
-		log.Error(msg)
-		setInstanceError(ctx, r, instance, msg)
-		return reconcile.Result{RequeueAfter: timeout}, nil
+		sc, err = k8sclient.StorageV1().StorageClasses().Get(ctx, storageClassName, metav1.GetOptions{})
+		if err != nil {
+			msg := fmt.Sprintf("Failed to fetch StorageClass: %q with error: %+v", storageClassName, err)
+			log.Error(msg)
+			setInstanceError(ctx, r, instance, msg)
+			return reconcile.Result{RequeueAfter: timeout}, nil
+		}
 	}
 
 	// Calculate accessible topology for the provisioned volume. datastoreAccessibleTopology is
 	// declared earlier and, for host-local volumes, is already populated by the node-driven
 	// resolver, so build the PV node affinity from it directly (covering all configurations,
-	// including non-stretched supervisors). Otherwise fall back to the shared-datastore topology.
-	if isHostLocal {
+	// including non-stretched supervisors). The same applies to vanilla clusters, where it is populated
+	// from the nodes with access to the datastore. Otherwise fall back to the shared-datastore topology.
+	if isVanilla || isHostLocal {
 		pvNodeAffinity = buildNodeAffinityFromSegments(datastoreAccessibleTopology)
 	} else if syncer.IsPodVMOnStretchSupervisorFSSEnabled {
 		if workloadDomainIsolationEnabled {
@@ -708,7 +765,22 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 			instance.Spec.PvcName)
 
 		// Validate that existing PVC's storage class matches the one found by storage policy mapping
-		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != storageClassName {
+		if isVanilla {
+			if pvc.Spec.StorageClassName != nil {
+				storageClassName = *pvc.Spec.StorageClassName
+			}
+			err = validateVanillaPVCTopologyCompatibility(ctx, k8sclient, pvc, accessibleNodeNames,
+				datastoreAccessibleTopology)
+			if err != nil {
+				msg := fmt.Sprintf("PVC topology validation failed: %v", err)
+				log.Error(msg)
+				setInstanceError(ctx, r, instance, msg)
+				if delErr := r.cleanupCNSVolume(ctx, instance, volumeID); delErr != nil {
+					log.Errorf("Failed to cleanup CNS volume: %s with error: %+v", volumeID, delErr)
+				}
+				return reconcile.Result{RequeueAfter: timeout}, nil
+			}
+		} else if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != storageClassName {
 			msg := fmt.Sprintf("PVC %s has storage class %s, but volume maps to storage class %s",
 				instance.Spec.PvcName, *pvc.Spec.StorageClassName, storageClassName)
 			log.Error(msg)
@@ -732,7 +804,7 @@ func (r *ReconcileCnsRegisterVolume) Reconcile(ctx context.Context,
 		// topology is already resolved (datastoreAccessibleTopology is non-empty) and topologyMgr is
 		// not consulted, so run the validation even when topologyMgr is nil (e.g. non-stretched
 		// supervisor) to ensure the reused PVC gets the {zone, hostname} annotation.
-		if topologyMgr != nil || isHostLocal {
+		if !isVanilla && (topologyMgr != nil || isHostLocal) {
 			err = validatePVCTopologyCompatibility(ctx, k8sclient, pvc, volume.DatastoreUrl, topologyMgr, vc,
 				datastoreAccessibleTopology, isHostLocal)
 			if err != nil {
@@ -1242,7 +1314,10 @@ func validateCnsRegisterVolumeSpec(ctx context.Context, instance *cnsregistervol
 		if instance.Spec.AccessMode == "" {
 			msg = "AccessMode cannot be empty when volumeID is specified"
 		} else {
-			pvName, found := commonco.ContainerOrchestratorUtility.GetPVNameFromCSIVolumeID(instance.Spec.VolumeID)
+			pvName, found, err := getPVNameFromCSIVolumeID(ctx, instance.Spec.VolumeID)
+			if err != nil {
+				return err
+			}
 			if found {
 				if pvName != staticPvNamePrefix+instance.Spec.VolumeID {
 					msg = fmt.Sprintf("PV: %q with the volume ID: %q "+
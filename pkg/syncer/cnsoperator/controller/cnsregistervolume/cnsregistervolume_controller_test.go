@@ -143,6 +143,12 @@ func (m *mockVolumeManager) ExpandVolume(ctx context.Context, volumeID string,
 	panic("implement me")
 }
 
+func (m *mockVolumeManager) UpdateVolumePolicy(ctx context.Context, volumeID string, storagePolicyID string,
+	targetDatastore *vim25types.ManagedObjectReference) (string, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m *mockVolumeManager) ResetManager(ctx context.Context, vcenter *cnsvsphere.VirtualCenter) error {
 	//TODO implement me
 	panic("implement me")
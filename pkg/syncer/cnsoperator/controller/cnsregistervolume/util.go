@@ -354,9 +354,13 @@ func constructCreateSpecForInstance(ctx context.Context, r *ReconcileCnsRegister
 	} else {
 		clusterIDForVolumeMetadata = r.configInfo.Cfg.Global.ClusterID
 	}
+	clusterFlavor := cnstypes.CnsClusterFlavorWorkload
+	if r.clusterFlavor == cnstypes.CnsClusterFlavorVanilla {
+		clusterFlavor = cnstypes.CnsClusterFlavorVanilla
+	}
 	containerCluster := vsphere.GetContainerCluster(clusterIDForVolumeMetadata,
 		r.configInfo.Cfg.VirtualCenter[host].User,
-		cnstypes.CnsClusterFlavorWorkload, r.configInfo.Cfg.Global.ClusterDistribution)
+		clusterFlavor, r.configInfo.Cfg.Global.ClusterDistribution)
 	createSpec := &cnstypes.CnsVolumeCreateSpec{
 		Name:       volumeName,
 		VolumeType: common.BlockVolumeType,
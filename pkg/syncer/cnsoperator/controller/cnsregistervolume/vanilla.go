@@ -0,0 +1,245 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsregistervolume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/node"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	commonconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
+	commoncotypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco/types"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
+)
+
+// annSelectedNode is the annotation added by the scheduler to the PVCs of the
+// StorageClasses with the WaitForFirstConsumer volume binding mode.
+const annSelectedNode = "volume.kubernetes.io/selected-node"
+
+// errVolumeNotAccessible is returned when the datastore of a volume registered
+// in a vanilla cluster is not accessible to the nodes required by the cluster
+// topology. Unlike the other errors, it is a permanent failure.
+var errVolumeNotAccessible = errors.New("volume is not accessible to the nodes of the cluster")
+
+// vanillaNodeManager is the subset of the node manager used to find the nodes
+// of a vanilla cluster.
+type vanillaNodeManager interface {
+	GetAllNodes(ctx context.Context) ([]*cnsvsphere.VirtualMachine, error)
+	GetNodeNameByUUID(ctx context.Context, nodeUUID string) (string, error)
+}
+
+var (
+	// nodeMgr is the node manager of the vanilla cluster.
+	nodeMgr vanillaNodeManager
+	// getDatastoreAccessibleNodeNamesFn is a variable so that the tests can
+	// substitute it.
+	getDatastoreAccessibleNodeNamesFn = getDatastoreAccessibleNodeNames
+	// getPVNameFromCSIVolumeID returns the name of the PV of a volume, if any.
+	// The volume ID to PV name map of the container orchestrator is only
+	// maintained in the vanilla clusters with the list-volumes feature, so
+	// initVanilla replaces it with a lookup of the PV informer cache.
+	getPVNameFromCSIVolumeID = func(ctx context.Context, volumeID string) (string, bool, error) {
+		pvName, found := commonco.ContainerOrchestratorUtility.GetPVNameFromCSIVolumeID(volumeID)
+		return pvName, found, nil
+	}
+)
+
+// initVanilla initializes the node manager and, if the cluster is topology
+// aware, the topology service used to register volumes in a vanilla cluster.
+func initVanilla(ctx context.Context, configInfo *commonconfig.ConfigurationInfo) error {
+	log := logger.GetLogger(ctx)
+	nodes := &node.Nodes{}
+	if err := nodes.Initialize(ctx); err != nil {
+		return logger.LogNewErrorf(log, "failed to initialize the node manager. Error: %v", err)
+	}
+	nodeMgr = nodes
+	k8sclient, err := k8s.NewClient(ctx)
+	if err != nil {
+		return logger.LogNewErrorf(log, "failed to create the kubernetes client. Error: %v", err)
+	}
+	// The PV informer is shared with the metadata syncer. Listen starts it and
+	// waits for its cache to sync.
+	informerManager := k8s.NewInformer(ctx, k8sclient)
+	informerManager.InitPVInformer()
+	informerManager.Listen()
+	pvLister := informerManager.GetPVLister()
+	getPVNameFromCSIVolumeID = func(ctx context.Context, volumeID string) (string, bool, error) {
+		return findPVNameByVolumeHandle(ctx, pvLister, volumeID)
+	}
+	if configInfo.Cfg.Labels.TopologyCategories == "" && configInfo.Cfg.Labels.Zone == "" {
+		return nil
+	}
+	topologyMgr, err = commonco.ContainerOrchestratorUtility.InitTopologyServiceInController(ctx)
+	if err != nil {
+		return logger.LogNewErrorf(log, "failed to init topology manager. Error: %v", err)
+	}
+	return nil
+}
+
+// findPVNameByVolumeHandle returns the name of the vSphere CSI PV with the
+// given volume handle, if any, from the PV informer cache.
+func findPVNameByVolumeHandle(ctx context.Context, pvLister corelisters.PersistentVolumeLister,
+	volumeID string) (string, bool, error) {
+	log := logger.GetLogger(ctx)
+	pvs, err := pvLister.List(labels.Everything())
+	if err != nil {
+		return "", false, logger.LogNewErrorf(log, "failed to list the PVs. Error: %v", err)
+	}
+	for _, pv := range pvs {
+		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == csitypes.Name && pv.Spec.CSI.VolumeHandle == volumeID {
+			return pv.Name, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// getDatastoreAccessibleNodeNames returns the names of the nodes of the
+// vanilla cluster which have access to the datastore and the total number of
+// nodes of the cluster.
+func getDatastoreAccessibleNodeNames(ctx context.Context, vc *cnsvsphere.VirtualCenter,
+	datastoreURL string) ([]string, int, error) {
+	log := logger.GetLogger(ctx)
+	allNodeVMs, err := nodeMgr.GetAllNodes(ctx)
+	if err != nil {
+		return nil, 0, logger.LogNewErrorf(log, "failed to get the nodes of the cluster. Error: %v", err)
+	}
+	accessibleNodes, err := common.GetNodeVMsWithAccessToDatastore(ctx, vc, datastoreURL, allNodeVMs)
+	if err != nil {
+		return nil, 0, logger.LogNewErrorf(log, "failed to find the nodes with access to datastore %q. "+
+			"Error: %v", datastoreURL, err)
+	}
+	nodeNames := make([]string, 0, len(accessibleNodes))
+	for _, vmRef := range accessibleNodes {
+		vmUUID, err := cnsvsphere.GetUUIDFromVMReference(ctx, vc, vmRef.Reference())
+		if err != nil {
+			return nil, 0, logger.LogNewErrorf(log, "failed to get the UUID of node VM %v. Error: %v",
+				vmRef.Reference(), err)
+		}
+		nodeName, err := nodeMgr.GetNodeNameByUUID(ctx, vmUUID)
+		if err != nil {
+			return nil, 0, logger.LogNewErrorf(log, "failed to get the name of node with UUID %q. Error: %v",
+				vmUUID, err)
+		}
+		nodeNames = append(nodeNames, nodeName)
+	}
+	return nodeNames, len(allNodeVMs), nil
+}
+
+// getVanillaVolumeAccessibility returns the names of the nodes with access to
+// the datastore of a volume registered in a vanilla cluster and the topology
+// segments the volume is accessible from.
+// In a cluster without topology, the PV doesn't have node affinity, so the
+// datastore must be accessible to all the nodes. In a topology aware cluster,
+// the datastore must be accessible to all the nodes of at least one topology
+// domain. errVolumeNotAccessible is returned otherwise.
+func getVanillaVolumeAccessibility(ctx context.Context, vc *cnsvsphere.VirtualCenter,
+	datastoreURL string) ([]string, []map[string]string, error) {
+	log := logger.GetLogger(ctx)
+	nodeNames, totalNodes, err := getDatastoreAccessibleNodeNamesFn(ctx, vc, datastoreURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(nodeNames) == 0 {
+		return nil, nil, fmt.Errorf("%w: datastore %q is not accessible to any node", errVolumeNotAccessible,
+			datastoreURL)
+	}
+	if topologyMgr == nil {
+		if len(nodeNames) != totalNodes {
+			return nil, nil, fmt.Errorf("%w: datastore %q is accessible to %d out of %d nodes",
+				errVolumeNotAccessible, datastoreURL, len(nodeNames), totalNodes)
+		}
+		return nodeNames, nil, nil
+	}
+	datastoreAccessibleTopology, err := topologyMgr.GetTopologyInfoFromNodes(ctx,
+		commoncotypes.VanillaRetrieveTopologyInfoParams{
+			DatastoreURL: datastoreURL,
+			NodeNames:    nodeNames,
+		})
+	if err != nil {
+		return nil, nil, logger.LogNewErrorf(log, "failed to find the topology of datastore %q. Error: %v",
+			datastoreURL, err)
+	}
+	if len(datastoreAccessibleTopology) == 0 {
+		return nil, nil, fmt.Errorf("%w: datastore %q is not accessible to all the nodes of any topology domain",
+			errVolumeNotAccessible, datastoreURL)
+	}
+	return nodeNames, datastoreAccessibleTopology, nil
+}
+
+// validateVanillaPVCTopologyCompatibility checks that an existing PVC can be
+// bound to a volume registered in a vanilla cluster. The node selected by the
+// scheduler for the PVC, if any, must have access to the volume and the
+// allowed topologies of the StorageClass of the PVC, if any, must include one
+// of the topology segments the volume is accessible from.
+func validateVanillaPVCTopologyCompatibility(ctx context.Context, k8sclient clientset.Interface,
+	pvc *v1.PersistentVolumeClaim, accessibleNodeNames []string,
+	datastoreAccessibleTopology []map[string]string) error {
+	log := logger.GetLogger(ctx)
+	if selectedNode := pvc.Annotations[annSelectedNode]; selectedNode != "" &&
+		!slices.Contains(accessibleNodeNames, selectedNode) {
+		return fmt.Errorf("PVC %s/%s is scheduled on node %q which doesn't have access to the volume",
+			pvc.Namespace, pvc.Name, selectedNode)
+	}
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" ||
+		len(datastoreAccessibleTopology) == 0 {
+		return nil
+	}
+	sc, err := k8sclient.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch StorageClass %q of PVC %s/%s: %v", *pvc.Spec.StorageClassName,
+			pvc.Namespace, pvc.Name, err)
+	}
+	if len(sc.AllowedTopologies) == 0 {
+		return nil
+	}
+	for _, segment := range datastoreAccessibleTopology {
+		for _, term := range sc.AllowedTopologies {
+			if isSegmentInTopologySelectorTerm(segment, term) {
+				log.Infof("PVC %s/%s StorageClass allowed topologies are compatible with volume placement",
+					pvc.Namespace, pvc.Name)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("allowed topologies of StorageClass %q of PVC %s/%s are not compatible with volume "+
+		"placement in %+v", sc.Name, pvc.Namespace, pvc.Name, datastoreAccessibleTopology)
+}
+
+// isSegmentInTopologySelectorTerm returns true if the topology segment
+// satisfies all the label expressions of the topology selector term.
+func isSegmentInTopologySelectorTerm(segment map[string]string, term v1.TopologySelectorTerm) bool {
+	for _, expression := range term.MatchLabelExpressions {
+		value, ok := segment[expression.Key]
+		if !ok || !slices.Contains(expression.Values, value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cnsregistervolume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cnstypes "github.com/vmware/govmomi/cns/types"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cnsregistervolumev1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator/cnsregistervolume/v1alpha1"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	commonconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	csitypes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/types"
+)
+
+func TestConstructCreateSpecForInstanceInVanillaCluster(t *testing.T) {
+	instance := &cnsregistervolumev1alpha1.CnsRegisterVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "register-vol", Namespace: "test-ns"},
+		Spec: cnsregistervolumev1alpha1.CnsRegisterVolumeSpec{
+			PvcName:     "pvc-1",
+			DiskURLPath: "https://vc/folder/vm/vm_1.vmdk?dcPath=dc&dsName=ds",
+		},
+	}
+	cfg := &commonconfig.Config{
+		VirtualCenter: map[string]*commonconfig.VirtualCenterConfig{
+			"test-host": {User: "test-user"},
+		},
+	}
+	cfg.Global.ClusterID = "test-cluster"
+	r := &ReconcileCnsRegisterVolume{
+		configInfo:    &commonconfig.ConfigurationInfo{Cfg: cfg},
+		clusterFlavor: cnstypes.CnsClusterFlavorVanilla,
+	}
+
+	spec := constructCreateSpecForInstance(context.TODO(), r, instance, "test-host", false)
+	assert.Equal(t, string(cnstypes.CnsClusterFlavorVanilla), spec.Metadata.ContainerCluster.ClusterFlavor)
+	assert.Equal(t, "test-cluster", spec.Metadata.ContainerCluster.ClusterId)
+	backing, ok := spec.BackingObjectDetails.(*cnstypes.CnsBlockBackingDetails)
+	assert.True(t, ok, "BackingObjectDetails should be *CnsBlockBackingDetails")
+	assert.Equal(t, instance.Spec.DiskURLPath, backing.BackingDiskUrlPath)
+}
+
+// TestGetVanillaVolumeAccessibility swaps getDatastoreAccessibleNodeNamesFn and
+// topologyMgr instead of connecting to a vCenter.
+func TestGetVanillaVolumeAccessibility(t *testing.T) {
+	ctx := context.Background()
+	datastoreURL := "ds:///vmfs/volumes/test-datastore"
+	originalFn := getDatastoreAccessibleNodeNamesFn
+	originalTopologyMgr := topologyMgr
+	restore := func() {
+		getDatastoreAccessibleNodeNamesFn = originalFn
+		topologyMgr = originalTopologyMgr
+	}
+	setAccessibleNodes := func(nodeNames []string, totalNodes int, err error) {
+		getDatastoreAccessibleNodeNamesFn = func(_ context.Context, _ *cnsvsphere.VirtualCenter,
+			_ string) ([]string, int, error) {
+			return nodeNames, totalNodes, err
+		}
+	}
+
+	t.Run("without topology - accessible to all nodes", func(t *testing.T) {
+		t.Cleanup(restore)
+		topologyMgr = nil
+		setAccessibleNodes([]string{"node-1", "node-2"}, 2, nil)
+		nodeNames, topology, err := getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"node-1", "node-2"}, nodeNames)
+		assert.Nil(t, topology)
+	})
+
+	t.Run("without topology - accessible to some nodes", func(t *testing.T) {
+		t.Cleanup(restore)
+		topologyMgr = nil
+		setAccessibleNodes([]string{"node-1"}, 2, nil)
+		_, _, err := getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.True(t, errors.Is(err, errVolumeNotAccessible))
+	})
+
+	t.Run("not accessible to any node", func(t *testing.T) {
+		t.Cleanup(restore)
+		topologyMgr = &mockTopologyService{}
+		setAccessibleNodes(nil, 2, nil)
+		_, _, err := getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.True(t, errors.Is(err, errVolumeNotAccessible))
+	})
+
+	t.Run("with topology - accessible to a topology domain", func(t *testing.T) {
+		t.Cleanup(restore)
+		zone := []map[string]string{{"topology.csi.vmware.com/k8s-zone": "zone-a"}}
+		topologyMgr = &mockTopologyService{returnTopology: zone}
+		setAccessibleNodes([]string{"node-1"}, 2, nil)
+		nodeNames, topology, err := getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"node-1"}, nodeNames)
+		assert.Equal(t, zone, topology)
+	})
+
+	t.Run("with topology - not accessible to any topology domain", func(t *testing.T) {
+		t.Cleanup(restore)
+		topologyMgr = &mockTopologyService{returnTopology: []map[string]string{}}
+		setAccessibleNodes([]string{"node-1"}, 2, nil)
+		_, _, err := getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.True(t, errors.Is(err, errVolumeNotAccessible))
+	})
+
+	t.Run("transient errors are not permanent failures", func(t *testing.T) {
+		t.Cleanup(restore)
+		topologyMgr = &mockTopologyService{shouldFail: true}
+		setAccessibleNodes([]string{"node-1"}, 1, nil)
+		_, _, err := getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, errVolumeNotAccessible))
+
+		setAccessibleNodes(nil, 0, fmt.Errorf("vCenter is not reachable"))
+		_, _, err = getVanillaVolumeAccessibility(ctx, nil, datastoreURL)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, errVolumeNotAccessible))
+	})
+}
+
+func TestValidateVanillaPVCTopologyCompatibility(t *testing.T) {
+	ctx := context.Background()
+	zoneKey := "topology.csi.vmware.com/k8s-zone"
+	scName := "zonal-sc"
+	k8sclient := k8sfake.NewClientset(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{Name: scName},
+		AllowedTopologies: []v1.TopologySelectorTerm{{
+			MatchLabelExpressions: []v1.TopologySelectorLabelRequirement{
+				{Key: zoneKey, Values: []string{"zone-a", "zone-b"}},
+			},
+		}},
+	})
+	newPVC := func(selectedNode string, storageClassName *string) *v1.PersistentVolumeClaim {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pvc", Namespace: "test-ns", Annotations: map[string]string{}},
+			Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: storageClassName},
+		}
+		if selectedNode != "" {
+			pvc.Annotations[annSelectedNode] = selectedNode
+		}
+		return pvc
+	}
+	accessibleNodeNames := []string{"node-1", "node-2"}
+
+	t.Run("selected node with access to the volume", func(t *testing.T) {
+		err := validateVanillaPVCTopologyCompatibility(ctx, k8sclient, newPVC("node-1", nil),
+			accessibleNodeNames, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("selected node without access to the volume", func(t *testing.T) {
+		err := validateVanillaPVCTopologyCompatibility(ctx, k8sclient, newPVC("node-3", nil),
+			accessibleNodeNames, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("allowed topologies include the volume topology", func(t *testing.T) {
+		err := validateVanillaPVCTopologyCompatibility(ctx, k8sclient, newPVC("", &scName),
+			accessibleNodeNames, []map[string]string{{zoneKey: "zone-c"}, {zoneKey: "zone-b"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("allowed topologies don't include the volume topology", func(t *testing.T) {
+		err := validateVanillaPVCTopologyCompatibility(ctx, k8sclient, newPVC("", &scName),
+			accessibleNodeNames, []map[string]string{{zoneKey: "zone-c"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("StorageClass not found", func(t *testing.T) {
+		missing := "missing-sc"
+		err := validateVanillaPVCTopologyCompatibility(ctx, k8sclient, newPVC("", &missing),
+			accessibleNodeNames, []map[string]string{{zoneKey: "zone-a"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestFindPVNameByVolumeHandle(t *testing.T) {
+	ctx := context.Background()
+	newPV := func(name, driver, volumeHandle string) *v1.PersistentVolume {
+		return &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1.PersistentVolumeSpec{
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{Driver: driver, VolumeHandle: volumeHandle},
+				},
+			},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(newPV("other-driver-pv", "other.csi.driver", "vol-1")))
+	assert.NoError(t, indexer.Add(newPV("vsphere-pv", csitypes.Name, "vol-1")))
+	pvLister := corelisters.NewPersistentVolumeLister(indexer)
+
+	pvName, found, err := findPVNameByVolumeHandle(ctx, pvLister, "vol-1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "vsphere-pv", pvName)
+
+	_, found, err = findPVNameByVolumeHandle(ctx, pvLister, "vol-2")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
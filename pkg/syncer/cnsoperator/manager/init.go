@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -265,36 +266,10 @@ func InitCnsOperator(ctx context.Context, clusterFlavor cnstypes.CnsClusterFlavo
 			log.Info("Observed stretchedSupervisor setup")
 		}
 		if !stretchedSupervisor || syncer.IsPodVMOnStretchSupervisorFSSEnabled {
-			// Create CnsRegisterVolume CRD from manifest.
-			log.Infof("Creating %q CRD", cnsoperatorv1alpha1.CnsRegisterVolumePlural)
-			err = k8s.CreateCustomResourceDefinitionFromManifest(ctx, cnsoperatorconfig.EmbedCnsRegisterVolumeCRFile,
-				cnsoperatorconfig.EmbedCnsRegisterVolumeCRFileName)
+			err = initCnsRegisterVolume(ctx, cnsOperator, restConfig)
 			if err != nil {
-				log.Errorf("Failed to create %q CRD. Err: %+v", cnsoperatorv1alpha1.CnsRegisterVolumePlural, err)
 				return err
 			}
-			log.Infof("%q CRD is created successfully", cnsoperatorv1alpha1.CnsRegisterVolumePlural)
-
-			// Clean up routine to cleanup successful CnsRegisterVolume instances.
-			log.Info("Starting go routine to cleanup successful CnsRegisterVolume instances.")
-			err = watcher(ctx, cnsOperator)
-			if err != nil {
-				log.Error("Failed to watch on config file for changes to "+
-					"CnsRegisterVolumesCleanupIntervalInMin. Error: %+v", err)
-				return err
-			}
-			go func() {
-				for {
-					ctx, log = logger.GetNewContextWithLogger()
-					log.Infof("Triggering CnsRegisterVolume cleanup routine")
-					cleanUpCnsRegisterVolumeInstances(ctx, restConfig,
-						cnsOperator.configInfo.Cfg.Global.CnsRegisterVolumesCleanupIntervalInMin)
-					log.Infof("Completed CnsRegisterVolume cleanup")
-					for i := 1; i <= cnsOperator.configInfo.Cfg.Global.CnsRegisterVolumesCleanupIntervalInMin; i++ {
-						time.Sleep(time.Duration(1 * time.Minute))
-					}
-				}
-			}()
 
 			if commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.WCPMobilityNonDisruptiveImport) {
 				// Create CnsUnregisterVolume CRD from manifest.
@@ -359,6 +334,23 @@ func InitCnsOperator(ctx context.Context, clusterFlavor cnstypes.CnsClusterFlavo
 			log.Errorf("Failed to create %q CRD. Error: %+v", csinodetopology.CRDSingular, err)
 			return err
 		}
+		// The CnsRegisterVolume and CnsBulkRegisterVolume Controllers only support
+		// single vCenter deployments.
+		if len(cnsOperator.configInfo.Cfg.VirtualCenter) == 1 {
+			err = initCnsRegisterVolume(ctx, cnsOperator, restConfig)
+			if err != nil {
+				return err
+			}
+			// Create CnsBulkRegisterVolume CRD from manifest.
+			log.Infof("Creating %q CRD", cnsoperatorv1alpha1.CnsBulkRegisterVolumePlural)
+			err = k8s.CreateCustomResourceDefinitionFromManifest(ctx, cnsoperatorconfig.EmbedCnsBulkRegisterVolumeCRFile,
+				cnsoperatorconfig.EmbedCnsBulkRegisterVolumeCRFileName)
+			if err != nil {
+				log.Errorf("Failed to create %q CRD. Err: %+v", cnsoperatorv1alpha1.CnsBulkRegisterVolumePlural, err)
+				return err
+			}
+			log.Infof("%q CRD is created successfully", cnsoperatorv1alpha1.CnsBulkRegisterVolumePlural)
+		}
 	} else if clusterFlavor == cnstypes.CnsClusterFlavorGuest {
 		if cnsOperator.coCommonInterface.IsFSSEnabled(ctx, common.TKGsHA) {
 			// Create CSINodeTopology CRD.
@@ -511,6 +503,43 @@ func InitCommonModules(ctx context.Context, clusterFlavor cnstypes.CnsClusterFla
 	return nil
 }
 
+// initCnsRegisterVolume creates the CnsRegisterVolume CRD and starts the go
+// routine to cleanup successful CnsRegisterVolume instances.
+func initCnsRegisterVolume(ctx context.Context, cnsOperator *cnsOperatorInfo, restConfig *rest.Config) error {
+	log := logger.GetLogger(ctx)
+	// Create CnsRegisterVolume CRD from manifest.
+	log.Infof("Creating %q CRD", cnsoperatorv1alpha1.CnsRegisterVolumePlural)
+	err := k8s.CreateCustomResourceDefinitionFromManifest(ctx, cnsoperatorconfig.EmbedCnsRegisterVolumeCRFile,
+		cnsoperatorconfig.EmbedCnsRegisterVolumeCRFileName)
+	if err != nil {
+		log.Errorf("Failed to create %q CRD. Err: %+v", cnsoperatorv1alpha1.CnsRegisterVolumePlural, err)
+		return err
+	}
+	log.Infof("%q CRD is created successfully", cnsoperatorv1alpha1.CnsRegisterVolumePlural)
+
+	// Clean up routine to cleanup successful CnsRegisterVolume instances.
+	log.Info("Starting go routine to cleanup successful CnsRegisterVolume instances.")
+	err = watcher(ctx, cnsOperator)
+	if err != nil {
+		log.Error("Failed to watch on config file for changes to "+
+			"CnsRegisterVolumesCleanupIntervalInMin. Error: %+v", err)
+		return err
+	}
+	go func() {
+		for {
+			ctx, log := logger.GetNewContextWithLogger()
+			log.Infof("Triggering CnsRegisterVolume cleanup routine")
+			cleanUpCnsRegisterVolumeInstances(ctx, restConfig,
+				cnsOperator.configInfo.Cfg.Global.CnsRegisterVolumesCleanupIntervalInMin)
+			log.Infof("Completed CnsRegisterVolume cleanup")
+			for i := 1; i <= cnsOperator.configInfo.Cfg.Global.CnsRegisterVolumesCleanupIntervalInMin; i++ {
+				time.Sleep(time.Duration(1 * time.Minute))
+			}
+		}
+	}()
+	return nil
+}
+
 // watcher watches on the vsphere.conf file mounted as secret within the syncer
 // container.
 func watcher(ctx context.Context, cnsOperator *cnsOperatorInfo) error {
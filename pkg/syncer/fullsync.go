@@ -79,7 +79,13 @@ var queryVolumeByIDFn = common.QueryVolumeByID
 // CsiFullSync reconciles volume metadata on a vanilla k8s cluster with volume
 // metadata on CNS.
 func CsiFullSync(ctx context.Context, metadataSyncer *metadataSyncInformer, vc string) error {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemFullSync)
+	ctx = cnsvsphere.WithRequestLane(ctx, cnsvsphere.RequestLaneFullSync)
 	log := logger.GetLogger(ctx)
+	if cnsvsphere.IsVCenterDown(vc) {
+		log.Infof("FullSync for VC %s: skipped as the vCenter is down", vc)
+		return nil
+	}
 	log.Infof("FullSync for VC %s: start", vc)
 	fullSyncStartTime := time.Now()
 	var migrationFeatureStateForFullSync bool
@@ -870,6 +870,12 @@ func (m *mockVolumeManagerForFullSync) ExpandVolume(
 	return "", nil
 }
 
+func (m *mockVolumeManagerForFullSync) UpdateVolumePolicy(
+	ctx context.Context, volumeID, storagePolicyID string, targetDatastore *types.ManagedObjectReference,
+) (string, error) {
+	return "", nil
+}
+
 func (m *mockVolumeManagerForFullSync) ResetManager(ctx context.Context, vcenter *cnsvsphere.VirtualCenter) error {
 	return nil
 }
@@ -66,6 +66,7 @@ import (
 	volumes "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/volume"
 	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 	cnsconfig "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/config"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/utils"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common/commonco"
@@ -277,6 +278,10 @@ func InitMetadataSyncer(ctx context.Context, clusterFlavor cnstypes.CnsClusterFl
 	metadataSyncer := newInformer()
 	MetadataSyncer = metadataSyncer
 	metadataSyncer.configInfo = configInfo
+	if err := prometheus.ConfigureOptionalLabels(configInfo.Cfg.Metrics.OptionalLabels,
+		configInfo.Cfg.Metrics.StoragePolicies); err != nil {
+		return logger.LogNewErrorf(log, "invalid Metrics configuration. Error: %v", err)
+	}
 
 	isStorageQuotaM2FSSEnabled = commonco.ContainerOrchestratorUtility.IsFSSEnabled(ctx, common.StorageQuotaM2)
 	// Create the kubernetes client from config.
@@ -660,6 +665,11 @@ func InitMetadataSyncer(ctx context.Context, clusterFlavor cnstypes.CnsClusterFl
 		}
 	}
 
+	if metadataSyncer.clusterFlavor != cnstypes.CnsClusterFlavorGuest {
+		if err := common.InitVCEventRecorder(ctx, "vsphere-syncer"); err != nil {
+			log.Warnf("failed to initialize event recorder for vCenter events. err=%v", err)
+		}
+	}
 	cfgPath := cnsconfig.GetConfigPath(ctx)
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -843,6 +853,10 @@ func InitMetadataSyncer(ctx context.Context, clusterFlavor cnstypes.CnsClusterFl
 	// This ensures that migration watchers are properly started after syncer restart.
 	initMigrationWatchersOnStartup(ctx, metadataSyncer)
 
+	if metadataSyncer.clusterFlavor != cnstypes.CnsClusterFlavorGuest {
+		startVCenterHealthReporter(ctx)
+	}
+
 	fullSyncTicker := time.NewTicker(time.Duration(getFullSyncIntervalInMin(ctx)) * time.Minute)
 	defer fullSyncTicker.Stop()
 	// Trigger full sync.
@@ -2767,8 +2781,24 @@ func ReloadConfiguration(metadataSyncer *metadataSyncInformer, reconnectToVCFrom
 				if err != nil {
 					return logger.LogNewErrorf(log, "failed to get VirtualCenter. err=%v", err)
 				}
-				vcenter.Config = newVCConfig
-				err := metadataSyncer.volumeManagers[newVCConfig.Host].ResetManager(ctx, vcenter)
+				// Login a new session if the credentials are rotated, and keep the
+				// current config if vCenter rejects them.
+				credentialProvider, err := cnsvsphere.NewCredentialProvider(newVCConfig)
+				if err == nil {
+					err = vcenter.RotateCredentials(ctx, credentialProvider)
+				}
+				if err != nil {
+					return logger.LogNewErrorf(log, "failed to rotate credentials for vCenter: %q. err=%v",
+						newVCConfig.Host, err)
+				}
+				vcConfigChanged := !cnsvsphere.IsCredentialOnlyChange(vcenter.Config, newVCConfig)
+				vcenter.SetConfig(newVCConfig)
+				if !vcConfigChanged {
+					// The volume manager already uses this vCenter instance, whose
+					// session was swapped in place by the credential rotation.
+					continue
+				}
+				err = metadataSyncer.volumeManagers[newVCConfig.Host].ResetManager(ctx, vcenter)
 				if err != nil {
 					return logger.LogNewErrorf(log, "failed to reset updated VC object in volumemanager for vCenter: %q "+
 						"err=%v", newVCConfig.Host, err)
@@ -2790,10 +2820,7 @@ func ReloadConfiguration(metadataSyncer *metadataSyncInformer, reconnectToVCFrom
 		if newVCConfig != nil {
 			var vcenter *cnsvsphere.VirtualCenter
 			newVCConfig.ReloadVCConfigForNewClient = true
-			if metadataSyncer.host != newVCConfig.Host ||
-				metadataSyncer.configInfo.Cfg.VirtualCenter[metadataSyncer.host].User != newVCConfig.Username ||
-				metadataSyncer.configInfo.Cfg.VirtualCenter[metadataSyncer.host].Password != newVCConfig.Password ||
-				reconnectToVCFromNewConfig {
+			if metadataSyncer.host != newVCConfig.Host || reconnectToVCFromNewConfig {
 				// Verify if new configuration has valid credentials by connecting
 				// to vCenter. Proceed only if the connection succeeds, else return
 				// error.
@@ -2812,13 +2839,31 @@ func ReloadConfiguration(metadataSyncer *metadataSyncInformer, reconnectToVCFrom
 					return logger.LogNewErrorf(log, "failed to get VirtualCenter. err=%v", err)
 				}
 			} else {
-				// If it's not a VC host or VC credentials update, same singleton
-				// instance can be used and it's Config field can be updated.
+				// If it's not a VC host update, same singleton instance can be used
+				// and it's Config field can be updated. Rotated credentials are
+				// verified by logging in a new session.
 				vcenter, err = cnsvsphere.GetVirtualCenterInstance(ctx, &cnsconfig.ConfigurationInfo{Cfg: cfg}, false)
 				if err != nil {
 					return logger.LogNewErrorf(log, "failed to get VirtualCenter. err=%v", err)
 				}
-				vcenter.Config = newVCConfig
+				credentialProvider, err := cnsvsphere.NewCredentialProvider(newVCConfig)
+				if err == nil {
+					err = vcenter.RotateCredentials(ctx, credentialProvider)
+				}
+				if err != nil {
+					return logger.LogNewErrorf(log, "failed to rotate credentials for VirtualCenter host: %s, Err: %+v",
+						newVCConfig.Host, err)
+				}
+				vcConfigChanged := !cnsvsphere.IsCredentialOnlyChange(vcenter.Config, newVCConfig)
+				vcenter.SetConfig(newVCConfig)
+				if !vcConfigChanged {
+					// The volume manager and storage pool service already use this
+					// vCenter instance, whose session was swapped in place by the
+					// credential rotation.
+					metadataSyncer.configInfo = &cnsconfig.ConfigurationInfo{Cfg: cfg}
+					log.Infof("updated metadataSyncer.configInfo")
+					return nil
+				}
 			}
 			err := metadataSyncer.volumeManager.ResetManager(ctx, vcenter)
 			if err != nil {
@@ -25,6 +25,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientset "k8s.io/client-go/kubernetes"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/utils"
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
@@ -34,6 +35,10 @@ import (
 func csiGetPVtoBackingDiskObjectIdMapping(ctx context.Context, k8sclient clientset.Interface,
 	metadataSyncer *metadataSyncInformer, vc string) {
 	log := logger.GetLogger(ctx)
+	if cnsvsphere.IsVCenterDown(vc) {
+		log.Infof("csiGetPVtoBackingDiskObjectIdMapping for %s: skipped as the vCenter is down", vc)
+		return
+	}
 	log.Debugf("csiGetPVtoBackingDiskObjectIdMapping for %s: start", vc)
 	// Call CNS QueryAll to get container volumes by cluster ID.
 	querySelection := cnstypes.CnsQuerySelection{
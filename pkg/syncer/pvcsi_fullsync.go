@@ -57,6 +57,7 @@ var (
 // PvcsiFullSync reconciles PV/PVC/Pod metadata on the guest cluster with
 // cnsvolumemetadata objects on the supervisor cluster for the guest cluster.
 func PvcsiFullSync(ctx context.Context, metadataSyncer *metadataSyncInformer) error {
+	ctx = logger.WithSubsystem(ctx, logger.SubsystemFullSync)
 	log := logger.GetLogger(ctx)
 	log.Infof("FullSync: Start")
 	var err error
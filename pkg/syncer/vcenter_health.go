@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	cnsoperatorv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/cnsoperator"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/common"
+	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/csi/service/logger"
+	triggercsifullsyncv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsoperator/triggercsifullsync/v1alpha1"
+	k8s "sigs.k8s.io/vsphere-csi-driver/v3/pkg/kubernetes"
+)
+
+// vCenterHealthReportInterval is the interval at which the health of the
+// vCenters is reported in the TriggerCsiFullSync instance.
+const vCenterHealthReportInterval = 30 * time.Second
+
+// reportVCenterHealth periodically reports the health of the connections of
+// the syncer to the vCenters as the VCenterAvailable condition of the
+// TriggerCsiFullSync instance. The instance only exists when the
+// trigger-csi-fullsync feature is enabled, the health is not reported in it
+// otherwise.
+func reportVCenterHealth(cnsOperatorClient client.Client) {
+	ticker := time.NewTicker(vCenterHealthReportInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		health := cnsvsphere.GetVCenterHealth()
+		if len(health) == 0 {
+			continue
+		}
+		ctx, log := logger.GetNewContextWithLogger()
+		instance := &triggercsifullsyncv1alpha1.TriggerCsiFullSync{}
+		key := k8stypes.NamespacedName{Namespace: "", Name: common.TriggerCsiFullSyncCRName}
+		if err := cnsOperatorClient.Get(ctx, key, instance); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				log.Debugf("TriggerCsiFullSync instance %q not found. Skipping the vCenter health report.",
+					common.TriggerCsiFullSyncCRName)
+				continue
+			}
+			log.Warnf("failed to get TriggerCsiFullSync instance %q to report the vCenter health. Error: %v",
+				common.TriggerCsiFullSyncCRName, err)
+			continue
+		}
+		if !meta.SetStatusCondition(&instance.Status.Conditions, getVCenterAvailableCondition(health)) {
+			continue
+		}
+		if err := updateTriggerCsiFullSyncInstance(ctx, cnsOperatorClient, instance); err != nil {
+			log.Warnf("failed to report the vCenter health in TriggerCsiFullSync instance %q. Error: %v",
+				common.TriggerCsiFullSyncCRName, err)
+		}
+	}
+}
+
+// getVCenterAvailableCondition returns the VCenterAvailable condition for the
+// health of the vCenters. The reason of the condition is the worst state of
+// the vCenters.
+func getVCenterAvailableCondition(health map[string]cnsvsphere.VCenterHealth) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    triggercsifullsyncv1alpha1.VCenterAvailableCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  triggercsifullsyncv1alpha1.VCenterHealthyReason,
+		Message: "All vCenters are healthy",
+	}
+	hosts := make([]string, 0, len(health))
+	for host := range health {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var messages []string
+	for _, host := range hosts {
+		h := health[host]
+		switch h.State {
+		case cnsvsphere.HealthStateHealthy:
+			continue
+		case cnsvsphere.HealthStateDown:
+			condition.Reason = triggercsifullsyncv1alpha1.VCenterDownReason
+		case cnsvsphere.HealthStateDegraded:
+			if condition.Reason != triggercsifullsyncv1alpha1.VCenterDownReason {
+				condition.Reason = triggercsifullsyncv1alpha1.VCenterDegradedReason
+			}
+		}
+		condition.Status = metav1.ConditionFalse
+		messages = append(messages, fmt.Sprintf("vCenter %s is %s since %s. Last error: %v",
+			host, h.State, h.Since.UTC().Format(time.RFC3339), h.LastError))
+	}
+	if len(messages) > 0 {
+		condition.Message = strings.Join(messages, "; ")
+	}
+	return condition
+}
+
+// startVCenterHealthReporter starts reporting the health of the vCenters in
+// the background. The health is also published as events on the pods of the
+// controller and the syncer, which do not depend on TriggerCsiFullSync.
+func startVCenterHealthReporter(ctx context.Context) {
+	log := logger.GetLogger(ctx)
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		log.Warnf("failed to get Kubernetes config to report the vCenter health. Err: %+v", err)
+		return
+	}
+	cnsOperatorClient, err := k8s.NewClientForGroup(ctx, restConfig, cnsoperatorv1alpha1.GroupName)
+	if err != nil {
+		log.Warnf("failed to create CnsOperator client to report the vCenter health. Err: %+v", err)
+		return
+	}
+	log.Infof("Reporting the vCenter health in the %q condition of TriggerCsiFullSync instance %q",
+		triggercsifullsyncv1alpha1.VCenterAvailableCondition, common.TriggerCsiFullSyncCRName)
+	go reportVCenterHealth(cnsOperatorClient)
+}
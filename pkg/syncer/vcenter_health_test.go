@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
+	triggercsifullsyncv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/internalapis/cnsoperator/triggercsifullsync/v1alpha1"
+)
+
+func TestGetVCenterAvailableCondition(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	health := map[string]cnsvsphere.VCenterHealth{
+		"vc1.example.com": {State: cnsvsphere.HealthStateHealthy, Since: since},
+	}
+	condition := getVCenterAvailableCondition(health)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, triggercsifullsyncv1alpha1.VCenterHealthyReason, condition.Reason)
+
+	health["vc2.example.com"] = cnsvsphere.VCenterHealth{State: cnsvsphere.HealthStateDegraded, Since: since,
+		LastError: errors.New("connection refused")}
+	condition = getVCenterAvailableCondition(health)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, triggercsifullsyncv1alpha1.VCenterDegradedReason, condition.Reason)
+	assert.Equal(t, "vCenter vc2.example.com is degraded since 2026-01-02T03:04:05Z. Last error: connection refused",
+		condition.Message)
+
+	// The reason is the worst state of the vCenters.
+	health["vc0.example.com"] = cnsvsphere.VCenterHealth{State: cnsvsphere.HealthStateDown, Since: since,
+		LastError: errors.New("i/o timeout")}
+	condition = getVCenterAvailableCondition(health)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, triggercsifullsyncv1alpha1.VCenterDownReason, condition.Reason)
+	assert.Equal(t, "vCenter vc0.example.com is down since 2026-01-02T03:04:05Z. Last error: i/o timeout; "+
+		"vCenter vc2.example.com is degraded since 2026-01-02T03:04:05Z. Last error: connection refused",
+		condition.Message)
+}
@@ -28,6 +28,7 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fvv1alpha1 "sigs.k8s.io/vsphere-csi-driver/v3/pkg/apis/filevolume/v1alpha1"
+	cnsvsphere "sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/cns-lib/vsphere"
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/utils"
 
 	"sigs.k8s.io/vsphere-csi-driver/v3/pkg/common/prometheus"
@@ -38,6 +39,10 @@ import (
 func csiGetVolumeHealthStatus(ctx context.Context, k8sclient clientset.Interface,
 	metadataSyncer *metadataSyncInformer) {
 	log := logger.GetLogger(ctx)
+	if vc := metadataSyncer.configInfo.Cfg.Global.VCenterIP; cnsvsphere.IsVCenterDown(vc) {
+		log.Infof("csiGetVolumeHealthStatus: skipped as vCenter %s is down", vc)
+		return
+	}
 	log.Infof("csiGetVolumeHealthStatus: start")
 
 	// Get K8s PVs in State "Bound".